@@ -1,13 +1,16 @@
 package config
 
 import (
-	"backend/models"
+	"backend/migrations"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
+	"github.com/glebarez/sqlite"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -21,6 +24,7 @@ func InitDB() {
 	}
 
 	connectDatabase()
+	migrateDatabase()
 }
 
 func TestInitDB() {
@@ -30,10 +34,62 @@ func TestInitDB() {
 		log.Fatal("Error loading .env.test file")
 	}
 
+	connectDatabase()
+	migrateDatabase()
+}
+
+// ConnectDB membuka koneksi database tanpa menjalankan migration,
+// dipakai oleh subcommand `migrate`
+func ConnectDB() {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
 	connectDatabase()
 }
 
 func connectDatabase() {
+	dialector, err := openDialector(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		log.Fatal("Failed to configure database:", err)
+	}
+
+	// Connect to the database
+	DB, err = gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// An in-memory SQLite database only lives as long as its connection,
+	// so every query has to share the same one
+	if dialector.Name() == "sqlite" && isMemoryDSN(os.Getenv("DB_NAME")) {
+		sqlDB, err := DB.DB()
+		if err != nil {
+			log.Fatal("Failed to connect to database:", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+}
+
+// migrateDatabase menerapkan migration yang belum dijalankan saat server start.
+// Set DB_AUTO_MIGRATE=false untuk menjalankannya manual lewat `migrate up`.
+func migrateDatabase() {
+	if strings.EqualFold(os.Getenv("DB_AUTO_MIGRATE"), "false") {
+		return
+	}
+
+	versions, err := migrations.Up(DB)
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+	for _, version := range versions {
+		log.Println("Applied migration", version)
+	}
+}
+
+// openDialector memilih driver database berdasarkan DB_DRIVER (default mysql)
+func openDialector(driver string) (gorm.Dialector, error) {
 	// Get database credentials from environment variables
 	dbUser := os.Getenv("DB_USER")
 	dbPassword := os.Getenv("DB_PASSWORD")
@@ -41,26 +97,43 @@ func connectDatabase() {
 	dbPort := os.Getenv("DB_PORT")
 	dbName := os.Getenv("DB_NAME")
 
-	// Build DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		dbUser, dbPassword, dbHost, dbPort, dbName)
-
-	// Connect to the database
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+	switch strings.ToLower(driver) {
+	case "", "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			dbUser, dbPassword, dbHost, dbPort, dbName)
+		return mysql.Open(dsn), nil
+	case "postgres", "postgresql":
+		sslMode := os.Getenv("DB_SSLMODE")
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+			dbHost, dbUser, dbPassword, dbName, dbPort, sslMode)
+		return postgres.Open(dsn), nil
+	case "sqlite", "sqlite3":
+		// DB_NAME is the database file, or ":memory:" for a throwaway database
+		if dbName == "" {
+			dbName = "tripwise.db"
+		}
+		return sqlite.Open(dbName), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
 	}
+}
+
+func isMemoryDSN(dsn string) bool {
+	return dsn == ":memory:" || strings.Contains(dsn, "mode=memory")
+}
 
-	// AutoMigrate models
-	DB.AutoMigrate(
-		&models.User{},
-		&models.Destination{},
-		&models.VideoContent{},
-		&models.Image{},
-		&models.City{},
-		&models.VideoContentView{},
-		&models.Route{},
-		&models.RouteDestination{},
-	)
+// MonthExpr mengembalikan ekspresi SQL nomor bulan (1-12) dari kolom tanggal
+// sesuai dialek database yang sedang dipakai
+func MonthExpr(column string) string {
+	switch DB.Dialector.Name() {
+	case "postgres":
+		return "CAST(EXTRACT(MONTH FROM " + column + ") AS INTEGER)"
+	case "sqlite":
+		return "CAST(strftime('%m', " + column + ") AS INTEGER)"
+	default:
+		return "MONTH(" + column + ")"
+	}
 }
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"backend/emissions"
+	"log"
+)
+
+// Emissions menghitung jejak karbon rute. Default memakai faktor bawaan.
+var Emissions = emissions.New()
+
+// InitEmissions membaca faktor emisi dari EMISSION_FACTOR_<MODE>
+func InitEmissions() {
+	calculator, err := emissions.FromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure emission factors:", err)
+	}
+	Emissions = calculator
+}
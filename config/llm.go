@@ -0,0 +1,26 @@
+package config
+
+import (
+	"backend/llm"
+	"log"
+)
+
+// LLM menjawab pesan chat asisten perjalanan. Default Mock sampai InitLLM dipanggil.
+var LLM llm.ChatProvider = llm.NewMock()
+
+// Embedder membuat vektor untuk pencarian katalog; nil berarti pencarian kata kunci saja
+var Embedder llm.Embedder
+
+// InitLLM memilih provider dari LLM_PROVIDER dan embedder dari EMBEDDING_PROVIDER
+func InitLLM() {
+	provider, err := llm.FromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure LLM provider:", err)
+	}
+	embedder, err := llm.EmbedderFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure embeddings:", err)
+	}
+	LLM = provider
+	Embedder = embedder
+}
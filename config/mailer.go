@@ -0,0 +1,19 @@
+package config
+
+import (
+	"backend/mailer"
+	"log"
+	"os"
+)
+
+// Mailer dipakai controller untuk mengirim email. Default menulis ke stdout.
+var Mailer mailer.Mailer = mailer.NewLogMailer(os.Stdout, "TripWise <no-reply@tripwise.my.id>")
+
+// InitMailer memilih implementasi Mailer dari MAIL_DRIVER
+func InitMailer() {
+	m, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure mailer:", err)
+	}
+	Mailer = m
+}
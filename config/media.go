@@ -0,0 +1,27 @@
+package config
+
+import (
+	"backend/media"
+	"backend/storage"
+	"log"
+)
+
+// Storage menyimpan file upload. Default disk lokal di ./assets yang disajikan di /assets.
+var Storage storage.Storage = storage.NewLocal("assets", "/assets")
+
+// Uploads memvalidasi lalu menyimpan upload gambar (gambar destinasi dan foto profil) ke Storage
+var Uploads = media.New(Storage)
+
+// InitUploads memilih Storage dari STORAGE_DRIVER dan membaca batas upload dari UPLOAD_MAX_BYTES
+func InitUploads() {
+	store, err := storage.FromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure storage:", err)
+	}
+	uploader, err := media.FromEnv(store)
+	if err != nil {
+		log.Fatal("Failed to configure uploads:", err)
+	}
+	Storage = store
+	Uploads = uploader
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"backend/config"
+	"backend/helper"
 	"backend/models"
 	"encoding/json"
 	"net/http"
@@ -10,16 +11,18 @@ import (
 )
 
 type CityInput struct {
-	Name string `json:"name"`
+	Name      string   `json:"name"`
+	Latitude  *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
 }
 
 // CreateCity godoc
 // @Summary Create a new city
-// @Description Create a new city in the database
+// @Description Create a new city in the database, optionally with its coordinates
 // @Tags Cities
 // @Accept  json
 // @Produce  json
-// @Param   city  body     CityInput  true  "City Name"
+// @Param   city  body     CityInput  true  "City name and coordinates"
 // @Success 200    {object} map[string]interface{}
 // @Failure 400    {object} map[string]interface{}
 // @Failure 409    {object} map[string]interface{}
@@ -38,6 +41,11 @@ func CreateCity(c echo.Context) error {
 	if input.Name == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "City name is required"})
 	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
 
 	// Cek apakah kota sudah ada
 	var existingCity models.City
@@ -46,7 +54,7 @@ func CreateCity(c echo.Context) error {
 	}
 
 	// Simpan kota baru ke database
-	city := models.City{Name: input.Name}
+	city := models.City{Name: input.Name, Latitude: input.Latitude, Longitude: input.Longitude}
 	if err := config.DB.Create(&city).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create city"})
 	}
@@ -57,19 +65,90 @@ func CreateCity(c echo.Context) error {
 	})
 }
 
+// UpdateCity godoc
+// @Summary Update a city
+// @Description Update a city's name and coordinates
+// @Tags Cities
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param   id    path     int        true  "City ID"
+// @Param   city  body     CityInput  true  "City name and coordinates"
+// @Success 200    {object} map[string]interface{}
+// @Failure 400    {object} map[string]interface{}
+// @Failure 404    {object} map[string]interface{}
+// @Failure 409    {object} map[string]interface{}
+// @Failure 500    {object} map[string]interface{}
+// @Router /city/{id} [put]
+func UpdateCity(c echo.Context) error {
+	var city models.City
+	if err := config.DB.First(&city, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "City not found"})
+	}
+
+	var input CityInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+
+	// Validasi input
+	if input.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "City name is required"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	// Nama kota harus tetap unik
+	var existingCity models.City
+	if err := config.DB.Where("name = ? AND id != ?", input.Name, city.ID).First(&existingCity).Error; err == nil {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "City already exists"})
+	}
+
+	city.Name = input.Name
+	city.Latitude = input.Latitude
+	city.Longitude = input.Longitude
+	if err := config.DB.Save(&city).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update city"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "City updated successfully",
+		"city":    city,
+	})
+}
+
 // GetCity godoc
 // @Summary Get all cities
-// @Description Retrieve a list of all cities from the database
+// @Description Retrieve a list of cities from the database, one page at a time
 // @Tags Cities
 // @Accept  json
 // @Produce  json
+// @Param   name    query string false "Filter by city name"
+// @Param   page    query int    false "Page number (default 1)"
+// @Param   limit   query int    false "Page size (default 100, max 500)"
+// @Param   cursor  query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
 // @Success 200 {object} map[string]interface{}()
+// @Failure 400 {object} map[string]interface{}()
 // @Failure 500 {object} map[string]interface{}()
 // @Router /city [get]
 func GetCity(c echo.Context) error {
-	// Mendapatkan semua data kota dari database
+	pageQuery, err := helper.ParsePageQuery(c, 100, 500)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	query := config.DB.Model(&models.City{})
+	if name := c.QueryParam("name"); name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+
+	// Mendapatkan data kota dari database per halaman
 	var cities []models.City
-	if err := config.DB.Find(&cities).Error; err != nil {
+	meta, err := helper.Paginate(query, pageQuery, "id", helper.Sort{}, &cities)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch cities"})
 	}
 
@@ -77,5 +156,6 @@ func GetCity(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "City fetched successfully",
 		"cities":  cities,
+		"meta":    meta,
 	})
 }
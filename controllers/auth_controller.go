@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/middlewares"
+	"backend/models"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// issueTokens membuat access token dan refresh token baru. Jika sessionID kosong,
+// sesi login baru dibuat; jika tidak, token baru melanjutkan sesi yang sama.
+func issueTokens(db *gorm.DB, user models.User, sessionID string) (string, string, error) {
+	if sessionID == "" {
+		sid, err := helper.RandomToken(16)
+		if err != nil {
+			return "", "", err
+		}
+		sessionID = sid
+	}
+
+	refreshToken, err := helper.RandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	record := models.RefreshToken{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		TokenHash: helper.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(helper.RefreshTokenTTL()),
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := helper.GenerateJWT(user.ID, user.Username, user.Role, sessionID, user.IsEmailVerified())
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeSession mencabut semua refresh token dalam satu sesi dan memasukkan
+// session id ke daftar revocation agar access token sesi itu ikut ditolak
+func revokeSession(db *gorm.DB, userID uint, sessionID string) error {
+	now := time.Now()
+
+	if err := db.Model(&models.RefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	revoked := models.RevokedToken{
+		TokenID:   sessionID,
+		UserID:    userID,
+		ExpiresAt: now.Add(helper.AccessTokenTTL()),
+	}
+	return db.Where(models.RevokedToken{TokenID: sessionID}).
+		Assign(models.RevokedToken{ExpiresAt: revoked.ExpiresAt}).
+		FirstOrCreate(&revoked).Error
+}
+
+// revokeAllSessions mencabut setiap sesi milik user dan mengembalikan jumlahnya
+func revokeAllSessions(db *gorm.DB, userID uint) (int, error) {
+	// Sesi yang refresh token-nya masih bisa menghasilkan access token yang berlaku
+	var sessionIDs []string
+	err := db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now().Add(-helper.AccessTokenTTL())).
+		Distinct().
+		Pluck("session_id", &sessionIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, sessionID := range sessionIDs {
+			if err := revokeSession(tx, userID, sessionID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(sessionIDs), nil
+}
+
+// RefreshTokenHandler godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated and the old one can no longer be used.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param input body RefreshTokenInput true "Refresh token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /token/refresh [post]
+func RefreshTokenHandler(c echo.Context) error {
+	var input RefreshTokenInput
+
+	// Bind input
+	if err := c.Bind(&input); err != nil {
+		response := helper.APIResponse("Invalid request", http.StatusBadRequest, "error", nil)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	// Validasi input
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	var stored models.RefreshToken
+	if err := config.DB.First(&stored, "token_hash = ?", helper.HashToken(input.RefreshToken)).Error; err != nil {
+		response := helper.APIResponse("Invalid refresh token", http.StatusUnauthorized, "error", nil)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+
+	// Refresh token yang sudah dirotasi dipakai lagi: anggap sesi bocor dan cabut semuanya
+	if stored.RevokedAt != nil {
+		revokeSession(config.DB, stored.UserID, stored.SessionID)
+		response := helper.APIResponse("Refresh token has been revoked", http.StatusUnauthorized, "error", nil)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		response := helper.APIResponse("Refresh token expired", http.StatusUnauthorized, "error", nil)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, stored.UserID).Error; err != nil {
+		response := helper.APIResponse("User not found", http.StatusUnauthorized, "error", nil)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+
+	var accessToken, refreshToken string
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Update bersyarat supaya dua request refresh bersamaan tidak sama-sama berhasil
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", stored.ID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var err error
+		accessToken, refreshToken, err = issueTokens(tx, user, stored.SessionID)
+		return err
+	})
+	if err == gorm.ErrRecordNotFound {
+		response := helper.APIResponse("Refresh token has been revoked", http.StatusUnauthorized, "error", nil)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+	if err != nil {
+		response := helper.APIResponse("Failed to generate token", http.StatusInternalServerError, "error", nil)
+		return c.JSON(http.StatusInternalServerError, response)
+	}
+
+	data := map[string]interface{}{
+		"token":         accessToken,
+		"refresh_token": refreshToken,
+		"expires_in":    int(helper.AccessTokenTTL().Seconds()),
+	}
+
+	response := helper.APIResponse("Token refreshed", http.StatusOK, "success", data)
+	return c.JSON(http.StatusOK, response)
+}
+
+// LogoutHandler godoc
+// @Summary Log out a user
+// @Description Revoke the current session so its access and refresh tokens can no longer be used
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /logout [get]
+func LogoutHandler(c echo.Context) error {
+	user, _ := middlewares.CurrentUser(c)
+
+	if err := revokeSession(config.DB, user.ID, user.SessionID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to logout",
+		})
+	}
+
+	// Bersihkan entri revocation yang access token-nya sudah kedaluwarsa
+	config.DB.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{})
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Berhasil Logout",
+	})
+}
+
+// LogoutAllHandler godoc
+// @Summary Log out from all devices
+// @Description Revoke every active session of the current user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /logout/all [post]
+func LogoutAllHandler(c echo.Context) error {
+	user, _ := middlewares.CurrentUser(c)
+
+	count, err := revokeAllSessions(config.DB, user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to logout from all devices",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "Berhasil Logout dari semua perangkat",
+		"sessions": count,
+	})
+}
@@ -1,42 +1,627 @@
 package controllers
 
 import (
+	"backend/config"
 	"backend/helper"
+	"backend/llm"
+	"backend/middlewares"
+	"backend/models"
+	"backend/request"
+	"backend/response"
+	"backend/retrieval"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
-type Input struct {
-	Message string `json:"message"`
+// ragLimit adalah jumlah maksimal destinasi/kota dari katalog yang disisipkan ke prompt
+// (RAG_LIMIT, default 5; 0 mematikan pencarian katalog)
+func ragLimit() int {
+	if n, err := strconv.Atoi(os.Getenv("RAG_LIMIT")); err == nil && n >= 0 {
+		return n
+	}
+	return 5
+}
+
+// ragMinSimilarity adalah kemiripan minimal hasil pencarian embedding (RAG_MIN_SIMILARITY, default 0.3)
+func ragMinSimilarity() float64 {
+	if f, err := strconv.ParseFloat(os.Getenv("RAG_MIN_SIMILARITY"), 64); err == nil {
+		return f
+	}
+	return 0.3
+}
+
+// chatHistoryLimit adalah jumlah pesan terakhir yang dikirim ke provider LLM sebagai konteks
+// (CHAT_HISTORY_LIMIT, default 20)
+func chatHistoryLimit() int {
+	if n, err := strconv.Atoi(os.Getenv("CHAT_HISTORY_LIMIT")); err == nil && n >= 0 {
+		return n
+	}
+	return 20
 }
 
 // ChatHandler godoc
-// @Summary Send a chat message to Gemini API
-// @Description Process a chat message and get a response from Gemini API
+// @Summary Send a chat message to the travel assistant
+// @Description Send a message to the travel assistant. Without conversation_id a new conversation is started; with it the previous turns are sent to the model as context. Destinations and cities from the catalogue that match the question are given to the model, and the ones the reply refers to are returned as citations. The model can call tools to search destinations, list cities, compute distances and draft a route; drafts are returned in route_drafts and are only saved as a route after POST /route/drafts/{id}/confirm.
 // @Tags Chat
 // @Accept json
 // @Produce json
-// @Param input body Input true "Chat Message"
+// @Security BearerAuth
+// @Param input body request.ChatInput true "Chat Message"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Failure 500 {object} map[string]string
 // @Router /chat [post]
 func ChatHandler(c echo.Context) error {
-	var input Input
+	var input request.ChatInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+	if ok, err := allowChat(c, currentUser.ID); !ok {
+		return err
+	}
+
+	turn, err := prepareChat(c, currentUser.ID, input)
+	if turn == nil {
+		return err
+	}
+
+	reply, err := runChat(c.Request().Context(), turn, currentUser.ID, nil, nil)
+	// Dicatat sebelum error dicek: runChat ikut mengembalikan token dari putaran yang gagal
+	recordChatUsage(currentUser.ID, reply.Usage)
+	if err != nil {
+		log.Println("Chat request failed:", err)
+		discardDrafts(turn)
+		status, message := chatErrorStatus(err)
+		return c.JSON(status, map[string]string{"message": message})
+	}
+
+	citations := citationsFor(reply.Text, turn.sources)
+	if err := saveChatTurn(turn, currentUser.ID, input.Message, reply.Text, citations); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to save conversation"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":         "Chat successfully sent!",
+		"data":            reply.Text,
+		"conversation_id": turn.conversation.ID,
+		"citations":       toCitationResponses(citations),
+		"route_drafts":    toRouteDrafts(turn.drafts),
+	})
+}
+
+// ChatStreamHandler godoc
+// @Summary Stream a chat response
+// @Description Same as POST /chat, but the reply is streamed as Server-Sent Events. Each "token" event carries {"text"} with the next piece of the reply and each "tool" event carries {"name"} of a tool the model is running; the final "done" event carries {"conversation_id", "usage", "citations", "route_drafts"}. If the model fails after streaming has started an "error" event is sent instead. Nothing is saved when the client disconnects before the reply is complete.
+// @Tags Chat
+// @Accept json
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param input body request.ChatInput true "Chat Message"
+// @Success 200 {string} string "Server-Sent Events stream"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /chat/stream [post]
+func ChatStreamHandler(c echo.Context) error {
+	var input request.ChatInput
 	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
 	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+	if ok, err := allowChat(c, currentUser.ID); !ok {
+		return err
+	}
+
+	turn, err := prepareChat(c, currentUser.ID, input)
+	if turn == nil {
+		return err
+	}
+
+	// Header SSE baru ditulis saat potongan pertama tiba, supaya kegagalan sebelum
+	// streaming dimulai masih bisa dijawab dengan JSON biasa
+	ctx := c.Request().Context()
+	started := false
+	send := func(event string, data interface{}) error {
+		if !started {
+			startEventStream(c)
+			started = true
+		}
+		return writeEvent(c, event, data)
+	}
+	reply, err := runChat(ctx, turn, currentUser.ID, func(text string) error {
+		return send("token", map[string]string{"text": text})
+	}, func(name string) error {
+		return send("tool", map[string]string{"name": name})
+	})
+	// Termasuk token dari jawaban yang terpotong karena client terputus
+	recordChatUsage(currentUser.ID, reply.Usage)
+
+	if ctx.Err() != nil {
+		// Client terputus: jawaban tidak lengkap sehingga tidak disimpan
+		discardDrafts(turn)
+		return nil
+	}
+	if err != nil {
+		log.Println("Chat stream failed:", err)
+		discardDrafts(turn)
+		status, message := chatErrorStatus(err)
+		if !started {
+			return c.JSON(status, map[string]string{"message": message})
+		}
+		return writeEvent(c, "error", map[string]string{"message": message})
+	}
+	if !started {
+		startEventStream(c)
+	}
+
+	citations := citationsFor(reply.Text, turn.sources)
+	if err := saveChatTurn(turn, currentUser.ID, input.Message, reply.Text, citations); err != nil {
+		return writeEvent(c, "error", map[string]string{"message": "Failed to save conversation"})
+	}
+
+	return writeEvent(c, "done", map[string]interface{}{
+		"conversation_id": turn.conversation.ID,
+		"usage":           reply.Usage,
+		"citations":       toCitationResponses(citations),
+		"route_drafts":    toRouteDrafts(turn.drafts),
+	})
+}
+
+func startEventStream(c echo.Context) {
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Matikan buffering reverse proxy (nginx) supaya token langsung sampai ke client
+	header.Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+}
+
+// writeEvent menulis satu event SSE lalu mengirimkannya ke client
+func writeEvent(c echo.Context, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	c.Response().Flush()
+	return nil
+}
+
+// GetConversations godoc
+// @Summary List chat conversations
+// @Description Fetch the authenticated user's conversations, most recently active first
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Invalid pagination; cursor mode is not supported"
+// @Failure 500 {object} map[string]string
+// @Router /chat/conversations [get]
+func GetConversations(c echo.Context) error {
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	currentUser, _ := middlewares.CurrentUser(c)
 
-	response, err := helper.CallGeminiAPI(input.Message)
+	var conversations []models.Conversation
+	query := config.DB.Model(&models.Conversation{}).Where("user_id = ?", currentUser.ID)
+	meta, err := helper.Paginate(query, pageQuery, "id", helper.Sort{Column: "updated_at", Desc: true}, &conversations)
+	if errors.Is(err, helper.ErrCursorUnsupported) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "cursor pagination is not supported for conversations"})
+	}
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch conversations"})
+	}
+
+	responses := make([]response.ConversationResponse, 0, len(conversations))
+	for _, conversation := range conversations {
+		responses = append(responses, toConversationResponse(conversation))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":       "Conversations fetched successfully",
+		"conversations": responses,
+		"meta":          meta,
+	})
+}
+
+// GetConversation godoc
+// @Summary Get a chat conversation
+// @Description Fetch one of your conversations with all its messages, oldest first, to resume it
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Success 200 {object} response.ConversationDetailResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /chat/conversations/{id} [get]
+func GetConversation(c echo.Context) error {
+	conversation, err := ownedConversation(c)
+	if conversation == nil {
+		return err
+	}
+
+	var messages []models.Message
+	if err := config.DB.Where("conversation_id = ?", conversation.ID).Order("id").Find(&messages).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch messages"})
+	}
+
+	var drafts []models.RouteDraft
+	messageIDs := config.DB.Model(&models.Message{}).Select("id").Where("conversation_id = ?", conversation.ID)
+	if err := config.DB.Where("message_id IN (?)", messageIDs).Order("id").Find(&drafts).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch messages"})
+	}
+	draftsByMessage := make(map[uint][]models.RouteDraft)
+	for _, draft := range drafts {
+		draftsByMessage[*draft.MessageID] = append(draftsByMessage[*draft.MessageID], draft)
+	}
+
+	detail := response.ConversationDetailResponse{
+		ConversationResponse: toConversationResponse(*conversation),
+		Messages:             make([]response.MessageResponse, 0, len(messages)),
+	}
+	for _, message := range messages {
+		detail.Messages = append(detail.Messages, response.MessageResponse{
+			ID:          message.ID,
+			Role:        message.Role,
+			Content:     message.Content,
+			Citations:   toCitationResponses(message.Citations),
+			RouteDrafts: toRouteDrafts(draftsByMessage[message.ID]),
+			CreatedAt:   message.CreatedAt,
 		})
 	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Chat successfully sent!",
-		"data":    response,
+		"message": "Conversation fetched successfully",
+		"data":    detail,
+	})
+}
+
+// DeleteConversation godoc
+// @Summary Delete a chat conversation
+// @Description Delete one of your conversations and all its messages. Pending route drafts proposed in it stay available under GET /route/drafts.
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /chat/conversations/{id} [delete]
+func DeleteConversation(c echo.Context) error {
+	conversation, err := ownedConversation(c)
+	if conversation == nil {
+		return err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		messageIDs := tx.Model(&models.Message{}).Select("id").Where("conversation_id = ?", conversation.ID)
+		if err := tx.Model(&models.RouteDraft{}).Where("message_id IN (?)", messageIDs).Update("message_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("conversation_id = ?", conversation.ID).Delete(&models.Message{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(conversation).Error
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete conversation"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Conversation deleted successfully"})
+}
+
+// chatTurn adalah satu pertanyaan yang siap dikirim ke provider LLM
+type chatTurn struct {
+	conversation models.Conversation
+	// messages berisi instruksi sistem dengan data katalog, riwayat terakhir, dan pesan baru
+	messages []llm.Message
+	// sources adalah data katalog yang disisipkan ke prompt atau ditemukan tool
+	sources []retrieval.Result
+	// drafts adalah draft rute yang dibuat tool selama turn ini
+	drafts []models.RouteDraft
+}
+
+// prepareChat mengambil percakapan yang dilanjutkan (jika ada), mencari data katalog
+// yang relevan, lalu menyusun pesan untuk provider LLM. Jika turn nil, response sudah
+// ditulis.
+func prepareChat(c echo.Context, userID uint, input request.ChatInput) (*chatTurn, error) {
+	turn := &chatTurn{}
+	var history []models.Message
+	if input.ConversationID != 0 {
+		err := config.DB.Where("id = ? AND user_id = ?", input.ConversationID, userID).First(&turn.conversation).Error
+		if err != nil {
+			return nil, c.JSON(http.StatusNotFound, map[string]string{"message": "Conversation not found"})
+		}
+		if history, err = recentMessages(turn.conversation.ID, chatHistoryLimit()); err != nil {
+			return nil, c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to load conversation"})
+		}
+	}
+
+	// Pertanyaan lanjutan seperti "yang paling murah?" dicari bersama pertanyaan sebelumnya
+	query := input.Message
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == models.MessageRoleUser {
+			query = history[i].Content + "\n" + query
+			break
+		}
+	}
+	sources, err := catalogRetriever(ragLimit()).Search(c.Request().Context(), query)
+	if err != nil {
+		// Tanpa data katalog asisten tetap bisa menjawab, hanya tanpa rujukan
+		log.Println("Catalog search failed:", err)
+	}
+	turn.sources = sources
+
+	prompt := groundingPrompt(sources)
+	if chatToolsEnabled() {
+		prompt += "\n\n" + toolPrompt
+	}
+	turn.messages = append(turn.messages, llm.Message{Role: llm.RoleSystem, Content: prompt})
+	turn.messages = append(turn.messages, toLLMMessages(history)...)
+	turn.messages = append(turn.messages, llm.Message{Role: llm.RoleUser, Content: input.Message})
+	return turn, nil
+}
+
+// catalogRetriever mencari sampai limit data katalog untuk chat
+func catalogRetriever(limit int) *retrieval.Retriever {
+	return &retrieval.Retriever{
+		DB:            config.DB,
+		Embedder:      config.Embedder,
+		Limit:         limit,
+		MinSimilarity: ragMinSimilarity(),
+	}
+}
+
+// saveChatTurn menyimpan pesan user dan jawaban model beserta rujukannya, lalu
+// menautkan draft rute turn ini ke jawaban tersebut; percakapan baru dibuat jika
+// turn.conversation belum tersimpan
+func saveChatTurn(turn *chatTurn, userID uint, message, reply string, citations []models.Citation) error {
+	conversation := &turn.conversation
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if conversation.ID == 0 {
+			*conversation = models.Conversation{UserID: userID, Title: conversationTitle(message)}
+			if err := tx.Create(conversation).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Model(conversation).Update("updated_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		messages := []models.Message{
+			{ConversationID: conversation.ID, Role: models.MessageRoleUser, Content: message},
+			{ConversationID: conversation.ID, Role: models.MessageRoleModel, Content: reply, Citations: citations},
+		}
+		if err := tx.Create(&messages).Error; err != nil {
+			return err
+		}
+
+		if len(turn.drafts) == 0 {
+			return nil
+		}
+		draftIDs := make([]uint, len(turn.drafts))
+		for i := range turn.drafts {
+			draftIDs[i] = turn.drafts[i].ID
+			turn.drafts[i].MessageID = &messages[1].ID
+		}
+		return tx.Model(&models.RouteDraft{}).Where("id IN ?", draftIDs).Update("message_id", messages[1].ID).Error
 	})
 }
+
+// discardDrafts menghapus draft rute dari turn yang gagal atau dibatalkan client
+func discardDrafts(turn *chatTurn) {
+	if len(turn.drafts) == 0 {
+		return
+	}
+	draftIDs := make([]uint, len(turn.drafts))
+	for i, draft := range turn.drafts {
+		draftIDs[i] = draft.ID
+	}
+	if err := config.DB.Delete(&models.RouteDraft{}, draftIDs).Error; err != nil {
+		log.Println("Failed to discard route drafts:", err)
+	}
+	turn.drafts = nil
+}
+
+// ownedConversation mengambil percakapan dari parameter :id milik user yang login.
+// Percakapan user lain dianggap tidak ada. Jika hasilnya nil, response sudah ditulis.
+func ownedConversation(c echo.Context) (*models.Conversation, error) {
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	var conversation models.Conversation
+	err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), currentUser.ID).First(&conversation).Error
+	if err != nil {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"message": "Conversation not found"})
+	}
+	return &conversation, nil
+}
+
+// recentMessages mengambil limit pesan terakhir percakapan, urut dari yang terlama
+func recentMessages(conversationID uint, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	if limit == 0 {
+		return messages, nil
+	}
+	err := config.DB.Where("conversation_id = ?", conversationID).Order("id DESC").Limit(limit).Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	// Gemini mengharuskan percakapan dimulai dari giliran user
+	for len(messages) > 0 && messages[0].Role != models.MessageRoleUser {
+		messages = messages[1:]
+	}
+	return messages, nil
+}
+
+// toLLMMessages memetakan pesan tersimpan ke format provider; role di database
+// sama dengan llm.RoleUser dan llm.RoleModel
+func toLLMMessages(messages []models.Message) []llm.Message {
+	converted := make([]llm.Message, 0, len(messages)+1)
+	for _, message := range messages {
+		converted = append(converted, llm.Message{Role: message.Role, Content: message.Content})
+	}
+	return converted
+}
+
+// groundingPrompt adalah instruksi sistem berisi data katalog hasil pencarian. Setiap
+// data diberi penanda (mis. [D12]) yang diminta dipakai model saat merujuknya.
+func groundingPrompt(sources []retrieval.Result) string {
+	var b strings.Builder
+	b.WriteString("Kamu adalah asisten perjalanan TripWise. Jawab dalam bahasa yang dipakai user. ")
+	b.WriteString("Saat merekomendasikan tempat, utamakan data katalog TripWise di bawah ini dan jangan mengarang nama tempat, harga, atau jam buka. ")
+	b.WriteString("Setiap kali menyebut tempat dari katalog, tulis penandanya dalam kurung siku tepat setelah namanya, misalnya \"Pantai Kuta [D12]\". ")
+	if len(sources) == 0 {
+		b.WriteString("\n\nKatalog TripWise tidak memuat tempat yang cocok dengan pertanyaan ini. Jika user meminta rekomendasi tempat, katakan bahwa TripWise belum punya datanya.")
+		return b.String()
+	}
+
+	b.WriteString("\n\nKatalog TripWise:")
+	for _, source := range sources {
+		fmt.Fprintf(&b, "\n[%s] %s", source.Marker(), source.Text)
+	}
+	return b.String()
+}
+
+// citationMarker mencocokkan penanda katalog di jawaban, mis. [D12] atau [C3]
+var citationMarker = regexp.MustCompile(`\[([DC]\d+)\]`)
+
+// citationsFor mengembalikan data katalog yang dirujuk jawaban sesuai urutan
+// kemunculannya. Jika model tidak menulis penanda, nama tempat yang disebut di
+// jawaban dipakai sebagai gantinya.
+func citationsFor(reply string, sources []retrieval.Result) []models.Citation {
+	byMarker := make(map[string]retrieval.Result, len(sources))
+	for _, source := range sources {
+		byMarker[source.Marker()] = source
+	}
+
+	var cited []retrieval.Result
+	seen := map[string]bool{}
+	for _, match := range citationMarker.FindAllStringSubmatch(reply, -1) {
+		source, ok := byMarker[match[1]]
+		if ok && !seen[match[1]] {
+			seen[match[1]] = true
+			cited = append(cited, source)
+		}
+	}
+	if len(cited) == 0 {
+		lowerReply := strings.ToLower(reply)
+		for _, source := range sources {
+			if strings.Contains(lowerReply, strings.ToLower(source.Name)) {
+				cited = append(cited, source)
+			}
+		}
+	}
+
+	citations := make([]models.Citation, 0, len(cited))
+	for _, source := range cited {
+		citations = append(citations, models.Citation{
+			Type:   source.Type,
+			ID:     source.ID,
+			Name:   source.Name,
+			Marker: source.Marker(),
+		})
+	}
+	return citations
+}
+
+func toCitationResponses(citations []models.Citation) []response.Citation {
+	responses := make([]response.Citation, 0, len(citations))
+	for _, citation := range citations {
+		responses = append(responses, response.Citation{
+			Type:   citation.Type,
+			ID:     citation.ID,
+			Name:   citation.Name,
+			Marker: citation.Marker,
+		})
+	}
+	return responses
+}
+
+// chatErrorStatus memetakan error provider LLM ke status HTTP dan pesan untuk client
+func chatErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, llm.ErrRateLimited):
+		return http.StatusTooManyRequests, "The assistant is busy, please try again in a moment"
+	case errors.Is(err, llm.ErrTimeout):
+		return http.StatusGatewayTimeout, "The assistant took too long to respond"
+	case errors.Is(err, llm.ErrRejected):
+		return http.StatusUnprocessableEntity, "The assistant could not process this message"
+	case errors.Is(err, llm.ErrUnavailable), errors.Is(err, llm.ErrEmptyResponse):
+		return http.StatusBadGateway, "Failed to get a response from the assistant"
+	default:
+		// ErrMisconfigured dan error lain adalah masalah server, bukan client
+		return http.StatusInternalServerError, "The assistant is not available right now"
+	}
+}
+
+// conversationTitle memakai awal pesan pertama sebagai judul percakapan
+func conversationTitle(message string) string {
+	title := strings.Join(strings.Fields(message), " ")
+	if runes := []rune(title); len(runes) > 60 {
+		title = strings.TrimSpace(string(runes[:60])) + "…"
+	}
+	return title
+}
+
+func toConversationResponse(conversation models.Conversation) response.ConversationResponse {
+	return response.ConversationResponse{
+		ID:        conversation.ID,
+		Title:     conversation.Title,
+		CreatedAt: conversation.CreatedAt,
+		UpdatedAt: conversation.UpdatedAt,
+	}
+}
+
+// deleteConversationsOf menghapus semua percakapan user beserta pesannya
+func deleteConversationsOf(tx *gorm.DB, userID uint) error {
+	conversationIDs := tx.Model(&models.Conversation{}).Select("id").Where("user_id = ?", userID)
+	if err := tx.Where("conversation_id IN (?)", conversationIDs).Delete(&models.Message{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", userID).Delete(&models.Conversation{}).Error
+}
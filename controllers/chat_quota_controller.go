@@ -0,0 +1,348 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/llm"
+	"backend/middlewares"
+	"backend/models"
+	"backend/request"
+	"backend/response"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// chatDailyTokenQuota adalah kuota token chat harian default per user
+// (CHAT_DAILY_TOKEN_QUOTA, default 100000; 0 tanpa batas)
+func chatDailyTokenQuota() int {
+	if n, err := strconv.Atoi(os.Getenv("CHAT_DAILY_TOKEN_QUOTA")); err == nil && n >= 0 {
+		return n
+	}
+	return 100000
+}
+
+// quotaDay adalah hari pemakaian (UTC) dalam format YYYY-MM-DD
+func quotaDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// nextQuotaReset adalah awal hari UTC berikutnya, saat pemakaian kembali ke nol
+func nextQuotaReset(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// GetMyChatQuota godoc
+// @Summary Get your chat quota
+// @Description Fetch your daily chat token quota and how much of it you have used today (UTC)
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.ChatQuota
+// @Failure 500 {object} map[string]string
+// @Router /chat/quota [get]
+func GetMyChatQuota(c echo.Context) error {
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	quota, err := chatQuotaOf(currentUser.ID, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch chat quota"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Chat quota fetched successfully",
+		"data":    quota,
+	})
+}
+
+// GetChatQuotas godoc
+// @Summary List chat usage
+// @Description Admin only. List the users who used the chat assistant on a day (UTC), heaviest users first, with their quotas
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Day as YYYY-MM-DD (default today, UTC)"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /chat/quotas [get]
+func GetChatQuotas(c echo.Context) error {
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+	now := time.Now()
+	day := quotaDay(now)
+	resetsAt := nextQuotaReset(now)
+	if raw := c.QueryParam("date"); raw != "" {
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "date must be formatted as YYYY-MM-DD"})
+		}
+		day = raw
+		resetsAt = nextQuotaReset(date)
+	}
+
+	var usages []models.ChatUsage
+	query := config.DB.Model(&models.ChatUsage{}).Where("day = ?", day)
+	meta, err := helper.Paginate(query, pageQuery, "id", helper.Sort{Column: "total_tokens", Desc: true}, &usages)
+	if errors.Is(err, helper.ErrCursorUnsupported) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "cursor pagination is not supported for chat usage"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch chat usage"})
+	}
+
+	userIDs := make([]uint, len(usages))
+	for i, usage := range usages {
+		userIDs[i] = usage.UserID
+	}
+	limits, err := chatQuotaOverrides(userIDs)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch chat usage"})
+	}
+
+	quotas := make([]response.ChatQuota, 0, len(usages))
+	for _, usage := range usages {
+		limit, custom := limits[usage.UserID]
+		if !custom {
+			limit = chatDailyTokenQuota()
+		}
+		quotas = append(quotas, toChatQuota(usage, limit, custom, resetsAt))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Chat usage fetched successfully",
+		"data":    quotas,
+		"meta":    meta,
+	})
+}
+
+// GetChatQuota godoc
+// @Summary Get a user's chat quota
+// @Description Admin only. Fetch a user's daily chat token quota and today's usage (UTC)
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} response.ChatQuota
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /chat/quotas/{id} [get]
+func GetChatQuota(c echo.Context) error {
+	user, err := quotaUser(c)
+	if user == nil {
+		return err
+	}
+	return respondChatQuota(c, user.ID, "Chat quota fetched successfully")
+}
+
+// UpdateChatQuota godoc
+// @Summary Set a user's chat quota
+// @Description Admin only. Override the default daily chat token quota (CHAT_DAILY_TOKEN_QUOTA) for a user; 0 means unlimited. Takes effect immediately for today's usage.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param input body request.ChatQuotaInput true "Daily token limit"
+// @Success 200 {object} response.ChatQuota
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /chat/quotas/{id} [put]
+func UpdateChatQuota(c echo.Context) error {
+	user, err := quotaUser(c)
+	if user == nil {
+		return err
+	}
+
+	var input request.ChatQuotaInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	quota := models.ChatQuota{UserID: user.ID, DailyTokenLimit: *input.DailyTokenLimit}
+	err = config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"daily_token_limit", "updated_at"}),
+	}).Create(&quota).Error
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update chat quota"})
+	}
+	return respondChatQuota(c, user.ID, "Chat quota updated successfully")
+}
+
+// ResetChatQuota godoc
+// @Summary Reset a user's chat quota to the default
+// @Description Admin only. Remove a user's quota override so the default daily chat token quota applies again. Today's usage is kept.
+// @Tags Chat
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} response.ChatQuota
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /chat/quotas/{id} [delete]
+func ResetChatQuota(c echo.Context) error {
+	user, err := quotaUser(c)
+	if user == nil {
+		return err
+	}
+
+	if err := config.DB.Where("user_id = ?", user.ID).Delete(&models.ChatQuota{}).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to reset chat quota"})
+	}
+	return respondChatQuota(c, user.ID, "Chat quota reset successfully")
+}
+
+// quotaUser mengambil user dari parameter :id. Jika hasilnya nil, response sudah ditulis.
+func quotaUser(c echo.Context) (*models.User, error) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid user ID"})
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"message": "User not found"})
+	}
+	return &user, nil
+}
+
+func respondChatQuota(c echo.Context, userID uint, message string) error {
+	quota, err := chatQuotaOf(userID, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch chat quota"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": message,
+		"data":    quota,
+	})
+}
+
+// chatQuotaOverrides mengambil kuota yang diatur admin untuk userIDs
+func chatQuotaOverrides(userIDs []uint) (map[uint]int, error) {
+	limits := make(map[uint]int)
+	if len(userIDs) == 0 {
+		return limits, nil
+	}
+	var quotas []models.ChatQuota
+	if err := config.DB.Where("user_id IN ?", userIDs).Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+	for _, quota := range quotas {
+		limits[quota.UserID] = quota.DailyTokenLimit
+	}
+	return limits, nil
+}
+
+// chatQuotaOf menghitung kuota dan pemakaian user pada hari now (UTC)
+func chatQuotaOf(userID uint, now time.Time) (response.ChatQuota, error) {
+	limits, err := chatQuotaOverrides([]uint{userID})
+	if err != nil {
+		return response.ChatQuota{}, err
+	}
+	limit, custom := limits[userID]
+	if !custom {
+		limit = chatDailyTokenQuota()
+	}
+
+	usage := models.ChatUsage{UserID: userID, Day: quotaDay(now)}
+	if err := config.DB.Where("user_id = ? AND day = ?", userID, usage.Day).Limit(1).Find(&usage).Error; err != nil {
+		return response.ChatQuota{}, err
+	}
+	return toChatQuota(usage, limit, custom, nextQuotaReset(now)), nil
+}
+
+func toChatQuota(usage models.ChatUsage, limit int, custom bool, resetsAt time.Time) response.ChatQuota {
+	quota := response.ChatQuota{
+		UserID:          usage.UserID,
+		Day:             usage.Day,
+		DailyTokenLimit: limit,
+		Custom:          custom,
+		UsedTokens:      usage.TotalTokens,
+		Requests:        usage.Requests,
+		ResetsAt:        resetsAt,
+	}
+	if limit > 0 {
+		remaining := max(limit-usage.TotalTokens, 0)
+		quota.RemainingTokens = &remaining
+	}
+	return quota
+}
+
+// allowChat menolak pesan chat dengan 429 jika kuota token harian user sudah habis.
+// Jika false, response sudah ditulis.
+func allowChat(c echo.Context, userID uint) (bool, error) {
+	now := time.Now()
+	quota, err := chatQuotaOf(userID, now)
+	if err != nil {
+		return false, c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to check chat quota"})
+	}
+	if quota.RemainingTokens != nil && *quota.RemainingTokens == 0 {
+		return false, middlewares.TooManyRequests(c, nextQuotaReset(now).Sub(now), "Daily chat token quota exceeded")
+	}
+	return true, nil
+}
+
+// recordChatUsage menambahkan token yang dipakai satu pesan chat ke pemakaian harian
+// user. Kuota dicek sebelum pesan dikirim, jadi pesan terakhir boleh melewati batas.
+func recordChatUsage(userID uint, usage llm.Usage) {
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	if usage.TotalTokens == 0 {
+		return
+	}
+
+	row := models.ChatUsage{
+		UserID:           userID,
+		Day:              quotaDay(time.Now()),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		Requests:         1,
+	}
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}},
+		// Kolom diberi nama tabel karena PostgreSQL menganggapnya ambigu dengan baris excluded
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"prompt_tokens":     gorm.Expr("chat_usages.prompt_tokens + ?", usage.PromptTokens),
+			"completion_tokens": gorm.Expr("chat_usages.completion_tokens + ?", usage.CompletionTokens),
+			"total_tokens":      gorm.Expr("chat_usages.total_tokens + ?", usage.TotalTokens),
+			"requests":          gorm.Expr("chat_usages.requests + 1"),
+			"updated_at":        time.Now(),
+		}),
+	}).Create(&row).Error
+	if err != nil {
+		log.Println("Failed to record chat usage:", err)
+	}
+}
+
+// deleteChatUsageOf menghapus pemakaian dan kuota chat user
+func deleteChatUsageOf(tx *gorm.DB, userID uint) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.ChatUsage{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", userID).Delete(&models.ChatQuota{}).Error
+}
@@ -0,0 +1,358 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/emissions"
+	"backend/helper"
+	"backend/llm"
+	"backend/models"
+	"backend/request"
+	"backend/retrieval"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxToolRounds membatasi berapa kali model boleh meminta tool untuk satu pertanyaan;
+// setelah itu model diminta menjawab tanpa tool
+const maxToolRounds = 5
+
+// chatToolsEnabled menentukan apakah asisten boleh memanggil tool (CHAT_TOOLS, default
+// true). Matikan untuk model OpenAI-compatible yang tidak mendukung tool calling.
+func chatToolsEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("CHAT_TOOLS"))
+	return err != nil || enabled
+}
+
+// toolPrompt menjelaskan kapan asisten memakai tool; ditambahkan ke instruksi sistem
+const toolPrompt = "Kamu bisa memanggil tool untuk mencari destinasi (search_destinations), melihat daftar kota (list_cities), " +
+	"menghitung jarak antar kota (calculate_distance), dan menyusun draft rute untuk user (draft_route). " +
+	"Jika user meminta rencana perjalanan, cari ID destinasinya lalu panggil draft_route. " +
+	"Draft belum tersimpan sebagai rute sampai user menekan tombol konfirmasi, jadi jangan mengatakan rutenya sudah disimpan."
+
+// chatTool adalah operasi aplikasi yang bisa dipanggil model. Error *echo.HTTPError
+// diteruskan ke model sebagai pesan supaya model bisa memperbaiki argumennya.
+type chatTool struct {
+	llm.Tool
+	run func(ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+// chatTools adalah tool yang dijalankan atas nama user yang login selama satu turn.
+// Hasil pencarian ditambahkan ke turn.sources (untuk citation) dan draft rute ke
+// turn.drafts.
+type chatTools struct {
+	turn   *chatTurn
+	userID uint
+	tools  []chatTool
+}
+
+func newChatTools(turn *chatTurn, userID uint) *chatTools {
+	transportModes, _ := json.Marshal(emissions.Modes)
+	t := &chatTools{turn: turn, userID: userID}
+	t.tools = []chatTool{
+		{
+			Tool: llm.Tool{
+				Name:        "search_destinations",
+				Description: "Cari destinasi dan kota di katalog TripWise. Hasilnya memuat ID destinasi untuk draft_route dan penanda untuk dirujuk di jawaban.",
+				Parameters: json.RawMessage(`{"type":"object","properties":{` +
+					`"query":{"type":"string","description":"Kata kunci, mis. \"pantai di Bali\""},` +
+					`"limit":{"type":"integer","description":"Jumlah hasil maksimal (1-10, default 5)"}},` +
+					`"required":["query"]}`),
+			},
+			run: t.searchDestinations,
+		},
+		{
+			Tool: llm.Tool{
+				Name:        "list_cities",
+				Description: "Daftar kota di TripWise. Nama kota dipakai sebagai originCityName dan destinationCityName di draft_route.",
+				Parameters: json.RawMessage(`{"type":"object","properties":{` +
+					`"query":{"type":"string","description":"Bagian nama kota (opsional)"}}}`),
+			},
+			run: t.listCities,
+		},
+		{
+			Tool: llm.Tool{
+				Name:        "calculate_distance",
+				Description: "Hitung jarak garis lurus (km) antara dua kota.",
+				Parameters: json.RawMessage(`{"type":"object","properties":{` +
+					`"origin_city":{"type":"string"},` +
+					`"destination_city":{"type":"string"}},` +
+					`"required":["origin_city","destination_city"]}`),
+			},
+			run: t.calculateDistance,
+		},
+		{
+			Tool: llm.Tool{
+				Name: "draft_route",
+				Description: "Susun draft rute untuk user: destinasi diurutkan menjadi rute terpendek dan jejak karbonnya dihitung. " +
+					"Draft tidak disimpan sebagai rute sampai user mengonfirmasinya.",
+				Parameters: json.RawMessage(`{"type":"object","properties":{` +
+					`"originCityName":{"type":"string","description":"Kota awal"},` +
+					`"destinationCityName":{"type":"string","description":"Kota akhir; kosongkan jika rute berakhir di destinasi terakhir"},` +
+					`"destinations":{"type":"array","items":{"type":"integer"},"description":"ID destinasi dari search_destinations"},` +
+					`"transportMode":{"type":"string","enum":` + string(transportModes) + `},` +
+					`"travellers":{"type":"integer","description":"Jumlah orang (default 1)"}},` +
+					`"required":["originCityName","destinations"]}`),
+			},
+			run: t.draftRoute,
+		},
+	}
+	return t
+}
+
+// definitions mengembalikan deklarasi tool untuk provider; nil jika tool dimatikan
+func (t *chatTools) definitions() []llm.Tool {
+	if t == nil {
+		return nil
+	}
+	definitions := make([]llm.Tool, len(t.tools))
+	for i, tool := range t.tools {
+		definitions[i] = tool.Tool
+	}
+	return definitions
+}
+
+// call menjalankan satu tool call dan mengembalikan hasilnya sebagai pesan RoleTool.
+// Kegagalan dilaporkan ke model sebagai {"error": ...}, bukan menggagalkan chat.
+func (t *chatTools) call(ctx context.Context, call llm.ToolCall) llm.Message {
+	message := llm.Message{Role: llm.RoleTool, ToolCallID: call.ID, ToolName: call.Name}
+
+	var result interface{}
+	var err error = echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unknown tool %q", call.Name))
+	for _, tool := range t.tools {
+		if tool.Name == call.Name {
+			result, err = tool.run(ctx, call.Arguments)
+			break
+		}
+	}
+	if err != nil {
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			result = map[string]interface{}{"error": he.Message}
+		} else {
+			log.Printf("Chat tool %s failed: %v", call.Name, err)
+			result = map[string]string{"error": "Internal error"}
+		}
+	}
+
+	content, _ := json.Marshal(result)
+	message.Content = string(content)
+	return message
+}
+
+// decodeToolArguments membaca argumen tool ke input
+func decodeToolArguments(args json.RawMessage, input interface{}) error {
+	if err := json.Unmarshal(args, input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid arguments: "+err.Error())
+	}
+	return nil
+}
+
+func (t *chatTools) searchDestinations(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var input struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeToolArguments(args, &input); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(input.Query) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "query is required")
+	}
+	if input.Limit <= 0 || input.Limit > 10 {
+		input.Limit = 5
+	}
+
+	retriever := catalogRetriever(input.Limit)
+	sources, err := retriever.Search(ctx, input.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0, len(sources))
+	for _, source := range sources {
+		t.addSource(source)
+		results = append(results, map[string]interface{}{
+			"marker":  source.Marker(),
+			"type":    source.Type,
+			"id":      source.ID,
+			"name":    source.Name,
+			"summary": source.Text,
+		})
+	}
+	return map[string]interface{}{"results": results}, nil
+}
+
+// addSource menambahkan hasil pencarian tool ke sumber citation tanpa duplikat
+func (t *chatTools) addSource(source retrieval.Result) {
+	for _, existing := range t.turn.sources {
+		if existing.Marker() == source.Marker() {
+			return
+		}
+	}
+	t.turn.sources = append(t.turn.sources, source)
+}
+
+func (t *chatTools) listCities(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var input struct {
+		Query string `json:"query"`
+	}
+	if err := decodeToolArguments(args, &input); err != nil {
+		return nil, err
+	}
+
+	query := config.DB.WithContext(ctx).Order("name").Limit(100)
+	if input.Query != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(input.Query)+"%")
+	}
+	var cities []models.City
+	if err := query.Find(&cities).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0, len(cities))
+	for _, city := range cities {
+		_, _, hasCoordinates := city.Coordinates()
+		results = append(results, map[string]interface{}{
+			"id":              city.ID,
+			"name":            city.Name,
+			"has_coordinates": hasCoordinates,
+		})
+	}
+	return map[string]interface{}{"cities": results}, nil
+}
+
+func (t *chatTools) calculateDistance(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var input struct {
+		OriginCity      string `json:"origin_city"`
+		DestinationCity string `json:"destination_city"`
+	}
+	if err := decodeToolArguments(args, &input); err != nil {
+		return nil, err
+	}
+
+	var cities [2]models.City
+	for i, name := range []string{input.OriginCity, input.DestinationCity} {
+		err := config.DB.WithContext(ctx).Where("LOWER(name) = ?", strings.ToLower(strings.TrimSpace(name))).First(&cities[i]).Error
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("City %q not found", name))
+		}
+		if _, _, ok := cities[i].Coordinates(); !ok {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("City %q has no coordinates", cities[i].Name))
+		}
+	}
+
+	return map[string]interface{}{
+		"origin_city":      cities[0].Name,
+		"destination_city": cities[1].Name,
+		"distance_km":      roundKm(calculateDistance(cities[0], cities[1])),
+	}, nil
+}
+
+// draftRoute menyusun rute seperti POST /route tetapi menyimpannya sebagai draft
+func (t *chatTools) draftRoute(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var input request.CreateRouteInput
+	if err := decodeToolArguments(args, &input); err != nil {
+		return nil, err
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, strings.Join(helper.FormatValidationError(err), "; "))
+	}
+	if len(input.Destinations) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "destinations must not be empty")
+	}
+
+	route, plan, err := newRoute(t.userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := models.RouteDraft{
+		UserID:              t.userID,
+		OriginCityName:      route.OriginCityName,
+		DestinationCityName: route.DestinationCityName,
+		OpenEnded:           route.OpenEnded,
+		Distance:            route.Distance,
+		TransportMode:       route.TransportMode,
+		Travellers:          route.Travellers,
+		CarbonKg:            route.CarbonKg,
+		Status:              models.RouteDraftPending,
+	}
+	for _, stop := range plan.Stops {
+		draft.Stops = append(draft.Stops, models.RouteDraftStop{
+			Sequence:      stop.Sequence,
+			DestinationID: stop.Destination.ID,
+			Name:          stop.Destination.Name,
+			LegDistance:   stop.LegDistance,
+		})
+	}
+	if err := config.DB.WithContext(ctx).Create(&draft).Error; err != nil {
+		return nil, err
+	}
+	t.turn.drafts = append(t.turn.drafts, draft)
+
+	return map[string]interface{}{
+		"draft_id": draft.ID,
+		"draft":    draft,
+		"note":     "Draft belum disimpan sebagai rute. User harus mengonfirmasinya terlebih dahulu.",
+	}, nil
+}
+
+// runChat mengirim turn ke provider dan menjalankan tool yang diminta model sampai
+// model menjawab dengan teks. Jika onText diisi jawaban di-stream, dan onTool dipanggil
+// sebelum setiap tool dijalankan. Teks dari semua putaran digabung dan usage dijumlahkan.
+func runChat(ctx context.Context, turn *chatTurn, userID uint, onText func(text string) error, onTool func(name string) error) (llm.Reply, error) {
+	var tools *chatTools
+	if chatToolsEnabled() {
+		tools = newChatTools(turn, userID)
+	}
+
+	var result llm.Reply
+	var text strings.Builder
+	for round := 0; ; round++ {
+		definitions := tools.definitions()
+		if round == maxToolRounds {
+			definitions = nil
+		}
+
+		var reply llm.Reply
+		var err error
+		if onText != nil {
+			reply, err = config.LLM.Stream(ctx, turn.messages, onText, definitions...)
+		} else {
+			reply, err = config.LLM.Chat(ctx, turn.messages, definitions...)
+		}
+		// Token dari putaran yang gagal atau dibatalkan client tetap dihitung ke kuota
+		result.Usage = result.Usage.Add(reply.Usage)
+		if err != nil {
+			return result, err
+		}
+		text.WriteString(reply.Text)
+
+		if len(reply.ToolCalls) == 0 || len(definitions) == 0 {
+			break
+		}
+		turn.messages = append(turn.messages, llm.Message{Role: llm.RoleModel, Content: reply.Text, ToolCalls: reply.ToolCalls})
+		for _, call := range reply.ToolCalls {
+			if onTool != nil {
+				if err := onTool(call.Name); err != nil {
+					return result, err
+				}
+			}
+			turn.messages = append(turn.messages, tools.call(ctx, call))
+		}
+	}
+
+	if result.Text = text.String(); result.Text == "" {
+		return result, fmt.Errorf("chat: %w: no answer after tool calls", llm.ErrEmptyResponse)
+	}
+	return result, nil
+}
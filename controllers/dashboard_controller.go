@@ -10,16 +10,25 @@ import (
 )
 
 type MonthlyUserCount struct {
-	Month string
+	Month int
 	Count int
 }
 
+type FavoriteDestinationCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
 // GetDashboardDataHandler godoc
 // @Summary Fetch dashboard data summary
-// @Description Retrieve a summary of users, destinations, video content, and destination categories for the dashboard
+// @Description Admin only. Retrieve a summary of users, destinations, video content, favorites, and destination categories for the dashboard
 // @Tags Dashboard
 // @Produce json
+// @Security BearerAuth
 // @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /dashboard/count-data [get]
 func GetDashboardDataHandler(c echo.Context) error {
@@ -34,6 +43,19 @@ func GetDashboardDataHandler(c echo.Context) error {
 	config.DB.Find(&destinations)
 	config.DB.Find(&videoContents)
 
+	// Count favorites and the most favorited destinations
+	var favoriteCount int64
+	config.DB.Model(&models.Favorite{}).Count(&favoriteCount)
+
+	topFavorites := []FavoriteDestinationCount{}
+	config.DB.Model(&models.Favorite{}).
+		Select("favorites.destination_id AS id, destinations.name AS name, COUNT(*) AS count").
+		Joins("JOIN destinations ON destinations.id = favorites.destination_id").
+		Group("favorites.destination_id, destinations.name").
+		Order("count DESC").
+		Limit(5).
+		Scan(&topFavorites)
+
 	// Count destinations by categories
 	config.DB.Model(&models.Destination{}).Where("category = ?", "Nature").Count(&natureCount)
 	config.DB.Model(&models.Destination{}).Where("category = ?", "Culture").Count(&cultureCount)
@@ -42,9 +64,11 @@ func GetDashboardDataHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Dashboard data fetched successfully",
 		"data": map[string]any{
-			"user":         len(users),
-			"destination":  len(destinations),
-			"videoContent": len(videoContents),
+			"user":                    len(users),
+			"destination":             len(destinations),
+			"videoContent":            len(videoContents),
+			"favorite":                favoriteCount,
+			"topFavoriteDestinations": topFavorites,
 			"destinationCategories": map[string]int64{
 				"Nature":     natureCount,
 				"Culture":    cultureCount,
@@ -56,19 +80,22 @@ func GetDashboardDataHandler(c echo.Context) error {
 
 // GetDashboardGraphicDataHandler godoc
 // @Summary Fetch monthly user registration data
-// @Description Retrieve the number of user registrations grouped by month
+// @Description Admin only. Retrieve the number of user registrations grouped by month
 // @Tags Dashboard
 // @Produce json
+// @Security BearerAuth
 // @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /dashboard/graphic [get]
 func GetDashboardGraphicDataHandler(c echo.Context) error {
 	var userMonthlyCounts []MonthlyUserCount
 
-	// Query for grouping user count by month with month abbreviation
+	// Query for grouping user count by month number, portable across database drivers
 	err := config.DB.Model(&models.User{}).
-		Select("DATE_FORMAT(created_at, '%b') AS month, COUNT(*) AS count").
-		Group("month").
+		Select(config.MonthExpr("created_at") + " AS month, COUNT(*) AS count").
+		Group(config.MonthExpr("created_at")).
 		Scan(&userMonthlyCounts).Error
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -88,7 +115,10 @@ func GetDashboardGraphicDataHandler(c echo.Context) error {
 
 	// Populate the map with data from the query
 	for _, userCount := range userMonthlyCounts {
-		monthlyUserData[userCount.Month] = userCount.Count
+		if userCount.Month < 1 || userCount.Month > len(months) {
+			continue
+		}
+		monthlyUserData[months[userCount.Month-1]] = userCount.Count
 	}
 
 	// Return response
@@ -2,12 +2,17 @@ package controllers
 
 import (
 	"backend/config"
+	"backend/emissions"
 	"backend/helper"
+	"backend/middlewares"
 	"backend/models"
 	"backend/request"
 	"backend/response"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -23,7 +28,7 @@ type CreateDestinationAssetsInput struct {
 
 // CreateDestination godoc
 // @Summary Create a new destination
-// @Description Create a new destination and associate it with a city, images, and video contents
+// @Description Create a new destination and associate it with a city, images, video contents, and sustainability attributes. The eco-score is computed from the sustainability attributes.
 // @Tags Destinations
 // @Accept json
 // @Produce json
@@ -39,6 +44,13 @@ func CreateDestination(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
 	}
 
+	// Validasi input
+	if err := helper.ValidateInput(jsonBody); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
 	// Cari City berdasarkan nama
 	var city models.City
 	if err := config.DB.Where("name = ?", jsonBody.City).First(&city).Error; err != nil {
@@ -50,6 +62,8 @@ func CreateDestination(c echo.Context) error {
 		Name:             jsonBody.Name,
 		CityID:           city.ID, // Gunakan ID dari City yang ditemukan
 		Position:         jsonBody.Position,
+		Latitude:         jsonBody.Latitude,
+		Longitude:        jsonBody.Longitude,
 		Address:          jsonBody.Address,
 		OperationalHours: jsonBody.OperationalHours,
 		TicketPrice:      jsonBody.TicketPrice,
@@ -57,6 +71,7 @@ func CreateDestination(c echo.Context) error {
 		Facilities:       jsonBody.Facilities,
 		Description:      jsonBody.Description,
 	}
+	applySustainability(&destination, jsonBody)
 
 	// Simpan destinasi ke database
 	if err := config.DB.Create(&destination).Error; err != nil {
@@ -85,7 +100,7 @@ func CreateDestination(c echo.Context) error {
 	}
 
 	// Muat ulang destinasi dengan properti City
-	if err := config.DB.Preload("City").Preload("Images").Preload("VideoContents").First(&destination, destination.ID).Error; err != nil {
+	if err := config.DB.Preload("City").Preload("Images.Variants").Preload("VideoContents").First(&destination, destination.ID).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch destination with related data"})
 	}
 
@@ -95,7 +110,7 @@ func CreateDestination(c echo.Context) error {
 
 // UpdateDestination godoc
 // @Summary Update a destination
-// @Description Update destination details including city, images, and video contents
+// @Description Update destination details including city, images, video contents, and sustainability attributes. The eco-score is recomputed.
 // @Tags Destinations
 // @Accept json
 // @Produce json
@@ -120,6 +135,13 @@ func UpdateDestination(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
 	}
 
+	// Validasi input
+	if err := helper.ValidateInput(jsonBody); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
 	// Cari destinasi berdasarkan ID
 	var destination models.Destination
 	if err := config.DB.First(&destination, destinationID).Error; err != nil {
@@ -135,18 +157,21 @@ func UpdateDestination(c echo.Context) error {
 	// Perbarui data destinasi
 	destination.Name = jsonBody.Name
 	destination.CityID = city.ID // Gunakan CityID yang benar
+	destination.Latitude = jsonBody.Latitude
+	destination.Longitude = jsonBody.Longitude
 	destination.Address = jsonBody.Address
 	destination.OperationalHours = jsonBody.OperationalHours
 	destination.TicketPrice = jsonBody.TicketPrice
 	destination.Category = jsonBody.Category
 	destination.Facilities = jsonBody.Facilities
+	applySustainability(&destination, jsonBody)
 
 	// Simpan perubahan ke database
 	if err := config.DB.Save(&destination).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Gagal memperbarui destinasi"})
 	}
 
-	config.DB.Where("destination_id = ?", destination.ID).Delete(&destination.Images)
+	deleteDestinationImages(config.DB, destination.ID)
 	config.DB.Where("destination_id = ?", destination.ID).Delete(&destination.VideoContents)
 
 	for i := 0; i < len(jsonBody.Image); i++ {
@@ -197,7 +222,7 @@ func DeleteDestination(c echo.Context) error {
 
 	// Find the destination by ID, including its related entities
 	var destination models.Destination
-	if err := config.DB.Preload("Images").Preload("VideoContents").First(&destination, destinationID).Error; err != nil {
+	if err := config.DB.Preload("Images.Variants").Preload("VideoContents").First(&destination, destinationID).Error; err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"message": "Destination not found"})
 	}
 
@@ -205,7 +230,7 @@ func DeleteDestination(c echo.Context) error {
 	tx := config.DB.Begin()
 
 	// Delete related Images
-	if err := tx.Where("destination_id = ?", destination.ID).Delete(&destination.Images).Error; err != nil {
+	if err := deleteDestinationImages(tx, destination.ID); err != nil {
 		tx.Rollback()
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete related images"})
 	}
@@ -216,6 +241,24 @@ func DeleteDestination(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete related video contents"})
 	}
 
+	// Delete related reviews
+	if err := deleteReviewsOf(tx, "destination_id", destination.ID); err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete related reviews"})
+	}
+
+	// Delete related video views
+	if err := tx.Where("destination_id = ?", destination.ID).Delete(&models.VideoContentView{}).Error; err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete related video views"})
+	}
+
+	// Delete related favorites
+	if err := tx.Where("destination_id = ?", destination.ID).Delete(&models.Favorite{}).Error; err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete related favorites"})
+	}
+
 	// Delete the destination
 	if err := tx.Delete(&destination).Error; err != nil {
 		tx.Rollback()
@@ -230,15 +273,23 @@ func DeleteDestination(c echo.Context) error {
 
 // GetAllDestinations godoc
 // @Summary Get all destinations
-// @Description Fetch a list of destinations with filters like name, city, category, and sort order
+// @Description Fetch a list of destinations with filters like name, city, category, eco-score, rating, and sort order
 // @Tags Destinations
 // @Accept json
 // @Produce json
 // @Param name query string false "Filter by destination name"
 // @Param city query string false "Filter by city name"
 // @Param category query string false "Filter by category"
-// @Param sort query string false "Sort order (newest, oldest)"
+// @Param min_eco_score query int false "Only destinations with at least this eco-score (0-100)"
+// @Param certified query bool false "Only destinations with at least one eco-certification"
+// @Param public_transport query bool false "Only destinations reachable by public transport"
+// @Param min_rating query number false "Only reviewed destinations with at least this average rating (0-5)"
+// @Param sort query string false "Sort order (newest, oldest, eco_score, rating); eco_score and rating sort highest first and do not support cursor mode"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /destinations [get]
 func GetAllDestinations(c echo.Context) error {
@@ -250,10 +301,12 @@ func GetAllDestinations(c echo.Context) error {
 	querySort := c.QueryParam("sort")
 	queryCategory := c.QueryParam("category")
 
-	query := config.DB.
-		Preload("City").
-		Preload("Images").
-		Preload("VideoContents")
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	query := config.DB.Model(&models.Destination{})
 
 	if queryName != "" {
 		query = query.Where("name LIKE ?", "%"+queryName+"%")
@@ -271,51 +324,147 @@ func GetAllDestinations(c echo.Context) error {
 		query = query.Where("category = ?", queryCategory)
 	}
 
-	if querySort != "" {
-		if querySort == "oldest" {
-			query = query.Order("created_at ASC")
-		} else {
-			query = query.Order("created_at DESC")
+	if raw := c.QueryParam("min_eco_score"); raw != "" {
+		minEcoScore, err := strconv.Atoi(raw)
+		if err != nil || minEcoScore < 0 || minEcoScore > 100 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "min_eco_score must be between 0 and 100"})
 		}
+		query = query.Where("eco_score >= ?", minEcoScore)
 	}
 
-	err := query.Find(&destinations).Error
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch destinations"})
+	if raw := c.QueryParam("min_rating"); raw != "" {
+		minRating, err := strconv.ParseFloat(raw, 64)
+		if err != nil || minRating < 0 || minRating > 5 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "min_rating must be between 0 and 5"})
+		}
+		query = query.Where("average_rating >= ? AND review_count > 0", minRating)
 	}
 
-	for _, dest := range destinations {
-		var facilitiesArray []string
-		if dest.Facilities != "" {
-			facilitiesArray = make([]string, 0)
-			for _, facility := range strings.Split(dest.Facilities, ",") {
-				facilitiesArray = append(facilitiesArray, strings.TrimSpace(facility))
-			}
-		}
+	if c.QueryParam("certified") == "true" {
+		query = query.Where("certifications <> ''")
+	}
+
+	if c.QueryParam("public_transport") == "true" {
+		query = query.Where("public_transport_accessible = ?", true)
+	}
 
-		destinationResponse := response.DestinationResponse{
-			ID:               dest.ID,
-			Name:             dest.Name,
-			City:             response.City{ID: dest.City.ID, Name: dest.City.Name},
-			Position:         dest.Position,
-			Address:          dest.Address,
-			OperationalHours: dest.OperationalHours,
-			TicketPrice:      dest.TicketPrice,
-			Category:         dest.Category,
-			Description:      dest.Description,
-			Facilities:       facilitiesArray,
-			CreatedAt:        dest.CreatedAt,
-			Images:           convertImagesToResponse(dest.Images),
-			VideoContents:    convertVideosToResponse(dest.VideoContents),
+	order := helper.Sort{}
+	switch querySort {
+	case "eco_score", "rating":
+		order = helper.Sort{Column: "eco_score", Desc: true}
+		if querySort == "rating" {
+			order.Column = "average_rating"
 		}
+	default:
+		if querySort != "" {
+			order.Desc = querySort != "oldest"
+			// Urutan id sama dengan urutan created_at, jadi mode cursor tetap bisa dipakai
+			if !pageQuery.CursorMode {
+				order.Column = "created_at"
+			}
+		}
+	}
 
-		destinationResponses = append(destinationResponses, destinationResponse)
+	meta, err := helper.Paginate(query, pageQuery, "destinations.id", order, &destinations, "City", "Images.Variants", "VideoContents")
+	if errors.Is(err, helper.ErrCursorUnsupported) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "cursor pagination is not supported with sort=" + querySort})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch destinations"})
 	}
 
+	destinationResponses = toDestinationResponses(c, destinations)
+
 	// Return the response with the destinations
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message":      "Destinations fetched successfully",
 		"destinations": destinationResponses,
+		"meta":         meta,
+	})
+}
+
+// GetNearbyDestinations godoc
+// @Summary Find destinations near a point
+// @Description Fetch destinations within radius_km of the given coordinates, sorted by distance (km)
+// @Tags Destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lat query number true "Latitude (-90 to 90)"
+// @Param lng query number true "Longitude (-180 to 180)"
+// @Param radius_km query number false "Search radius in km (default 10, max 200)"
+// @Param category query string false "Filter by category"
+// @Param limit query int false "Maximum results (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /destination/nearby [get]
+func GetNearbyDestinations(c echo.Context) error {
+	lat, latErr := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(c.QueryParam("lng"), 64)
+	if latErr != nil || lngErr != nil || !helper.ValidCoordinates(lat, lng) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "lat and lng must be valid coordinates"})
+	}
+
+	radiusKm := 10.0
+	if raw := c.QueryParam("radius_km"); raw != "" {
+		r, err := strconv.ParseFloat(raw, 64)
+		if err != nil || r <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "radius_km must be a positive number"})
+		}
+		radiusKm = math.Min(r, 200)
+	}
+
+	limit := 20
+	if raw := c.QueryParam("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "limit must be a positive number"})
+		}
+		limit = min(l, 100)
+	}
+
+	// Pre-filter kotak di database, lalu jarak sebenarnya dihitung dengan Haversine
+	minLat, maxLat, minLng, maxLng, wraps := helper.BoundingBox(lat, lng, radiusKm)
+	query := config.DB.
+		Preload("City").
+		Preload("Images.Variants").
+		Where("latitude IS NOT NULL AND longitude IS NOT NULL").
+		Where("latitude BETWEEN ? AND ?", minLat, maxLat)
+	if !wraps {
+		query = query.Where("longitude BETWEEN ? AND ?", minLng, maxLng)
+	}
+	if category := c.QueryParam("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var destinations []models.Destination
+	if err := query.Find(&destinations).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch destinations"})
+	}
+
+	destinationResponses := toDestinationResponses(c, destinations)
+	results := make([]response.NearbyDestination, 0, len(destinations))
+	for i, dest := range destinations {
+		destLat, destLng, _ := dest.Coordinates()
+		distance := helper.Haversine(lat, lng, destLat, destLng)
+		if distance > radiusKm {
+			continue
+		}
+		results = append(results, response.NearbyDestination{
+			DestinationResponse: destinationResponses[i],
+			DistanceKm:          roundKm(distance),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].DistanceKm < results[j].DistanceKm })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "Nearby destinations fetched successfully",
+		"destinations": results,
 	})
 }
 
@@ -339,7 +488,7 @@ func GetDetailDestination(c echo.Context) error {
 	// Fetch the destination details with related data
 	err := config.DB.
 		Preload("City").
-		Preload("Images").
+		Preload("Images.Variants").
 		Preload("VideoContents").
 		First(&destination, "id = ?", id).Error
 
@@ -350,32 +499,10 @@ func GetDetailDestination(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch destination details"})
 	}
 
-	// Convert Facilities field to an array of strings and trim spaces
-	var facilitiesArray []string
-	if destination.Facilities != "" {
-		// Split the Facilities string and trim spaces for each element
-		facilitiesArray = make([]string, 0)
-		for _, facility := range strings.Split(destination.Facilities, ",") {
-			facilitiesArray = append(facilitiesArray, strings.TrimSpace(facility))
-		}
-	}
-
 	// Populate the response struct with the destination details
-	destinationResponse = response.DestinationResponse{
-		ID:               destination.ID,
-		Name:             destination.Name,
-		City:             response.City{ID: destination.City.ID, Name: destination.City.Name},
-		Position:         destination.Position,
-		Address:          destination.Address,
-		OperationalHours: destination.OperationalHours,
-		TicketPrice:      destination.TicketPrice,
-		Category:         destination.Category,
-		Description:      destination.Description,
-		Facilities:       facilitiesArray, // Facilities as array of strings
-		CreatedAt:        destination.CreatedAt,
-		Images:           convertImagesToResponse(destination.Images),
-		VideoContents:    convertVideosToResponse(destination.VideoContents),
-	}
+	destinationResponse = toDestinationResponse(destination)
+	currentUser, _ := middlewares.CurrentUser(c)
+	destinationResponse.IsFavorite = favoriteSet(currentUser.ID, []uint{destination.ID})[destination.ID]
 
 	// Return the response
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -384,65 +511,137 @@ func GetDetailDestination(c echo.Context) error {
 	})
 }
 
-// GetMostViewedVideoContent godoc
-// @Summary Get most viewed video content
-// @Description Fetch destinations ranked by the number of video views
+// GetDestinationCarbon godoc
+// @Summary Estimate the carbon footprint of travelling to a destination
+// @Description Estimate the kg CO2e of travelling from an origin city to a destination with the given transport mode and number of travellers, along with lower-carbon alternatives
 // @Tags Destinations
-// @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]string
-// @Router /destinations/most-viewed-videos [get]
-func GetMostViewedVideoContent(c echo.Context) error {
-	var results []struct {
-		ID          uint   `json:"id"`
-		Name        string `json:"name"`
-		Address     string `json:"address"`
-		Description string `json:"description"`
-		ViewCount   int64  `json:"view_count"`
-	}
-
-	// Query to join Destination with VideoContentView and calculate view counts
-	err := config.DB.Table("destinations").
-		Select("destinations.id, destinations.name, destinations.address, destinations.description, COUNT(video_content_views.id) as view_count").
-		Joins("LEFT JOIN video_content_views ON destinations.id = video_content_views.destination_id").
-		Group("destinations.id").
-		Order("view_count DESC").
-		Scan(&results).Error
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch data"})
-	}
-
-	// Convert the result to a format similar to DestinationResponse
-	var responseResults []map[string]interface{}
-	for _, result := range results {
-		var videos []models.VideoContent
-		_ = config.DB.
-			Find(&videos, "destination_id = ?", result.ID)
-
-		responseResults = append(responseResults, map[string]interface{}{
-			"id":          result.ID,
-			"name":        result.Name,
-			"address":     result.Address,
-			"description": result.Description,
-			"view_count":  result.ViewCount,
-			"videos":      videos,
-		})
+// @Security BearerAuth
+// @Param id path int true "Destination ID"
+// @Param origin query string true "Origin city name"
+// @Param mode query string false "Transport mode: car, motorbike, bus, train, plane, bicycle, walking (default car)"
+// @Param travellers query int false "Number of travellers (default 1)"
+// @Success 200 {object} response.CarbonEstimateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /destination/{id}/carbon [get]
+func GetDestinationCarbon(c echo.Context) error {
+	var destination models.Destination
+	if err := config.DB.Preload("City").First(&destination, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Destination not found"})
 	}
 
-	// Return the result as JSON
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Destinations with view count fetched successfully",
-		"data":    responseResults,
+	var origin models.City
+	if err := config.DB.Where("name = ?", c.QueryParam("origin")).First(&origin).Error; err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Origin City not found"})
+	}
+
+	mode := c.QueryParam("mode")
+	if mode == "" {
+		mode = emissions.Car
+	}
+	if !config.Emissions.ValidMode(mode) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid transport mode"})
+	}
+
+	travellers := 1
+	if raw := c.QueryParam("travellers"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > 100 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "travellers must be between 1 and 100"})
+		}
+		travellers = value
+	}
+
+	lat, lon, ok := origin.Coordinates()
+	point, pointOK := destinationPoint(destination)
+	if !ok || !pointOK {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Coordinates are not available for this trip"})
+	}
+	distance := roundKm(helper.Haversine(lat, lon, point.Lat, point.Lon))
+
+	carbon, _ := config.Emissions.Estimate(mode, distance, travellers)
+	alternatives, _ := config.Emissions.Alternatives(mode, distance, travellers)
+
+	return c.JSON(http.StatusOK, response.CarbonEstimateResponse{
+		DistanceKm:    distance,
+		TransportMode: mode,
+		Travellers:    travellers,
+		CarbonKg:      carbon,
+		Alternatives:  alternatives,
 	})
 }
 
+// applySustainability menyalin atribut keberlanjutan dari input; EcoScore dihitung
+// ulang oleh hook BeforeSave saat destinasi disimpan
+func applySustainability(destination *models.Destination, input *request.CreateDestinationInput) {
+	certifications := make([]string, 0, len(input.Certifications))
+	for _, certification := range input.Certifications {
+		certifications = append(certifications, strings.TrimSpace(certification))
+	}
+
+	destination.Certifications = strings.Join(certifications, ",")
+	destination.WastePractice = input.WastePractice
+	destination.EnergyPractice = input.EnergyPractice
+	destination.VisitorCapacity = input.VisitorCapacity
+	destination.PublicTransportAccessible = input.PublicTransportAccessible
+}
+
+// toDestinationResponse mengubah model destinasi menjadi response, termasuk
+// memecah Facilities menjadi array string
+func toDestinationResponse(dest models.Destination) response.DestinationResponse {
+	var facilitiesArray []string
+	if dest.Facilities != "" {
+		facilitiesArray = make([]string, 0)
+		for _, facility := range strings.Split(dest.Facilities, ",") {
+			facilitiesArray = append(facilitiesArray, strings.TrimSpace(facility))
+		}
+	}
+
+	return response.DestinationResponse{
+		ID:               dest.ID,
+		Name:             dest.Name,
+		City:             response.City{ID: dest.City.ID, Name: dest.City.Name, Latitude: dest.City.Latitude, Longitude: dest.City.Longitude},
+		Position:         dest.Position,
+		Latitude:         dest.Latitude,
+		Longitude:        dest.Longitude,
+		Address:          dest.Address,
+		OperationalHours: dest.OperationalHours,
+		TicketPrice:      dest.TicketPrice,
+		Category:         dest.Category,
+		Description:      dest.Description,
+		Facilities:       facilitiesArray,
+		EcoScore:         dest.EcoScore,
+		AverageRating:    dest.AverageRating,
+		ReviewCount:      dest.ReviewCount,
+		Sustainability: response.Sustainability{
+			Certifications:            dest.CertificationList(),
+			WastePractice:             dest.WastePractice,
+			EnergyPractice:            dest.EnergyPractice,
+			VisitorCapacity:           dest.VisitorCapacity,
+			PublicTransportAccessible: dest.PublicTransportAccessible,
+		},
+		CreatedAt:     dest.CreatedAt,
+		Images:        convertImagesToResponse(dest.Images),
+		VideoContents: convertVideosToResponse(dest.VideoContents),
+	}
+}
+
 func convertImagesToResponse(images []models.Image) []response.Image {
 	var imageResponses []response.Image
 	for _, img := range images {
+		variants := make(map[string]response.ImageVariant, len(img.Variants))
+		for _, variant := range img.Variants {
+			variants[variant.Name] = response.ImageVariant{
+				URL:    config.Storage.URL(variant.Key),
+				Width:  variant.Width,
+				Height: variant.Height,
+			}
+		}
 		imageResponses = append(imageResponses, response.Image{
 			DestinationID: img.DestinationID,
-			URL:           img.URL,
+			URL:           fileURL(img.URL),
+			Variants:      variants,
 		})
 	}
 	return imageResponses
@@ -463,11 +662,11 @@ func convertVideosToResponse(videos []models.VideoContent) []response.VideoConte
 
 // GetPersonalizedDestinationByUser godoc
 // @Summary Get personalized destinations for a user
-// @Description Fetch destinations based on the user's preferences and categories
+// @Description Fetch destinations based on the authenticated user's preferences and categories
 // @Tags Destinations
 // @Accept json
 // @Produce json
-// @Param user_id query string true "User ID"
+// @Security BearerAuth
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
@@ -477,10 +676,10 @@ func GetPersonalizedDestinationByUser(c echo.Context) error {
 	var destinations []models.Destination
 	var destinationResponses []response.DestinationResponse
 
-	userID := c.QueryParam("user_id")
+	currentUser, _ := middlewares.CurrentUser(c)
 
 	var user models.User
-	result := config.DB.First(&user, "id = ?", userID)
+	result := config.DB.First(&user, "id = ?", currentUser.ID)
 	if result.Error != nil || user.ID == 0 {
 		response := helper.APIResponse("User not found", http.StatusUnauthorized, "error", nil)
 		return c.JSON(http.StatusUnauthorized, response)
@@ -488,7 +687,7 @@ func GetPersonalizedDestinationByUser(c echo.Context) error {
 
 	query := config.DB.
 		Preload("City").
-		Preload("Images").
+		Preload("Images.Variants").
 		Preload("VideoContents")
 
 	categories := strings.Split(user.Category, ",")
@@ -502,33 +701,7 @@ func GetPersonalizedDestinationByUser(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch destinations"})
 	}
 
-	for _, dest := range destinations {
-		var facilitiesArray []string
-		if dest.Facilities != "" {
-			facilitiesArray = make([]string, 0)
-			for _, facility := range strings.Split(dest.Facilities, ",") {
-				facilitiesArray = append(facilitiesArray, strings.TrimSpace(facility))
-			}
-		}
-
-		destinationResponse := response.DestinationResponse{
-			ID:               dest.ID,
-			Name:             dest.Name,
-			City:             response.City{ID: dest.City.ID, Name: dest.City.Name},
-			Position:         dest.Position,
-			Address:          dest.Address,
-			OperationalHours: dest.OperationalHours,
-			TicketPrice:      dest.TicketPrice,
-			Category:         dest.Category,
-			Description:      dest.Description,
-			Facilities:       facilitiesArray,
-			CreatedAt:        dest.CreatedAt,
-			Images:           convertImagesToResponse(dest.Images),
-			VideoContents:    convertVideosToResponse(dest.VideoContents),
-		}
-
-		destinationResponses = append(destinationResponses, destinationResponse)
-	}
+	destinationResponses = toDestinationResponses(c, destinations)
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Destinations fetched successfully",
@@ -591,17 +764,32 @@ func CreateDestinationAssetsHandler(c echo.Context) error {
 
 // GetAllVideoContents godoc
 // @Summary Get all video contents
-// @Description Fetch all video contents stored in the system
+// @Description Fetch video contents stored in the system, one page at a time
 // @Tags Video
 // @Accept json
 // @Produce json
+// @Param destination_id query int false "Filter by destination ID"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /videos [get]
 func GetAllVideoContents(c echo.Context) error {
 	var videos []models.VideoContent
 
-	err := config.DB.Find(&videos).Error
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	query := config.DB.Model(&models.VideoContent{})
+	if destinationID := c.QueryParam("destination_id"); destinationID != "" {
+		query = query.Where("destination_id = ?", destinationID)
+	}
+
+	meta, err := helper.Paginate(query, pageQuery, "id", helper.Sort{}, &videos)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch videos"})
 	}
@@ -609,6 +797,7 @@ func GetAllVideoContents(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Video Contents fetched successfully",
 		"videos":  convertVideosToResponse(videos),
+		"meta":    meta,
 	})
 }
 
@@ -632,7 +821,7 @@ func UpdateDestinationAssetsHandler(c echo.Context) error {
 	tx := config.DB.Begin()
 
 	// Delete related Images
-	if err := tx.Where("destination_id = ?", destination.ID).Delete(&destination.Images).Error; err != nil {
+	if err := deleteDestinationImages(tx, destination.ID); err != nil {
 		tx.Rollback()
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete related images"})
 	}
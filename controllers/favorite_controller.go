@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/middlewares"
+	"backend/models"
+	"backend/response"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm/clause"
+)
+
+// AddFavorite godoc
+// @Summary Save a destination to favorites
+// @Description Add a destination to the authenticated user's favorites. Saving a destination twice has no effect.
+// @Tags Favorites
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Destination ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /destination/{id}/favorite [post]
+func AddFavorite(c echo.Context) error {
+	var destination models.Destination
+	if err := config.DB.First(&destination, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Destination not found"})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	favorite := models.Favorite{UserID: currentUser.ID, DestinationID: destination.ID}
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to save favorite"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":     "Destination added to favorites",
+		"is_favorite": true,
+	})
+}
+
+// RemoveFavorite godoc
+// @Summary Remove a destination from favorites
+// @Description Remove a destination from the authenticated user's favorites. Removing a destination that is not a favorite has no effect.
+// @Tags Favorites
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Destination ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /destination/{id}/favorite [delete]
+func RemoveFavorite(c echo.Context) error {
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	err := config.DB.Where("user_id = ? AND destination_id = ?", currentUser.ID, c.Param("id")).
+		Delete(&models.Favorite{}).Error
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to remove favorite"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":     "Destination removed from favorites",
+		"is_favorite": false,
+	})
+}
+
+// GetMyFavorites godoc
+// @Summary List favorite destinations
+// @Description Fetch the authenticated user's favorite destinations, most recently saved first
+// @Tags Favorites
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/favorites [get]
+func GetMyFavorites(c echo.Context) error {
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	var favorites []models.Favorite
+	query := config.DB.Model(&models.Favorite{}).Where("user_id = ?", currentUser.ID)
+	meta, err := helper.Paginate(query, pageQuery, "favorites.id", helper.Sort{Desc: true}, &favorites,
+		"Destination", "Destination.City", "Destination.Images.Variants", "Destination.VideoContents")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch favorites"})
+	}
+
+	destinationResponses := make([]response.DestinationResponse, 0, len(favorites))
+	for _, favorite := range favorites {
+		destinationResponse := toDestinationResponse(favorite.Destination)
+		destinationResponse.IsFavorite = true
+		destinationResponses = append(destinationResponses, destinationResponse)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "Favorites fetched successfully",
+		"destinations": destinationResponses,
+		"meta":         meta,
+	})
+}
+
+// favoriteSet mengembalikan destinasi mana saja (dari destinationIDs) yang difavoritkan user
+func favoriteSet(userID uint, destinationIDs []uint) map[uint]bool {
+	favorites := make(map[uint]bool)
+	if userID == 0 || len(destinationIDs) == 0 {
+		return favorites
+	}
+
+	var ids []uint
+	config.DB.Model(&models.Favorite{}).
+		Where("user_id = ? AND destination_id IN ?", userID, destinationIDs).
+		Pluck("destination_id", &ids)
+	for _, id := range ids {
+		favorites[id] = true
+	}
+	return favorites
+}
+
+// toDestinationResponses mengubah daftar destinasi menjadi response dengan is_favorite
+// untuk user yang sedang login
+func toDestinationResponses(c echo.Context, destinations []models.Destination) []response.DestinationResponse {
+	ids := make([]uint, len(destinations))
+	for i, destination := range destinations {
+		ids[i] = destination.ID
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+	favorites := favoriteSet(currentUser.ID, ids)
+
+	responses := make([]response.DestinationResponse, 0, len(destinations))
+	for _, destination := range destinations {
+		destinationResponse := toDestinationResponse(destination)
+		destinationResponse.IsFavorite = favorites[destination.ID]
+		responses = append(responses, destinationResponse)
+	}
+	return responses
+}
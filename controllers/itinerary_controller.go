@@ -0,0 +1,526 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/middlewares"
+	"backend/models"
+	"backend/request"
+	"backend/response"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxRouteStops sama dengan batas destinasi saat rute dibuat
+const maxRouteStops = 50
+
+// GetRouteDetail godoc
+// @Summary Get a route itinerary
+// @Description Fetch a route with its stops grouped by day, including planned arrival/departure times, stay durations and notes. Only the owner or an admin can view it.
+// @Tags Routes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Success 200 {object} response.RouteDetailResponse
+// @Failure 400 {object} map[string]string "Invalid route ID"
+// @Failure 403 {object} map[string]interface{} "Route belongs to another user"
+// @Failure 404 {object} map[string]string "Route not found"
+// @Router /route/{id} [get]
+func GetRouteDetail(c echo.Context) error {
+	route, err := ownedRoute(c)
+	if route == nil {
+		return err
+	}
+
+	return respondItinerary(c, config.DB, *route)
+}
+
+// AddRouteStop godoc
+// @Summary Add a stop to a route
+// @Description Add a destination to an existing route on the given day (default 1). Position is 1-based within that day; without it the stop is appended to the day. The route distance is recomputed in the new order.
+// @Tags Routes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Param input body request.RouteStopInput true "Stop details"
+// @Success 200 {object} response.RouteDetailResponse
+// @Failure 400 {object} map[string]interface{} "Invalid input"
+// @Failure 403 {object} map[string]interface{} "Route belongs to another user"
+// @Failure 404 {object} map[string]string "Route or destination not found"
+// @Failure 500 {object} map[string]string "Failed to add stop"
+// @Router /route/{id}/stops [post]
+func AddRouteStop(c echo.Context) error {
+	route, err := ownedRoute(c)
+	if route == nil {
+		return err
+	}
+
+	var input request.RouteStopInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	var destination models.Destination
+	if err := config.DB.First(&destination, input.DestinationID).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Destination not found"})
+	}
+
+	stop := models.RouteDestination{
+		RouteID:       route.ID,
+		DestinationID: destination.ID,
+		Day:           input.Day,
+		ArrivalTime:   input.ArrivalTime,
+		DepartureTime: input.DepartureTime,
+		Notes:         input.Notes,
+		CreatedAt:     time.Now(),
+	}
+	if stop.Day == 0 {
+		stop.Day = 1
+	}
+	if input.StayMinutes != nil {
+		stop.StayMinutes = *input.StayMinutes
+	}
+	if err := normalizeStopTimes(&stop, input.StayMinutes != nil); err != nil {
+		return respondHTTPError(c, err)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		stops, err := routeStops(tx, route.ID)
+		if err != nil {
+			return err
+		}
+		if len(stops) >= maxRouteStops {
+			return echo.NewHTTPError(http.StatusBadRequest, "A route can have at most "+strconv.Itoa(maxRouteStops)+" stops")
+		}
+
+		if err := tx.Create(&stop).Error; err != nil {
+			return err
+		}
+
+		stops = insertStop(stops, stop, input.Position)
+		return saveItinerary(tx, route, stops)
+	})
+	if err != nil {
+		return respondHTTPError(c, err)
+	}
+
+	return respondItinerary(c, config.DB, *route)
+}
+
+// UpdateRouteStop godoc
+// @Summary Update a route stop
+// @Description Change the day, planned times, stay duration or notes of a stop. Only the fields that are sent are updated; an empty string clears a time.
+// @Tags Routes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Param stopId path int true "Stop ID"
+// @Param input body request.UpdateRouteStopInput true "Fields to update"
+// @Success 200 {object} response.RouteDetailResponse
+// @Failure 400 {object} map[string]interface{} "Invalid input"
+// @Failure 403 {object} map[string]interface{} "Route belongs to another user"
+// @Failure 404 {object} map[string]string "Route or stop not found"
+// @Failure 500 {object} map[string]string "Failed to update stop"
+// @Router /route/{id}/stops/{stopId} [put]
+func UpdateRouteStop(c echo.Context) error {
+	route, err := ownedRoute(c)
+	if route == nil {
+		return err
+	}
+
+	var input request.UpdateRouteStopInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		stops, err := routeStops(tx, route.ID)
+		if err != nil {
+			return err
+		}
+
+		index, err := findStop(c, stops)
+		if err != nil {
+			return err
+		}
+
+		stop := &stops[index]
+		if input.Day != nil {
+			stop.Day = *input.Day
+		}
+		if input.ArrivalTime != nil {
+			stop.ArrivalTime = *input.ArrivalTime
+		}
+		if input.DepartureTime != nil {
+			stop.DepartureTime = *input.DepartureTime
+		}
+		if input.StayMinutes != nil {
+			stop.StayMinutes = *input.StayMinutes
+		}
+		if input.Notes != nil {
+			stop.Notes = *input.Notes
+		}
+		if err := normalizeStopTimes(stop, input.StayMinutes != nil); err != nil {
+			return err
+		}
+
+		if err := tx.Save(stop).Error; err != nil {
+			return err
+		}
+		return saveItinerary(tx, route, stops)
+	})
+	if err != nil {
+		return respondHTTPError(c, err)
+	}
+
+	return respondItinerary(c, config.DB, *route)
+}
+
+// RemoveRouteStop godoc
+// @Summary Remove a stop from a route
+// @Description Remove a stop; the remaining stops are renumbered and the route distance is recomputed.
+// @Tags Routes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Param stopId path int true "Stop ID"
+// @Success 200 {object} response.RouteDetailResponse
+// @Failure 400 {object} map[string]string "Invalid stop ID"
+// @Failure 403 {object} map[string]interface{} "Route belongs to another user"
+// @Failure 404 {object} map[string]string "Route or stop not found"
+// @Failure 500 {object} map[string]string "Failed to remove stop"
+// @Router /route/{id}/stops/{stopId} [delete]
+func RemoveRouteStop(c echo.Context) error {
+	route, err := ownedRoute(c)
+	if route == nil {
+		return err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		stops, err := routeStops(tx, route.ID)
+		if err != nil {
+			return err
+		}
+
+		index, err := findStop(c, stops)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&stops[index]).Error; err != nil {
+			return err
+		}
+		stops = append(stops[:index], stops[index+1:]...)
+		return saveItinerary(tx, route, stops)
+	})
+	if err != nil {
+		return respondHTTPError(c, err)
+	}
+
+	return respondItinerary(c, config.DB, *route)
+}
+
+// ReorderRouteStops godoc
+// @Summary Reorder the stops of a route
+// @Description Replace the visiting order with the given list, which must contain every stop of the route exactly once. A day may be given per stop to move it; stops are kept grouped by day.
+// @Tags Routes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Param input body request.ReorderRouteStopsInput true "Stops in the new order"
+// @Success 200 {object} response.RouteDetailResponse
+// @Failure 400 {object} map[string]interface{} "Invalid input"
+// @Failure 403 {object} map[string]interface{} "Route belongs to another user"
+// @Failure 404 {object} map[string]string "Route not found"
+// @Failure 500 {object} map[string]string "Failed to reorder stops"
+// @Router /route/{id}/stops/order [put]
+func ReorderRouteStops(c echo.Context) error {
+	route, err := ownedRoute(c)
+	if route == nil {
+		return err
+	}
+
+	var input request.ReorderRouteStopsInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		stops, err := routeStops(tx, route.ID)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[uint]models.RouteDestination, len(stops))
+		for _, stop := range stops {
+			byID[stop.ID] = stop
+		}
+		if len(input.Stops) != len(stops) {
+			return echo.NewHTTPError(http.StatusBadRequest, "The new order must list every stop of the route exactly once")
+		}
+
+		reordered := make([]models.RouteDestination, 0, len(stops))
+		for _, item := range input.Stops {
+			stop, ok := byID[item.ID]
+			if !ok {
+				return echo.NewHTTPError(http.StatusBadRequest, "The new order must list every stop of the route exactly once")
+			}
+			delete(byID, item.ID)
+
+			if item.Day != 0 {
+				stop.Day = item.Day
+			}
+			reordered = append(reordered, stop)
+		}
+
+		return saveItinerary(tx, route, reordered)
+	})
+	if err != nil {
+		return respondHTTPError(c, err)
+	}
+
+	return respondItinerary(c, config.DB, *route)
+}
+
+// ownedRoute memuat rute dari parameter :id dan memastikan user boleh mengubahnya.
+// Jika rute nil, response sudah ditulis dan error-nya tinggal dikembalikan handler.
+func ownedRoute(c echo.Context) (*models.Route, error) {
+	routeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid route ID"})
+	}
+
+	var route models.Route
+	if err := config.DB.First(&route, routeID).Error; err != nil {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"message": "Route not found"})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+	if !middlewares.CanActOn(currentUser, route.UserID) {
+		return nil, middlewares.Forbidden(c, "Access forbidden: you can only manage your own routes")
+	}
+
+	return &route, nil
+}
+
+func routeStops(tx *gorm.DB, routeID uint) ([]models.RouteDestination, error) {
+	var stops []models.RouteDestination
+	err := tx.Where("route_id = ?", routeID).Order("sequence, id").Find(&stops).Error
+	return stops, err
+}
+
+func findStop(c echo.Context, stops []models.RouteDestination) (int, error) {
+	stopID, err := strconv.Atoi(c.Param("stopId"))
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid stop ID")
+	}
+	for i, stop := range stops {
+		if stop.ID == uint(stopID) {
+			return i, nil
+		}
+	}
+	return 0, echo.NewHTTPError(http.StatusNotFound, "Stop not found")
+}
+
+// insertStop menyisipkan stop pada posisi ke-position (mulai dari 1) di harinya;
+// position 0 atau melebihi jumlah stop hari itu berarti di akhir hari tersebut.
+func insertStop(stops []models.RouteDestination, stop models.RouteDestination, position int) []models.RouteDestination {
+	at := len(stops)
+	seen := 0
+	for i, existing := range stops {
+		if existing.Day > stop.Day || (existing.Day == stop.Day && position > 0 && seen == position-1) {
+			at = i
+			break
+		}
+		if existing.Day == stop.Day {
+			seen++
+		}
+	}
+
+	stops = append(stops, models.RouteDestination{})
+	copy(stops[at+1:], stops[at:])
+	stops[at] = stop
+	return stops
+}
+
+// normalizeStopTimes memastikan jam berangkat tidak sebelum jam tiba, dan mengisi lama
+// singgah dari selisih keduanya jika tidak diisi secara eksplisit.
+func normalizeStopTimes(stop *models.RouteDestination, explicitStay bool) error {
+	if stop.ArrivalTime == "" || stop.DepartureTime == "" {
+		return nil
+	}
+
+	arrival, _ := time.Parse("15:04", stop.ArrivalTime)
+	departure, _ := time.Parse("15:04", stop.DepartureTime)
+	if departure.Before(arrival) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Departure time must not be before arrival time")
+	}
+	if !explicitStay {
+		stop.StayMinutes = int(departure.Sub(arrival).Minutes())
+	}
+	return nil
+}
+
+// saveItinerary mengelompokkan stop per hari, menomori ulang sequence sesuai urutan
+// yang diberikan, lalu menghitung ulang jarak dan jejak karbon rute.
+func saveItinerary(tx *gorm.DB, route *models.Route, stops []models.RouteDestination) error {
+	sort.SliceStable(stops, func(a, b int) bool {
+		return stops[a].Day < stops[b].Day
+	})
+
+	for i := range stops {
+		err := tx.Model(&models.RouteDestination{}).Where("id = ?", stops[i].ID).
+			Updates(map[string]interface{}{"sequence": i + 1, "day": stops[i].Day}).Error
+		if err != nil {
+			return err
+		}
+		stops[i].Sequence = i + 1
+	}
+
+	distance, err := itineraryDistance(tx, *route, stops)
+	if err != nil {
+		return err
+	}
+	route.Distance = distance
+	if err := tx.Model(route).Update("distance", distance).Error; err != nil {
+		return err
+	}
+	return updateRouteCarbon(tx, route)
+}
+
+// itineraryDistance menjumlahkan jarak origin -> stop sesuai urutan -> kota tujuan
+// (kecuali rute terbuka). Titik tanpa koordinat dilewati.
+func itineraryDistance(tx *gorm.DB, route models.Route, stops []models.RouteDestination) (float64, error) {
+	var points []helper.GeoPoint
+
+	var origin models.City
+	if err := tx.Where("name = ?", route.OriginCityName).First(&origin).Error; err == nil {
+		if lat, lon, ok := origin.Coordinates(); ok {
+			points = append(points, helper.GeoPoint{Lat: lat, Lon: lon})
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	destinations, err := stopDestinations(tx, stops)
+	if err != nil {
+		return 0, err
+	}
+	for _, stop := range stops {
+		if point, ok := destinationPoint(destinations[stop.DestinationID]); ok {
+			points = append(points, point)
+		}
+	}
+
+	if !route.OpenEnded {
+		var end models.City
+		if err := tx.Where("name = ?", route.DestinationCityName).First(&end).Error; err == nil {
+			if lat, lon, ok := end.Coordinates(); ok {
+				points = append(points, helper.GeoPoint{Lat: lat, Lon: lon})
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+	}
+
+	if len(points) < 2 {
+		return 0, nil
+	}
+
+	order := make([]int, len(points)-1)
+	for i := range order {
+		order[i] = i + 1
+	}
+	total := 0.0
+	for _, leg := range helper.RouteLegs(points[0], nil, points, order) {
+		total += leg
+	}
+	return roundKm(total), nil
+}
+
+func stopDestinations(tx *gorm.DB, stops []models.RouteDestination) (map[uint]models.Destination, error) {
+	byID := make(map[uint]models.Destination)
+	if len(stops) == 0 {
+		return byID, nil
+	}
+
+	ids := make([]uint, len(stops))
+	for i, stop := range stops {
+		ids[i] = stop.DestinationID
+	}
+
+	var destinations []models.Destination
+	if err := tx.Preload("City").Where("id IN ?", ids).Find(&destinations).Error; err != nil {
+		return nil, err
+	}
+	for _, destination := range destinations {
+		byID[destination.ID] = destination
+	}
+	return byID, nil
+}
+
+func respondItinerary(c echo.Context, db *gorm.DB, route models.Route) error {
+	stops, err := routeStops(db, route.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+	}
+	destinations, err := stopDestinations(db, stops)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+	}
+
+	detail := response.RouteDetailResponse{
+		ID:                  route.ID,
+		UserID:              route.UserID,
+		OriginCityName:      route.OriginCityName,
+		DestinationCityName: route.DestinationCityName,
+		OpenEnded:           route.OpenEnded,
+		Distance:            route.Distance,
+		Time:                route.Time,
+		Cost:                route.Cost,
+		TransportMode:       route.TransportMode,
+		Travellers:          route.Travellers,
+		CarbonKg:            route.CarbonKg,
+		CarbonAlternatives:  carbonAlternatives(route),
+		CreatedAt:           route.CreatedAt,
+		Days:                []response.ItineraryDay{},
+	}
+
+	for _, stop := range stops {
+		if len(detail.Days) == 0 || detail.Days[len(detail.Days)-1].Day != stop.Day {
+			detail.Days = append(detail.Days, response.ItineraryDay{Day: stop.Day})
+		}
+		day := &detail.Days[len(detail.Days)-1]
+		day.Stops = append(day.Stops, response.ItineraryStop{
+			RouteDestination: stop,
+			Destination:      destinations[stop.DestinationID],
+		})
+	}
+
+	return c.JSON(http.StatusOK, detail)
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/jobs"
+	"backend/media"
+	"backend/models"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxImagesPerUpload membatasi jumlah file dalam satu request upload gambar destinasi
+const maxImagesPerUpload = 10
+
+// UploadDestinationImages godoc
+// @Summary Upload destination images
+// @Description Upload one or more images for a destination. Files are checked by content (JPEG, PNG, WebP or GIF), limited in size (UPLOAD_MAX_BYTES, default 5 MiB) and stored under content-hash names, so the returned URLs are stable. Thumbnail, medium and large variants are generated with EXIF metadata stripped.
+// @Tags Destinations
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Destination ID"
+// @Param images formData file true "Image files (up to 10)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /destination/{id}/images [post]
+func UploadDestinationImages(c echo.Context) error {
+	var destination models.Destination
+	if err := config.DB.First(&destination, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Destination not found"})
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["images"]) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "At least one file is required in the images field"})
+	}
+	files := form.File["images"]
+	if len(files) > maxImagesPerUpload {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Too many files in one request"})
+	}
+
+	// Simpan semua file dulu; file yang sudah tersimpan aman ditinggal jika ada yang gagal
+	// karena namanya berbasis isi dan tidak menimpa file lain
+	uploaded := make([]media.File, 0, len(files))
+	contents := make([][]byte, 0, len(files))
+	for _, file := range files {
+		data, err := config.Uploads.ReadFile(file)
+		if err != nil {
+			status, message := uploadErrorStatus(err)
+			return c.JSON(status, map[string]string{"message": message, "file": file.Filename})
+		}
+		saved, err := config.Uploads.SaveData(c.Request().Context(), data)
+		if err != nil {
+			status, message := uploadErrorStatus(err)
+			return c.JSON(status, map[string]string{"message": message, "file": file.Filename})
+		}
+		uploaded = append(uploaded, saved)
+		contents = append(contents, data)
+	}
+
+	images := make([]models.Image, len(uploaded))
+	for i, file := range uploaded {
+		images[i] = models.Image{DestinationID: destination.ID, URL: file.Key, VariantsStatus: models.ImageVariantsPending}
+	}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&images).Error
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to add image"})
+	}
+
+	// Gambar yang gagal diproses tetap tersimpan; job backfill mencoba lagi nanti
+	for i := range images {
+		if err := jobs.GenerateImageVariants(c.Request().Context(), &images[i], contents[i]); err != nil {
+			log.Printf("Failed to generate variants for image %d: %v", images[i].ID, err)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Images uploaded successfully",
+		"images":  convertImagesToResponse(images),
+		"files":   uploaded,
+	})
+}
+
+// uploadErrorStatus memetakan error dari media.Uploader ke status HTTP dan pesan untuk client
+func uploadErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, media.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge, "File is too large"
+	case errors.Is(err, media.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType, "Only JPEG, PNG, WebP and GIF images are allowed"
+	case errors.Is(err, media.ErrEmpty):
+		return http.StatusBadRequest, "File is empty"
+	default:
+		return http.StatusInternalServerError, "Failed to save file"
+	}
+}
+
+// deleteDestinationImages menghapus gambar destinasi beserta variannya. File di Storage
+// tidak ikut dihapus karena key berbasis isi bisa dipakai gambar lain.
+func deleteDestinationImages(tx *gorm.DB, destinationID uint) error {
+	imageIDs := tx.Model(&models.Image{}).Select("id").Where("destination_id = ?", destinationID)
+	if err := tx.Where("image_id IN (?)", imageIDs).Delete(&models.ImageVariant{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("destination_id = ?", destinationID).Delete(&models.Image{}).Error
+}
+
+// fileURL mengubah nilai file yang tersimpan di database menjadi URL publik. URL absolut
+// (mis. gambar dari input JSON) dikembalikan apa adanya; selain itu dianggap key Storage,
+// termasuk nilai lama berbentuk "assets/<nama file>"
+func fileURL(value string) string {
+	if value == "" || strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return value
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(value, "/"), "assets/")
+	return config.Storage.URL(key)
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/mailer"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=6"`
+}
+
+// passwordResetTTL adalah masa berlaku link reset password (PASSWORD_RESET_TTL, default 1 jam)
+func passwordResetTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return time.Hour
+}
+
+// passwordResetLink membangun link yang dikirim ke email pengguna
+func passwordResetLink(token string) string {
+	base := os.Getenv("PASSWORD_RESET_URL")
+	if base == "" {
+		base = os.Getenv("APP_BASE") + "/reset-password"
+	}
+	return base + "?token=" + url.QueryEscape(token)
+}
+
+// ForgotPasswordHandler godoc
+// @Summary Request a password reset
+// @Description Send a one-time password reset link to the email address if it belongs to an account. The response is the same whether or not the email exists.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param input body ForgotPasswordInput true "Account email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /password/forgot [post]
+func ForgotPasswordHandler(c echo.Context) error {
+	var input ForgotPasswordInput
+
+	// Bind input
+	if err := c.Bind(&input); err != nil {
+		response := helper.APIResponse("Invalid request", http.StatusBadRequest, "error", nil)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	// Validasi input
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	// Jangan bocorkan apakah email terdaftar atau tidak
+	success := helper.APIResponse("If the email is registered, a reset link has been sent", http.StatusOK, "success", nil)
+
+	var user models.User
+	if err := config.DB.First(&user, "email = ?", input.Email).Error; err != nil {
+		return c.JSON(http.StatusOK, success)
+	}
+
+	token, err := helper.RandomToken(32)
+	if err != nil {
+		response := helper.APIResponse("Failed to create reset token", http.StatusInternalServerError, "error", nil)
+		return c.JSON(http.StatusInternalServerError, response)
+	}
+
+	ttl := passwordResetTTL()
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Hanya link terakhir yang berlaku
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: helper.HashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		response := helper.APIResponse("Failed to create reset token", http.StatusInternalServerError, "error", nil)
+		return c.JSON(http.StatusInternalServerError, response)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your TripWise password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your TripWise password. "+
+			"Open the link below to choose a new one:\n\n%s\n\n"+
+			"The link expires in %s and can only be used once. "+
+			"If you did not request this, you can ignore this email.\n",
+			user.FirstName, passwordResetLink(token), ttl),
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 15*time.Second)
+	defer cancel()
+	// Kegagalan kirim hanya dicatat: status yang berbeda akan membocorkan email mana yang terdaftar
+	if err := config.Mailer.Send(ctx, msg); err != nil {
+		log.Println("Failed to send password reset email:", err)
+	}
+
+	return c.JSON(http.StatusOK, success)
+}
+
+// ResetPasswordHandler godoc
+// @Summary Reset password with a one-time token
+// @Description Set a new password using the token from the reset email. The token can only be used once and every active session of the user is logged out.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param input body ResetPasswordInput true "Reset token and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /password/reset [post]
+func ResetPasswordHandler(c echo.Context) error {
+	var input ResetPasswordInput
+
+	// Bind input
+	if err := c.Bind(&input); err != nil {
+		response := helper.APIResponse("Invalid request", http.StatusBadRequest, "error", nil)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	// Validasi input
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	invalid := helper.APIResponse("Invalid or expired reset token", http.StatusBadRequest, "error", nil)
+
+	var resetToken models.PasswordResetToken
+	if err := config.DB.First(&resetToken, "token_hash = ?", helper.HashToken(input.Token)).Error; err != nil {
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+
+	hashedPassword, err := helper.HashPassword(input.NewPassword)
+	if err != nil {
+		response := helper.APIResponse("Failed to hash password", http.StatusInternalServerError, "error", nil)
+		return c.JSON(http.StatusInternalServerError, response)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Update bersyarat supaya token tidak bisa dipakai dua kali secara bersamaan
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", resetToken.UserID).Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		_, err := revokeAllSessions(tx, resetToken.UserID)
+		return err
+	})
+	if err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+	if err != nil {
+		response := helper.APIResponse("Failed to reset password", http.StatusInternalServerError, "error", nil)
+		return c.JSON(http.StatusInternalServerError, response)
+	}
+
+	response := helper.APIResponse("Password has been reset", http.StatusOK, "success", nil)
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,442 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/middlewares"
+	"backend/models"
+	"backend/request"
+	"backend/response"
+	"encoding/json"
+	"math"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetDestinationReviews godoc
+// @Summary List reviews of a destination
+// @Description Fetch visible reviews of a destination, newest first. Admins can add include_hidden=true to see hidden reviews too.
+// @Tags Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Destination ID"
+// @Param include_hidden query bool false "Include hidden reviews (admin only)"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /destination/{id}/reviews [get]
+func GetDestinationReviews(c echo.Context) error {
+	var destination models.Destination
+	if err := config.DB.First(&destination, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Destination not found"})
+	}
+
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	query := config.DB.Model(&models.Review{}).Where("destination_id = ?", destination.ID)
+	if !(currentUser.IsAdmin() && c.QueryParam("include_hidden") == "true") {
+		query = query.Where("status = ?", models.ReviewVisible)
+	}
+
+	var reviews []models.Review
+	meta, err := helper.Paginate(query, pageQuery, "reviews.id", helper.Sort{Desc: true}, &reviews, "User", "Photos")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch reviews"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":        "Reviews fetched successfully",
+		"average_rating": destination.AverageRating,
+		"review_count":   destination.ReviewCount,
+		"reviews":        toReviewResponses(reviews, currentUser),
+		"meta":           meta,
+	})
+}
+
+// GetReviewsForModeration godoc
+// @Summary List reviews for moderation
+// @Description Fetch reviews across all destinations for moderation, newest first. Admin only.
+// @Tags Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (visible, hidden)"
+// @Param flagged query bool false "Only flagged reviews"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /review [get]
+func GetReviewsForModeration(c echo.Context) error {
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	query := config.DB.Model(&models.Review{})
+	if status := c.QueryParam("status"); status != "" {
+		if status != models.ReviewVisible && status != models.ReviewHidden {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "status must be visible or hidden"})
+		}
+		query = query.Where("status = ?", status)
+	}
+	if c.QueryParam("flagged") == "true" {
+		query = query.Where("flagged = ?", true)
+	}
+
+	var reviews []models.Review
+	meta, err := helper.Paginate(query, pageQuery, "reviews.id", helper.Sort{Desc: true}, &reviews, "User", "Photos")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch reviews"})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Reviews fetched successfully",
+		"reviews": toReviewResponses(reviews, currentUser),
+		"meta":    meta,
+	})
+}
+
+// CreateReview godoc
+// @Summary Review a destination
+// @Description Give a destination a 1-5 rating with optional text and photo URLs. Each user can review a destination once.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Destination ID"
+// @Param input body request.ReviewInput true "Rating, comment and photo URLs"
+// @Success 200 {object} response.ReviewResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Already reviewed"
+// @Failure 500 {object} map[string]string
+// @Router /destination/{id}/reviews [post]
+func CreateReview(c echo.Context) error {
+	var destination models.Destination
+	if err := config.DB.First(&destination, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Destination not found"})
+	}
+
+	var input request.ReviewInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	var existing models.Review
+	if err := config.DB.Where("destination_id = ? AND user_id = ?", destination.ID, currentUser.ID).First(&existing).Error; err == nil {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "You have already reviewed this destination"})
+	}
+
+	review := models.Review{
+		DestinationID: destination.ID,
+		UserID:        currentUser.ID,
+		Rating:        input.Rating,
+		Comment:       input.Comment,
+		Status:        models.ReviewVisible,
+		Photos:        toReviewPhotos(input.Photos),
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		return refreshDestinationRating(tx, destination.ID)
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create review"})
+	}
+
+	return respondReview(c, review.ID)
+}
+
+// UpdateReview godoc
+// @Summary Edit a review
+// @Description Change the rating, text and photos of your own review
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param input body request.ReviewInput true "Rating, comment and photo URLs"
+// @Success 200 {object} response.ReviewResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /review/{id} [put]
+func UpdateReview(c echo.Context) error {
+	var review models.Review
+	if err := config.DB.First(&review, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Review not found"})
+	}
+
+	// Isi review hanya boleh diubah penulisnya; admin memakai moderasi
+	currentUser, _ := middlewares.CurrentUser(c)
+	if review.UserID != currentUser.ID {
+		return middlewares.Forbidden(c, "Access forbidden: you can only edit your own reviews")
+	}
+
+	var input request.ReviewInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		review.Rating = input.Rating
+		review.Comment = input.Comment
+		if err := tx.Save(&review).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("review_id = ?", review.ID).Delete(&models.ReviewPhoto{}).Error; err != nil {
+			return err
+		}
+		if photos := toReviewPhotos(input.Photos); len(photos) > 0 {
+			for i := range photos {
+				photos[i].ReviewID = review.ID
+			}
+			if err := tx.Create(&photos).Error; err != nil {
+				return err
+			}
+		}
+
+		return refreshDestinationRating(tx, review.DestinationID)
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update review"})
+	}
+
+	return respondReview(c, review.ID)
+}
+
+// DeleteReview godoc
+// @Summary Delete a review
+// @Description Delete a review. Only the author or an admin can delete it.
+// @Tags Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /review/{id} [delete]
+func DeleteReview(c echo.Context) error {
+	var review models.Review
+	if err := config.DB.First(&review, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Review not found"})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+	if !middlewares.CanActOn(currentUser, review.UserID) {
+		return middlewares.Forbidden(c, "Access forbidden: you can only delete your own reviews")
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", review.ID).Delete(&models.ReviewPhoto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		return refreshDestinationRating(tx, review.DestinationID)
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete review"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Review successfully deleted"})
+}
+
+// ModerateReview godoc
+// @Summary Moderate a review
+// @Description Hide, unhide, flag or unflag a review. Hidden reviews are excluded from listings and from the destination's rating. Admin only.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param input body request.ModerateReviewInput true "Moderation action and optional reason"
+// @Success 200 {object} response.ReviewResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /review/{id}/moderation [put]
+func ModerateReview(c echo.Context) error {
+	var review models.Review
+	if err := config.DB.First(&review, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Review not found"})
+	}
+
+	var input request.ModerateReviewInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	updates := map[string]interface{}{}
+	switch input.Action {
+	case "hide":
+		updates["status"] = models.ReviewHidden
+	case "unhide":
+		updates["status"] = models.ReviewVisible
+	case "flag":
+		updates["flagged"] = true
+		updates["flag_reason"] = input.Reason
+	case "unflag":
+		updates["flagged"] = false
+		updates["flag_reason"] = ""
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&review).Updates(updates).Error; err != nil {
+			return err
+		}
+		return refreshDestinationRating(tx, review.DestinationID)
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to moderate review"})
+	}
+
+	return respondReview(c, review.ID)
+}
+
+// refreshDestinationRating menghitung ulang rata-rata rating dan jumlah review yang tampil
+func refreshDestinationRating(tx *gorm.DB, destinationID uint) error {
+	var stats struct {
+		Average float64
+		Count   int
+	}
+	err := tx.Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("destination_id = ? AND status = ?", destinationID, models.ReviewVisible).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	// UpdateColumns supaya hook BeforeSave (eco-score) tidak ikut berjalan
+	return tx.Model(&models.Destination{}).Where("id = ?", destinationID).UpdateColumns(map[string]interface{}{
+		"average_rating": math.Round(stats.Average*100) / 100,
+		"review_count":   stats.Count,
+	}).Error
+}
+
+func toReviewPhotos(urls []string) []models.ReviewPhoto {
+	photos := make([]models.ReviewPhoto, 0, len(urls))
+	for _, url := range urls {
+		photos = append(photos, models.ReviewPhoto{URL: url})
+	}
+	return photos
+}
+
+func toReviewResponses(reviews []models.Review, viewer middlewares.AuthUser) []response.ReviewResponse {
+	responses := make([]response.ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		responses = append(responses, toReviewResponse(review, viewer))
+	}
+	return responses
+}
+
+// toReviewResponse hanya menampilkan alasan flag kepada admin
+func toReviewResponse(review models.Review, viewer middlewares.AuthUser) response.ReviewResponse {
+	photos := make([]string, 0, len(review.Photos))
+	for _, photo := range review.Photos {
+		photos = append(photos, photo.URL)
+	}
+
+	reviewResponse := response.ReviewResponse{
+		ID:            review.ID,
+		DestinationID: review.DestinationID,
+		User:          response.ReviewAuthor{ID: review.UserID, Username: review.User.Username},
+		Rating:        review.Rating,
+		Comment:       review.Comment,
+		Photos:        photos,
+		Status:        review.Status,
+		Flagged:       review.Flagged,
+		CreatedAt:     review.CreatedAt,
+		UpdatedAt:     review.UpdatedAt,
+	}
+	if viewer.IsAdmin() {
+		reviewResponse.FlagReason = review.FlagReason
+	}
+	return reviewResponse
+}
+
+func respondReview(c echo.Context, reviewID uint) error {
+	var review models.Review
+	if err := config.DB.Preload("User").Preload("Photos").First(&review, reviewID).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch review"})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+	return c.JSON(http.StatusOK, toReviewResponse(review, currentUser))
+}
+
+// deleteReviewsOf menghapus review (beserta fotonya) milik user atau destinasi lalu
+// memperbarui rating destinasi yang terdampak
+func deleteReviewsOf(tx *gorm.DB, column string, id uint) error {
+	var reviews []models.Review
+	if err := tx.Where(column+" = ?", id).Find(&reviews).Error; err != nil {
+		return err
+	}
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	reviewIDs := make([]uint, len(reviews))
+	destinationIDs := make(map[uint]bool)
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+		destinationIDs[review.DestinationID] = true
+	}
+
+	if err := tx.Where("review_id IN ?", reviewIDs).Delete(&models.ReviewPhoto{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("id IN ?", reviewIDs).Delete(&models.Review{}).Error; err != nil {
+		return err
+	}
+
+	if column == "destination_id" {
+		return nil
+	}
+	for destinationID := range destinationIDs {
+		if err := refreshDestinationRating(tx, destinationID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,24 +2,31 @@ package controllers
 
 import (
 	"backend/config"
+	"backend/emissions"
 	"backend/helper"
+	"backend/middlewares"
 	"backend/models"
 	"backend/request"
 	"backend/response"
 	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 // CreateRoute godoc
 // @Summary Create a new travel route
-// @Description Create a new route by specifying the origin and destination cities, and additional route details
+// @Description Create a new route for the authenticated user. The destinations are reordered into the shortest visiting order and the total distance is computed by the server. When destinationCityName is empty the route ends at the last destination.
 // @Tags Routes
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param input body request.CreateRouteInput true "Route details"
 // @Success 200 {object} models.Route
 // @Failure 400 {object} map[string]string "Invalid input"
@@ -31,67 +38,326 @@ func CreateRoute(c echo.Context) error {
 	if err := json.NewDecoder(c.Request().Body).Decode(jsonBody); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
 	}
+	if err := helper.ValidateInput(jsonBody); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
 
-	var originCity models.City
-	if err := config.DB.Where("name = ?", jsonBody.OriginCityName).First(&originCity).Error; err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Origin City not found"})
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	route, plan, err := newRoute(currentUser.ID, *jsonBody)
+	if err != nil {
+		return respondHTTPError(c, err)
 	}
 
-	var destinationCity models.City
-	if err := config.DB.Where("name = ?", jsonBody.DestinationCityName).First(&destinationCity).Error; err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Destination City not found"})
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return saveRoute(tx, &route, plan)
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create route"})
+	}
+
+	return c.JSON(http.StatusOK, route)
+}
+
+// newRoute menyusun rute (belum disimpan) dari input: urutan kunjungan terpendek,
+// jarak, dan jejak karbon. Error berupa *echo.HTTPError seperti planRoute.
+func newRoute(userID uint, input request.CreateRouteInput) (models.Route, response.OptimizedRouteResponse, error) {
+	plan, err := planRoute(input.OriginCityName, input.DestinationCityName, input.Destinations)
+	if err != nil {
+		return models.Route{}, plan, err
+	}
+
+	transportMode := input.TransportMode
+	if transportMode == "" {
+		transportMode = emissions.Car
+	}
+	travellers := input.Travellers
+	if travellers == 0 {
+		travellers = 1
+	}
+	carbon, err := config.Emissions.Estimate(transportMode, plan.Distance, travellers)
+	if err != nil {
+		return models.Route{}, plan, echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	route := models.Route{
-		UserID:              jsonBody.UserID,
-		OriginCityName:      originCity.Name,
-		DestinationCityName: destinationCity.Name,
-		Distance:            jsonBody.Distance,
-		Time:                jsonBody.Time,
-		Cost:                jsonBody.Cost,
+		UserID:              userID,
+		OriginCityName:      plan.OriginCityName,
+		DestinationCityName: plan.DestinationCityName,
+		Distance:            plan.Distance,
+		Time:                input.Time,
+		Cost:                input.Cost,
+		OpenEnded:           input.DestinationCityName == "",
+		TransportMode:       transportMode,
+		Travellers:          travellers,
+		CarbonKg:            carbon,
 	}
+	return route, plan, nil
+}
 
-	if err := config.DB.Create(&route).Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create route"})
+// saveRoute menyimpan rute beserta destinasinya sesuai urutan plan
+func saveRoute(tx *gorm.DB, route *models.Route, plan response.OptimizedRouteResponse) error {
+	if err := tx.Create(route).Error; err != nil {
+		return err
 	}
 
-	for i := 0; i < len(jsonBody.Destinations); i++ {
+	for _, stop := range plan.Stops {
 		routeDestination := models.RouteDestination{
 			RouteID:       route.ID,
-			DestinationID: jsonBody.Destinations[i],
+			DestinationID: stop.Destination.ID,
+			Sequence:      stop.Sequence,
 			CreatedAt:     time.Now(),
 		}
+		if err := tx.Create(&routeDestination).Error; err != nil {
+			return err
+		}
+		route.Destinations = append(route.Destinations, routeDestination)
+	}
+	return nil
+}
 
-		config.DB.Create(&routeDestination)
+// UpdateRouteTransport godoc
+// @Summary Change the transport mode of a route
+// @Description Set the transport mode and number of travellers and recompute the route's carbon footprint. Only the owner or an admin can change it.
+// @Tags Routes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Param input body request.RouteTransportInput true "Transport mode and travellers"
+// @Success 200 {object} response.RouteDetailResponse
+// @Failure 400 {object} map[string]interface{} "Invalid input"
+// @Failure 403 {object} map[string]interface{} "Route belongs to another user"
+// @Failure 404 {object} map[string]string "Route not found"
+// @Failure 500 {object} map[string]string "Failed to update route"
+// @Router /route/{id}/transport [put]
+func UpdateRouteTransport(c echo.Context) error {
+	route, err := ownedRoute(c)
+	if route == nil {
+		return err
 	}
 
-	return c.JSON(http.StatusOK, route)
+	var input request.RouteTransportInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(&input); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+	if !config.Emissions.ValidMode(input.TransportMode) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid transport mode"})
+	}
+
+	route.TransportMode = input.TransportMode
+	if input.Travellers != 0 {
+		route.Travellers = input.Travellers
+	}
+	if err := updateRouteCarbon(config.DB, route); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update route"})
+	}
+
+	return respondItinerary(c, config.DB, *route)
+}
+
+// updateRouteCarbon menghitung ulang jejak karbon dari jarak, mode, dan jumlah penumpang
+func updateRouteCarbon(tx *gorm.DB, route *models.Route) error {
+	carbon, err := config.Emissions.Estimate(route.TransportMode, route.Distance, route.Travellers)
+	if err != nil {
+		return err
+	}
+	route.CarbonKg = carbon
+
+	return tx.Model(route).Updates(map[string]interface{}{
+		"transport_mode": route.TransportMode,
+		"travellers":     route.Travellers,
+		"carbon_kg":      route.CarbonKg,
+	}).Error
 }
 
+// carbonAlternatives mengembalikan mode lain dengan emisi lebih rendah untuk rute ini
+func carbonAlternatives(route models.Route) []emissions.Alternative {
+	alternatives, err := config.Emissions.Alternatives(route.TransportMode, route.Distance, route.Travellers)
+	if err != nil {
+		return []emissions.Alternative{}
+	}
+	return alternatives
+}
+
+// OptimizeRoute godoc
+// @Summary Preview the optimal visiting order
+// @Description Order the given destinations into the shortest itinerary from the origin city, optionally ending at destinationCityName, without saving anything. Small sets are solved exactly; larger sets use nearest-neighbour with 2-opt.
+// @Tags Routes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body request.OptimizeRouteInput true "Origin, optional end city and destination IDs"
+// @Success 200 {object} response.OptimizedRouteResponse
+// @Failure 400 {object} map[string]string "Invalid input or missing coordinates"
+// @Failure 500 {object} map[string]string "Failed to optimize route"
+// @Router /route/optimize [post]
+func OptimizeRoute(c echo.Context) error {
+	jsonBody := new(request.OptimizeRouteInput)
+	if err := json.NewDecoder(c.Request().Body).Decode(jsonBody); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid JSON body"})
+	}
+	if err := helper.ValidateInput(jsonBody); err != nil {
+		errors := helper.FormatValidationError(err)
+		response := helper.APIResponse("Validation error", http.StatusBadRequest, "error", errors)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	plan, err := planRoute(jsonBody.OriginCityName, jsonBody.DestinationCityName, jsonBody.Destinations)
+	if err != nil {
+		return respondHTTPError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, plan)
+}
+
+// planRoute memuat kota dan destinasi lalu mengurutkannya menjadi rute terpendek.
+// Destinasi tanpa koordinat memakai koordinat kotanya. Error yang dikembalikan
+// berupa *echo.HTTPError supaya handler bisa meneruskan status dan pesannya.
+func planRoute(originCityName, destinationCityName string, destinationIDs []uint) (response.OptimizedRouteResponse, error) {
+	var plan response.OptimizedRouteResponse
+
+	var originCity models.City
+	if err := config.DB.Where("name = ?", originCityName).First(&originCity).Error; err != nil {
+		return plan, echo.NewHTTPError(http.StatusBadRequest, "Origin City not found")
+	}
+	lat, lon, ok := originCity.Coordinates()
+	if !ok {
+		return plan, echo.NewHTTPError(http.StatusBadRequest, "Origin City has no coordinates")
+	}
+	start := helper.GeoPoint{Lat: lat, Lon: lon}
+
+	var end *helper.GeoPoint
+	if destinationCityName != "" {
+		var destinationCity models.City
+		if err := config.DB.Where("name = ?", destinationCityName).First(&destinationCity).Error; err != nil {
+			return plan, echo.NewHTTPError(http.StatusBadRequest, "Destination City not found")
+		}
+		lat, lon, ok := destinationCity.Coordinates()
+		if !ok {
+			return plan, echo.NewHTTPError(http.StatusBadRequest, "Destination City has no coordinates")
+		}
+		end = &helper.GeoPoint{Lat: lat, Lon: lon}
+	}
+
+	// Buang ID duplikat tanpa mengubah urutan input
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, id := range destinationIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var destinations []models.Destination
+	if len(ids) > 0 {
+		if err := config.DB.Preload("City").Where("id IN ?", ids).Order("id").Find(&destinations).Error; err != nil {
+			return plan, echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch destinations")
+		}
+	}
+	if len(destinations) != len(ids) {
+		return plan, echo.NewHTTPError(http.StatusBadRequest, "Destination not found")
+	}
+
+	stops := make([]helper.GeoPoint, len(destinations))
+	for i, destination := range destinations {
+		point, ok := destinationPoint(destination)
+		if !ok {
+			return plan, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Destination %d has no coordinates", destination.ID))
+		}
+		stops[i] = point
+	}
+
+	order, distance := helper.OptimizeRoute(start, end, stops)
+	legs := helper.RouteLegs(start, end, stops, order)
+
+	plan.OriginCityName = originCity.Name
+	plan.DestinationCityName = destinationCityName
+	plan.Distance = roundKm(distance)
+	plan.Stops = make([]response.RouteStop, len(order))
+	for i, index := range order {
+		plan.Stops[i] = response.RouteStop{
+			Sequence:    i + 1,
+			LegDistance: roundKm(legs[i]),
+			Destination: destinations[index],
+		}
+	}
+	if end != nil {
+		plan.FinalLegDistance = roundKm(legs[len(legs)-1])
+	}
+
+	// Rute terbuka berakhir di kota destinasi terakhir
+	if plan.DestinationCityName == "" {
+		plan.DestinationCityName = originCity.Name
+		if len(plan.Stops) > 0 {
+			plan.DestinationCityName = plan.Stops[len(plan.Stops)-1].Destination.City.Name
+		}
+	}
+
+	return plan, nil
+}
+
+// destinationPoint mengambil koordinat destinasi, atau koordinat kotanya jika kosong.
+// City harus sudah di-preload.
+func destinationPoint(destination models.Destination) (helper.GeoPoint, bool) {
+	lat, lon, ok := destination.Coordinates()
+	if !ok {
+		lat, lon, ok = destination.City.Coordinates()
+	}
+	return helper.GeoPoint{Lat: lat, Lon: lon}, ok
+}
+
+// respondHTTPError menulis *echo.HTTPError sebagai {"message": ...}
+func respondHTTPError(c echo.Context, err error) error {
+	if he, ok := err.(*echo.HTTPError); ok {
+		return c.JSON(he.Code, map[string]interface{}{"message": he.Message})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+}
+
+func roundKm(km float64) float64 {
+	return math.Round(km*100) / 100
+}
+
+// calculateDistance menghitung jarak (km) antar kota; 0 jika koordinat belum diisi
 func calculateDistance(originCity models.City, destinationCity models.City) float64 {
-	lat1, _ := strconv.ParseFloat(originCity.Lat, 64)
-	lon1, _ := strconv.ParseFloat(originCity.Long, 64)
-	lat2, _ := strconv.ParseFloat(destinationCity.Lat, 64)
-	lon2, _ := strconv.ParseFloat(destinationCity.Long, 64)
+	lat1, lon1, ok1 := originCity.Coordinates()
+	lat2, lon2, ok2 := destinationCity.Coordinates()
+	if !ok1 || !ok2 {
+		return 0
+	}
 
 	return helper.Haversine(lat1, lon1, lat2, lon2)
 }
 
 // GetRouteByUser godoc
 // @Summary Get all routes by user
-// @Description Fetch all routes created by a specific user
+// @Description Fetch routes created by the authenticated user, newest first, one page at a time
 // @Tags Routes
 // @Accept json
 // @Produce json
-// @Param user_id query string true "User ID"
+// @Security BearerAuth
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 50)"
+// @Param cursor query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Invalid pagination"
 // @Failure 401 {object} map[string]string "User not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /route [get]
 func GetRouteByUser(c echo.Context) error {
 	var routes []models.Route
 
-	userID := c.QueryParam("user_id")
+	currentUser, _ := middlewares.CurrentUser(c)
+	userID := currentUser.ID
 
 	var user models.User
 	result := config.DB.First(&user, "id = ?", userID)
@@ -100,26 +366,49 @@ func GetRouteByUser(c echo.Context) error {
 		return c.JSON(http.StatusUnauthorized, response)
 	}
 
-	err := config.DB.Where("user_id = ?", userID).Find(&routes).Error
+	pageQuery, err := helper.ParsePageQuery(c, 20, 50)
 	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
 
+	query := config.DB.Model(&models.Route{}).Where("user_id = ?", userID)
+	meta, err := helper.Paginate(query, pageQuery, "id", helper.Sort{Desc: true}, &routes, "Destinations")
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
 	}
 
+	// Ambil semua destinasi untuk halaman ini dalam satu query
+	var destinationIDs []uint
+	for _, route := range routes {
+		for _, routeDestination := range route.Destinations {
+			destinationIDs = append(destinationIDs, routeDestination.DestinationID)
+		}
+	}
+
+	destinationsByID := make(map[uint]models.Destination)
+	if len(destinationIDs) > 0 {
+		var destinations []models.Destination
+		if err := config.DB.Where("id IN ?", destinationIDs).Find(&destinations).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		}
+		for _, destination := range destinations {
+			destinationsByID[destination.ID] = destination
+		}
+	}
+
 	var responses []response.RouteResponse
 
 	for i := 0; i < len(routes); i++ {
-		var routeDestinations []models.RouteDestination
-		config.DB.Where("route_id = ?", routes[i].ID).Find(&routeDestinations)
-
 		var destinations []models.Destination
 
-		for j := 0; j < len(routeDestinations); j++ {
-			var destination models.Destination
-			config.DB.Where("id = ?", routeDestinations[j].DestinationID).Find(&destination)
+		sort.SliceStable(routes[i].Destinations, func(a, b int) bool {
+			return routes[i].Destinations[a].Sequence < routes[i].Destinations[b].Sequence
+		})
 
-			println(destination.Name)
-			destinations = append(destinations, destination)
+		for _, routeDestination := range routes[i].Destinations {
+			if destination, ok := destinationsByID[routeDestination.DestinationID]; ok {
+				destinations = append(destinations, destination)
+			}
 		}
 
 		var response = response.RouteResponse{
@@ -130,6 +419,10 @@ func GetRouteByUser(c echo.Context) error {
 			Distance:            routes[i].Distance,
 			Time:                routes[i].Time,
 			Cost:                routes[i].Cost,
+			TransportMode:       routes[i].TransportMode,
+			Travellers:          routes[i].Travellers,
+			CarbonKg:            routes[i].CarbonKg,
+			CarbonAlternatives:  carbonAlternatives(routes[i]),
 			CreatedAt:           routes[i].CreatedAt,
 			Destinations:        destinations,
 		}
@@ -140,18 +433,21 @@ func GetRouteByUser(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Routes fetched successfully",
 		"data":    responses,
+		"meta":    meta,
 	})
 }
 
 // DeleteRoute godoc
 // @Summary Delete a specific route
-// @Description Delete a route by its ID, including all related destinations
+// @Description Delete a route by its ID, including all related destinations. Only the owner or an admin can delete it.
 // @Tags Routes
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Route ID"
 // @Success 200 {object} map[string]string "Route successfully deleted"
 // @Failure 400 {object} map[string]string "Invalid route ID"
+// @Failure 403 {object} map[string]interface{} "Route belongs to another user"
 // @Failure 404 {object} map[string]string "Route not found"
 // @Failure 500 {object} map[string]string "Failed to delete route"
 // @Router /route/{id} [delete]
@@ -167,6 +463,11 @@ func DeleteRoute(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, map[string]string{"message": "Route not found"})
 	}
 
+	currentUser, _ := middlewares.CurrentUser(c)
+	if !middlewares.CanActOn(currentUser, route.UserID) {
+		return middlewares.Forbidden(c, "Access forbidden: you can only manage your own routes")
+	}
+
 	tx := config.DB.Begin()
 
 	if err := tx.Where("route_id = ?", route.ID).Delete(&route.Destinations).Error; err != nil {
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/middlewares"
+	"backend/models"
+	"backend/request"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// errDraftNotPending menandai draft yang sudah dikonfirmasi saat transaksi berjalan
+var errDraftNotPending = errors.New("route draft is not pending")
+
+// GetRouteDrafts godoc
+// @Summary List route drafts
+// @Description Fetch the route drafts the chat assistant proposed to the authenticated user that have not been confirmed yet, newest first
+// @Tags Routes
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Invalid pagination"
+// @Failure 500 {object} map[string]string "Failed to fetch route drafts"
+// @Router /route/drafts [get]
+func GetRouteDrafts(c echo.Context) error {
+	pageQuery, err := helper.ParsePageQuery(c, 20, 50)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	var drafts []models.RouteDraft
+	query := config.DB.Model(&models.RouteDraft{}).Where("user_id = ? AND status = ?", currentUser.ID, models.RouteDraftPending)
+	meta, err := helper.Paginate(query, pageQuery, "id", helper.Sort{Desc: true}, &drafts)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch route drafts"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Route drafts fetched successfully",
+		"data":    toRouteDrafts(drafts),
+		"meta":    meta,
+	})
+}
+
+// ConfirmRouteDraft godoc
+// @Summary Save a route draft as a route
+// @Description Confirm a route draft proposed by the chat assistant. The route is planned again from the draft's cities and destinations, so it reflects the current catalogue, and saved like POST /route. A draft can only be confirmed once.
+// @Tags Routes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route draft ID"
+// @Success 200 {object} models.Route
+// @Failure 400 {object} map[string]string "Draft can no longer be planned"
+// @Failure 404 {object} map[string]string "Route draft not found"
+// @Failure 409 {object} map[string]string "Route draft already confirmed"
+// @Failure 500 {object} map[string]string "Failed to save route"
+// @Router /route/drafts/{id}/confirm [post]
+func ConfirmRouteDraft(c echo.Context) error {
+	draft, err := ownedRouteDraft(c)
+	if draft == nil {
+		return err
+	}
+	if draft.Status != models.RouteDraftPending {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Route draft already confirmed"})
+	}
+
+	input := request.CreateRouteInput{
+		OriginCityName: draft.OriginCityName,
+		TransportMode:  draft.TransportMode,
+		Travellers:     draft.Travellers,
+	}
+	if !draft.OpenEnded {
+		input.DestinationCityName = draft.DestinationCityName
+	}
+	for _, stop := range draft.Stops {
+		input.Destinations = append(input.Destinations, stop.DestinationID)
+	}
+
+	route, plan, err := newRoute(draft.UserID, input)
+	if err != nil {
+		return respondHTTPError(c, err)
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveRoute(tx, &route, plan); err != nil {
+			return err
+		}
+		// Status ikut dicek supaya konfirmasi ganda yang bersamaan hanya membuat satu rute
+		result := tx.Model(&models.RouteDraft{}).Where("id = ? AND status = ?", draft.ID, models.RouteDraftPending).
+			Updates(map[string]interface{}{"status": models.RouteDraftConfirmed, "route_id": route.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errDraftNotPending
+		}
+		return nil
+	})
+	if errors.Is(err, errDraftNotPending) {
+		return c.JSON(http.StatusConflict, map[string]string{"message": "Route draft already confirmed"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to save route"})
+	}
+
+	return c.JSON(http.StatusOK, route)
+}
+
+// DeleteRouteDraft godoc
+// @Summary Discard a route draft
+// @Description Delete one of your route drafts. A route already saved from it is not affected.
+// @Tags Routes
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route draft ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "Route draft not found"
+// @Failure 500 {object} map[string]string "Failed to delete route draft"
+// @Router /route/drafts/{id} [delete]
+func DeleteRouteDraft(c echo.Context) error {
+	draft, err := ownedRouteDraft(c)
+	if draft == nil {
+		return err
+	}
+
+	if err := config.DB.Delete(draft).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete route draft"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Route draft deleted successfully"})
+}
+
+// ownedRouteDraft mengambil draft dari parameter :id milik user yang login. Draft
+// user lain dianggap tidak ada. Jika hasilnya nil, response sudah ditulis.
+func ownedRouteDraft(c echo.Context) (*models.RouteDraft, error) {
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	var draft models.RouteDraft
+	err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), currentUser.ID).First(&draft).Error
+	if err != nil {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"message": "Route draft not found"})
+	}
+	return &draft, nil
+}
+
+// toRouteDrafts memastikan daftar kosong ditulis sebagai [] di JSON
+func toRouteDrafts(drafts []models.RouteDraft) []models.RouteDraft {
+	if drafts == nil {
+		return []models.RouteDraft{}
+	}
+	return drafts
+}
@@ -3,15 +3,15 @@ package controllers
 import (
 	"backend/config"
 	"backend/helper"
+	"backend/middlewares"
 	"backend/models"
 	"backend/response"
-	"io"
 	"log"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 )
@@ -71,7 +71,7 @@ func LoginHandler(c echo.Context) error {
 	}
 
 	// Generate token JWT
-	token, err := helper.GenerateJWT(user.ID, user.Username, user.Role)
+	token, refreshToken, err := issueTokens(config.DB, user, "")
 	if err != nil {
 		response := helper.APIResponse("Failed to generate token", http.StatusInternalServerError, "error", nil)
 		return c.JSON(http.StatusInternalServerError, response)
@@ -80,45 +80,30 @@ func LoginHandler(c echo.Context) error {
 	var file string
 
 	if user.File != "" {
-		file = os.Getenv("APP_BASE") + "/" + user.File
+		file = fileURL(user.File)
 	}
 
 	// Response data
 	data := map[string]interface{}{
-		"id_user":      user.ID,
-		"username":     user.Username,
-		"first_name":   user.FirstName,
-		"last_name":    user.LastName,
-		"email":        user.Email,
-		"city":         user.City,
-		"role":         user.Role,
-		"token":        token,
-		"file":         file,
-		"phone_number": user.PhoneNumber,
-		"gender":       user.Gender,
+		"id_user":        user.ID,
+		"username":       user.Username,
+		"first_name":     user.FirstName,
+		"last_name":      user.LastName,
+		"email":          user.Email,
+		"city":           user.City,
+		"role":           user.Role,
+		"token":          token,
+		"refresh_token":  refreshToken,
+		"file":           file,
+		"phone_number":   user.PhoneNumber,
+		"gender":         user.Gender,
+		"email_verified": user.IsEmailVerified(),
 	}
 
 	response := helper.APIResponse("Login successful", http.StatusOK, "success", data)
 	return c.JSON(http.StatusOK, response)
 }
 
-// LogoutHandler godoc
-// @Summary Log out a user
-// @Description Process the logout request and return a success message
-// @Tags User
-// @Accept json
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Router /logout [get]
-func LogoutHandler(c echo.Context) error {
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Berhasil Logout",
-	})
-}
-
 // Struct untuk validasi input registrasi
 type RegisterInput struct {
 	Username  string `json:"username" validate:"required,alphanum"`
@@ -132,13 +117,14 @@ type RegisterInput struct {
 
 // RegisterHandler godoc
 // @Summary User registration
-// @Description Handle user registration by validating input and creating a new user in the database.
+// @Description Handle user registration by validating input and creating a new, unverified user in the database. A verification link is sent by email; until it is opened the returned token only grants limited access. Registering as admin is not allowed.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param input body RegisterInput true "Registration details"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /register [post]
 // RegisterHandler menangani proses registrasi
@@ -159,18 +145,18 @@ func RegisterHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response)
 	}
 
+	// Akun admin hanya bisa dibuat oleh admin lain
+	if input.Role == "admin" {
+		return middlewares.Forbidden(c, "Access forbidden: registering as admin is not allowed")
+	}
+
 	hashedPassword, err := helper.HashPassword(input.Password)
 	if err != nil {
 		response := helper.APIResponse("Failed to hash password", http.StatusInternalServerError, "error", nil)
 		return c.JSON(http.StatusInternalServerError, response)
 	}
 
-	var role string
-	if input.Role == "" {
-		role = "user"
-	} else {
-		role = input.Role
-	}
+	role := "user"
 
 	// Create user object
 	user := models.User{
@@ -192,8 +178,13 @@ func RegisterHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, response)
 	}
 
+	// Gagal kirim email tidak membatalkan registrasi; user bisa minta kirim ulang
+	if err := sendVerificationEmail(c.Request().Context(), user); err != nil {
+		log.Println("Failed to send verification email:", err)
+	}
+
 	// Generate JWT token
-	token, err := helper.GenerateJWT(user.ID, user.Username, user.Role)
+	token, refreshToken, err := issueTokens(config.DB, user, "")
 	if err != nil {
 		response := helper.APIResponse("Failed to generate token", http.StatusInternalServerError, "error", nil)
 		return c.JSON(http.StatusInternalServerError, response)
@@ -201,34 +192,36 @@ func RegisterHandler(c echo.Context) error {
 
 	// Response data
 	data := map[string]interface{}{
-		"id_user":      user.ID,
-		"username":     user.Username,
-		"first_name":   user.FirstName,
-		"last_name":    user.LastName,
-		"email":        user.Email,
-		"city":         user.City,
-		"role":         user.Role,
-		"file":         user.File,
-		"token":        token,
-		"phone_number": user.PhoneNumber,
-		"gender":       user.Gender,
-	}
-
-	response := helper.APIResponse("Registration successful", http.StatusOK, "success", data)
+		"id_user":        user.ID,
+		"username":       user.Username,
+		"first_name":     user.FirstName,
+		"last_name":      user.LastName,
+		"email":          user.Email,
+		"city":           user.City,
+		"role":           user.Role,
+		"file":           fileURL(user.File),
+		"token":          token,
+		"refresh_token":  refreshToken,
+		"phone_number":   user.PhoneNumber,
+		"gender":         user.Gender,
+		"email_verified": user.IsEmailVerified(),
+	}
+
+	response := helper.APIResponse("Registration successful, please check your email to verify your account", http.StatusOK, "success", data)
 	return c.JSON(http.StatusOK, response)
 }
 
 type UserCategoryInput struct {
-	UserID   int      `json:"userID"`
 	Category []string `json:"category"`
 }
 
 // CreateUserCategoryHandler godoc
 // @Summary Create or update user categories
-// @Description Assign categories to a user by updating their profile.
+// @Description Assign categories to the authenticated user by updating their profile.
 // @Tags User
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param input body UserCategoryInput true "User categories"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
@@ -243,8 +236,10 @@ func CreateUserCategoryHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response)
 	}
 
+	currentUser, _ := middlewares.CurrentUser(c)
+
 	var user models.User
-	result := config.DB.First(&user, "id = ?", input.UserID)
+	result := config.DB.First(&user, "id = ?", currentUser.ID)
 	if result.Error != nil || user.ID == 0 {
 		response := helper.APIResponse("User not found", http.StatusUnauthorized, "error", nil)
 		return c.JSON(http.StatusUnauthorized, response)
@@ -270,7 +265,11 @@ func CreateUserCategoryHandler(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param name query string false "Search by first or last name"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Opaque cursor from meta.next_cursor; send empty to start cursor mode"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /user/{id} [get]
 func GetAllUserHandler(c echo.Context) error {
@@ -283,13 +282,21 @@ func GetAllUserHandler(c echo.Context) error {
 
 	queryName := c.QueryParam("name")
 
-	query := config.DB
+	pageQuery, err := helper.ParsePageQuery(c, 20, 100)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	query := config.DB.Model(&models.User{})
 
 	if queryName != "" {
 		query = query.Where("first_name LIKE ? OR last_name LIKE ?", "%"+queryName+"%", "%"+queryName+"%")
 	}
 
-	query.Find(&users)
+	meta, err := helper.Paginate(query, pageQuery, "id", helper.Sort{}, &users)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch users"})
+	}
 
 	var responses []response.UserResponse
 
@@ -297,7 +304,7 @@ func GetAllUserHandler(c echo.Context) error {
 		var file string
 
 		if users[i].File != "" {
-			file = os.Getenv("APP_BASE") + "/" + users[i].File
+			file = fileURL(users[i].File)
 		} else {
 			file = "https://static-00.iconduck.com/assets.00/profile-default-icon-2048x2045-u3j7s5nj.png"
 		}
@@ -314,6 +321,8 @@ func GetAllUserHandler(c echo.Context) error {
 			File:        file,
 			PhoneNumber: users[i].PhoneNumber,
 			Gender:      users[i].Gender,
+
+			EmailVerified: users[i].IsEmailVerified(),
 		}
 
 		responses = append(responses, response)
@@ -322,6 +331,7 @@ func GetAllUserHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Users fetched successfully",
 		"data":    responses,
+		"meta":    meta,
 	})
 }
 
@@ -346,7 +356,7 @@ func GetDetailUserHandler(c echo.Context) error {
 	var file string
 
 	if user.File != "" {
-		file = os.Getenv("APP_BASE") + "/" + user.File
+		file = fileURL(user.File)
 	} else {
 		file = "https://static-00.iconduck.com/assets.00/profile-default-icon-2048x2045-u3j7s5nj.png"
 	}
@@ -362,6 +372,8 @@ func GetDetailUserHandler(c echo.Context) error {
 		File:        file,
 		PhoneNumber: user.PhoneNumber,
 		Gender:      user.Gender,
+
+		EmailVerified: user.IsEmailVerified(),
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -372,10 +384,11 @@ func GetDetailUserHandler(c echo.Context) error {
 
 // EditUserHandler godoc
 // @Summary Edit user profile
-// @Description Update user information such as username, email, password, and categories.
+// @Description Update user information such as username, email, password, and categories. Users can only edit their own account; only admins can change roles.
 // @Tags User
 // @Accept multipart/form-data
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "User ID"
 // @Param username formData string false "Username"
 // @Param first_name formData string false "First Name"
@@ -389,6 +402,7 @@ func GetDetailUserHandler(c echo.Context) error {
 // @Param file formData file false "Profile Image"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /users/{id} [put]
@@ -418,6 +432,11 @@ func EditUserHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "All fields except password are required"})
 	}
 
+	// Aturan yang sama dengan RegisterInput, supaya email verifikasi tidak dikirim ke alamat yang salah ketik
+	if err := validator.New().Var(email, "email"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Email is not valid"})
+	}
+
 	var existUserByUsername models.User
 	config.DB.Where("username = ? AND id != ?", username, id).Find(&existUserByUsername)
 
@@ -432,6 +451,12 @@ func EditUserHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Email already used"})
 	}
 
+	// Email baru harus diverifikasi ulang
+	emailChanged := email != user.Email
+	if emailChanged {
+		user.EmailVerifiedAt = nil
+	}
+
 	// Update optional fields
 	user.Username = username
 	user.FirstName = firstName
@@ -441,35 +466,28 @@ func EditUserHandler(c echo.Context) error {
 	user.PhoneNumber = phoneNumber
 	user.Gender = gender
 
-	// Handle role (optional, default to existing)
-	if role != "" {
+	// Handle role (optional, default to existing). Only admins may change roles.
+	if role != "" && role != user.Role {
+		currentUser, _ := middlewares.CurrentUser(c)
+		if !middlewares.CanChangeRole(currentUser) {
+			return middlewares.Forbidden(c, "Access forbidden: only admins can change roles")
+		}
+		if role != "admin" && role != "user" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Role must be admin or user"})
+		}
 		user.Role = role
 	}
 
 	// Handle file upload (optional)
 	file, err := c.FormFile("file")
 	if err == nil {
-		src, err := file.Open()
+		uploaded, err := config.Uploads.SaveFile(c.Request().Context(), file)
 		if err != nil {
-			response := helper.APIResponse("Failed to process file", http.StatusInternalServerError, "error", nil)
-			return c.JSON(http.StatusInternalServerError, response)
-		}
-		defer src.Close()
-
-		// Save uploaded file
-		filePath := "assets/" + file.Filename
-		dst, err := os.Create(filePath)
-		if err != nil {
-			response := helper.APIResponse("Failed to save file", http.StatusInternalServerError, "error", nil)
-			return c.JSON(http.StatusInternalServerError, response)
+			status, message := uploadErrorStatus(err)
+			response := helper.APIResponse(message, status, "error", nil)
+			return c.JSON(status, response)
 		}
-		defer dst.Close()
-
-		if _, err := io.Copy(dst, src); err != nil {
-			response := helper.APIResponse("Failed to save file", http.StatusInternalServerError, "error", nil)
-			return c.JSON(http.StatusInternalServerError, response)
-		}
-		user.File = filePath
+		user.File = uploaded.Key
 	}
 
 	// Hash password (if provided)
@@ -488,11 +506,22 @@ func EditUserHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, response)
 	}
 
-	// Generate a new token
-	token, err := helper.GenerateJWT(user.ID, user.Username, user.Role)
-	if err != nil {
-		response := helper.APIResponse("Failed to generate token", http.StatusInternalServerError, "error", nil)
-		return c.JSON(http.StatusInternalServerError, response)
+	if emailChanged {
+		if err := sendVerificationEmail(c.Request().Context(), user); err != nil {
+			log.Println("Failed to send verification email:", err)
+		}
+	}
+
+	// Generate a new access token for the current session. Admins editing
+	// another account keep their own session and get no token for it.
+	var token string
+	currentUser, _ := middlewares.CurrentUser(c)
+	if currentUser.ID == user.ID {
+		token, err = helper.GenerateJWT(user.ID, user.Username, user.Role, currentUser.SessionID, user.IsEmailVerified())
+		if err != nil {
+			response := helper.APIResponse("Failed to generate token", http.StatusInternalServerError, "error", nil)
+			return c.JSON(http.StatusInternalServerError, response)
+		}
 	}
 
 	// Prepare response data
@@ -504,7 +533,7 @@ func EditUserHandler(c echo.Context) error {
 		"email":        user.Email,
 		"city":         user.City,
 		"role":         user.Role,
-		"file":         user.File,
+		"file":         fileURL(user.File),
 		"token":        token,
 		"phone_number": user.PhoneNumber,
 		"gender":       user.Gender,
@@ -528,6 +557,31 @@ func DeleteUser(c echo.Context) error {
 
 	tx := config.DB.Begin()
 
+	if err := deleteReviewsOf(tx, "user_id", user.ID); err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete user reviews"})
+	}
+
+	if err := tx.Where("user_id = ?", user.ID).Delete(&models.Favorite{}).Error; err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete user favorites"})
+	}
+
+	if err := deleteConversationsOf(tx, user.ID); err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete user conversations"})
+	}
+
+	if err := tx.Where("user_id = ?", user.ID).Delete(&models.RouteDraft{}).Error; err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete user route drafts"})
+	}
+
+	if err := deleteChatUsageOf(tx, user.ID); err != nil {
+		tx.Rollback()
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete user chat usage"})
+	}
+
 	if err := tx.Delete(&user).Error; err != nil {
 		tx.Rollback()
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to delete user"})
@@ -544,11 +598,13 @@ func DeleteUser(c echo.Context) error {
 // @Tags User
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "User ID"
 // @Param input body ChangePasswordInput true "Change Password Payload"
 // @Success 200 {object} map[string]string "User successfully updated"
 // @Failure 400 {object} map[string]string "Invalid request or validation error"
 // @Failure 401 {object} map[string]string "Incorrect password"
+// @Failure 403 {object} map[string]interface{} "Not the account owner"
 // @Failure 404 {object} map[string]string "User not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users/change-password/{id} [put]
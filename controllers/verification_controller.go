@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/mailer"
+	"backend/middlewares"
+	"backend/models"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// emailVerificationTTL adalah masa berlaku link verifikasi (EMAIL_VERIFICATION_TTL, default 48 jam)
+func emailVerificationTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 48 * time.Hour
+}
+
+// sendVerificationEmail membuat token verifikasi baru untuk user dan mengirimkannya lewat email
+func sendVerificationEmail(ctx context.Context, user models.User) error {
+	token, err := helper.RandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	ttl := emailVerificationTTL()
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Hanya link terakhir yang berlaku
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.EmailVerificationToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: helper.HashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	link := os.Getenv("APP_BASE") + "/verify-email?token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your TripWise email",
+		Body: fmt.Sprintf("Hi %s,\n\nWelcome to TripWise! Please confirm your email address by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s.\n",
+			user.FirstName, link, ttl),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	return config.Mailer.Send(ctx, msg)
+}
+
+// VerifyEmailHandler godoc
+// @Summary Verify email address
+// @Description Mark the account's email as verified using the token from the verification email. Refresh or log in again to get a token with full access.
+// @Tags User
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /verify-email [get]
+func VerifyEmailHandler(c echo.Context) error {
+	invalid := helper.APIResponse("Invalid or expired verification token", http.StatusBadRequest, "error", nil)
+
+	token := c.QueryParam("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+
+	var verification models.EmailVerificationToken
+	if err := config.DB.First(&verification, "token_hash = ?", helper.HashToken(token)).Error; err != nil {
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+	if verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&models.EmailVerificationToken{}).
+			Where("id = ? AND used_at IS NULL", verification.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ? AND email_verified_at IS NULL", verification.UserID).
+			Update("email_verified_at", now).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+	if err != nil {
+		response := helper.APIResponse("Failed to verify email", http.StatusInternalServerError, "error", nil)
+		return c.JSON(http.StatusInternalServerError, response)
+	}
+
+	response := helper.APIResponse("Email verified successfully", http.StatusOK, "success", nil)
+	return c.JSON(http.StatusOK, response)
+}
+
+// ResendVerificationHandler godoc
+// @Summary Resend verification email
+// @Description Send a new verification link to the authenticated user's email address
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /verify-email/resend [post]
+func ResendVerificationHandler(c echo.Context) error {
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	var user models.User
+	if err := config.DB.First(&user, currentUser.ID).Error; err != nil {
+		response := helper.APIResponse("User not found", http.StatusUnauthorized, "error", nil)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+
+	if user.IsEmailVerified() {
+		response := helper.APIResponse("Email is already verified", http.StatusBadRequest, "error", nil)
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	if err := sendVerificationEmail(c.Request().Context(), user); err != nil {
+		response := helper.APIResponse("Failed to send verification email", http.StatusInternalServerError, "error", nil)
+		return c.JSON(http.StatusInternalServerError, response)
+	}
+
+	response := helper.APIResponse("Verification email sent", http.StatusOK, "success", nil)
+	return c.JSON(http.StatusOK, response)
+}
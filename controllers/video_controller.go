@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/middlewares"
+	"backend/models"
+	"backend/response"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// videoViewWindow adalah panjang jendela waktu tetap di mana view berulang dari user yang
+// sama untuk video yang sama hanya dihitung sekali (VIDEO_VIEW_DEDUP_WINDOW, default 30
+// menit, minimal 1 detik)
+func videoViewWindow() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("VIDEO_VIEW_DEDUP_WINDOW")); err == nil && d >= time.Second {
+		return d
+	}
+	return 30 * time.Minute
+}
+
+// RecordVideoView godoc
+// @Summary Record a video view
+// @Description Record that the authenticated user watched a video. Views are grouped into fixed time buckets of VIDEO_VIEW_DEDUP_WINDOW (default 30m); repeated views by the same user within one bucket are counted once.
+// @Tags Video
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Video content ID"
+// @Success 200 {object} map[string]interface{} "counted is false when the view was deduplicated"
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /video-content/{id}/view [post]
+func RecordVideoView(c echo.Context) error {
+	var video models.VideoContent
+	if err := config.DB.First(&video, "id = ?", c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "Video content not found"})
+	}
+
+	currentUser, _ := middlewares.CurrentUser(c)
+
+	// Setiap view masuk ke satu jendela waktu tetap. Unique index (video, user, jendela)
+	// membuat request bersamaan hanya bisa mencatat satu view per jendela; sisanya
+	// diabaikan oleh ON CONFLICT DO NOTHING
+	bucket := time.Now().Unix() / int64(videoViewWindow().Seconds())
+	view := models.VideoContentView{
+		VideoContentID: video.ID,
+		UserID:         currentUser.ID,
+		DestinationID:  video.DestinationID,
+		WindowBucket:   &bucket,
+	}
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&view)
+	if result.Error != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to record view"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "View recorded",
+		"counted": result.RowsAffected > 0,
+	})
+}
+
+// GetMostViewedVideoContent godoc
+// @Summary Get most viewed videos and destinations
+// @Description Rank videos and destinations by the number of views within a time window
+// @Tags Video
+// @Produce json
+// @Param window query string false "Time window: 24h, 7d, 30d, ... or all (default all)"
+// @Param limit query int false "Maximum entries per ranking (default 10, max 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /video-content/most [get]
+func GetMostViewedVideoContent(c echo.Context) error {
+	window := c.QueryParam("window")
+	if window == "" {
+		window = "all"
+	}
+	since, err := parseViewWindow(window)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "window must be all or a duration such as 24h, 7d or 30d"})
+	}
+
+	limit := 10
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 50 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "limit must be between 1 and 50"})
+		}
+	}
+
+	views := config.DB.Model(&models.VideoContentView{})
+	if !since.IsZero() {
+		views = views.Where("video_content_views.created_at >= ?", since)
+	}
+
+	videos := []response.VideoViewRanking{}
+	err = views.Session(&gorm.Session{}).
+		Select("video_contents.id, video_contents.title, video_contents.url, video_contents.destination_id, " +
+			"destinations.name AS destination_name, COUNT(video_content_views.id) AS view_count").
+		Joins("JOIN video_contents ON video_contents.id = video_content_views.video_content_id").
+		Joins("JOIN destinations ON destinations.id = video_contents.destination_id").
+		Group("video_contents.id, video_contents.title, video_contents.url, video_contents.destination_id, destinations.name").
+		Order("view_count DESC, video_contents.id").
+		Limit(limit).
+		Scan(&videos).Error
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch data"})
+	}
+
+	destinations := []response.DestinationViewRanking{}
+	err = views.Session(&gorm.Session{}).
+		Select("destinations.id, destinations.name, destinations.address, destinations.description, " +
+			"COUNT(video_content_views.id) AS view_count").
+		Joins("JOIN destinations ON destinations.id = video_content_views.destination_id").
+		Group("destinations.id, destinations.name, destinations.address, destinations.description").
+		Order("view_count DESC, destinations.id").
+		Limit(limit).
+		Scan(&destinations).Error
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch data"})
+	}
+
+	// Ambil video semua destinasi di ranking dalam satu query
+	if len(destinations) > 0 {
+		ids := make([]uint, len(destinations))
+		for i, destination := range destinations {
+			ids[i] = destination.ID
+		}
+
+		var destinationVideos []models.VideoContent
+		if err := config.DB.Where("destination_id IN ?", ids).Find(&destinationVideos).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to fetch data"})
+		}
+
+		videosByDestination := make(map[uint][]response.VideoContent)
+		for _, video := range destinationVideos {
+			videosByDestination[video.DestinationID] = append(videosByDestination[video.DestinationID], response.VideoContent{
+				DestinationID: video.DestinationID,
+				Title:         video.Title,
+				URL:           video.URL,
+				Description:   video.Description,
+			})
+		}
+		for i := range destinations {
+			destinations[i].Videos = videosByDestination[destinations[i].ID]
+			if destinations[i].Videos == nil {
+				destinations[i].Videos = []response.VideoContent{}
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Most viewed content fetched successfully",
+		"data": map[string]interface{}{
+			"window":       window,
+			"videos":       videos,
+			"destinations": destinations,
+		},
+	})
+}
+
+var errInvalidWindow = errors.New("invalid window")
+
+// parseViewWindow mengubah "all", "7d", "24h", dst. menjadi batas waktu awal;
+// waktu nol berarti tanpa batas
+func parseViewWindow(window string) (time.Time, error) {
+	if window == "all" {
+		return time.Time{}, nil
+	}
+
+	var d time.Duration
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return time.Time{}, errInvalidWindow
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(window)
+		if err != nil || parsed <= 0 {
+			return time.Time{}, errInvalidWindow
+		}
+		d = parsed
+	}
+
+	return time.Now().Add(-d), nil
+}
@@ -26,7 +26,12 @@ const docTemplate = `{
     "paths": {
         "/chat": {
             "post": {
-                "description": "Process a chat message and get a response from Gemini API",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a message to the travel assistant. Without conversation_id a new conversation is started; with it the previous turns are sent to the model as context. Destinations and cities from the catalogue that match the question are given to the model, and the ones the reply refers to are returned as citations. The model can call tools to search destinations, list cities, compute distances and draft a route; drafts are returned in route_drafts and are only saved as a route after POST /route/drafts/{id}/confirm.",
                 "consumes": [
                     "application/json"
                 ],
@@ -36,7 +41,7 @@ const docTemplate = `{
                 "tags": [
                     "Chat"
                 ],
-                "summary": "Send a chat message to Gemini API",
+                "summary": "Send a chat message to the travel assistant",
                 "parameters": [
                     {
                         "description": "Chat Message",
@@ -44,7 +49,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.Input"
+                            "$ref": "#/definitions/request.ChatInput"
                         }
                     }
                 ],
@@ -63,29 +68,92 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/city": {
+        "/chat/conversations": {
             "get": {
-                "description": "Retrieve a list of all cities from the database",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Fetch the authenticated user's conversations, most recently active first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cities"
+                    "Chat"
+                ],
+                "summary": "List chat conversations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all cities",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -94,153 +162,199 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Invalid pagination; cursor mode is not supported",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new city in the database",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/chat/conversations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Fetch one of your conversations with all its messages, oldest first, to resume it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cities"
+                    "Chat"
                 ],
-                "summary": "Create a new city",
+                "summary": "Get a chat conversation",
                 "parameters": [
                     {
-                        "description": "City Name",
-                        "name": "city",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CityInput"
-                        }
+                        "type": "integer",
+                        "description": "Conversation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/response.ConversationDetailResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/dashboard/count-data": {
-            "get": {
-                "description": "Retrieve a summary of users, destinations, video content, and destination categories for the dashboard",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete one of your conversations and all its messages. Pending route drafts proposed in it stay available under GET /route/drafts.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard"
+                    "Chat"
+                ],
+                "summary": "Delete a chat conversation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Conversation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Fetch dashboard data summary",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/dashboard/graphic": {
+        "/chat/quota": {
             "get": {
-                "description": "Retrieve the number of user registrations grouped by month",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch your daily chat token quota and how much of it you have used today (UTC)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dashboard"
+                    "Chat"
                 ],
-                "summary": "Fetch monthly user registration data",
+                "summary": "Get your chat quota",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/response.ChatQuota"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/destination": {
+        "/chat/quotas": {
             "get": {
-                "description": "Fetch the destinations between two cities, based on the origin and destination city names",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Admin only. List the users who used the chat assistant on a day (UTC), heaviest users first, with their quotas",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Routes"
+                    "Chat"
                 ],
-                "summary": "Get destinations for a specific route",
+                "summary": "List chat usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Origin city name",
-                        "name": "origin",
-                        "in": "query",
-                        "required": true
+                        "description": "Day as YYYY-MM-DD (default today, UTC)",
+                        "name": "date",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Destination city name",
-                        "name": "destination",
-                        "in": "query",
-                        "required": true
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -252,7 +366,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "City not found",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -260,8 +374,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
-                        "description": "Failed to fetch destinations",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -270,39 +391,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new destination and associate it with a city, images, and video contents",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/chat/quotas/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Admin only. Fetch a user's daily chat token quota and today's usage (UTC)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Destinations"
+                    "Chat"
                 ],
-                "summary": "Create a new destination",
+                "summary": "Get a user's chat quota",
                 "parameters": [
                     {
-                        "description": "Destination Input",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.CreateDestinationInput"
-                        }
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Destination"
+                            "$ref": "#/definitions/response.ChatQuota"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -320,11 +450,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/destinations": {
-            "get": {
-                "description": "Fetch a list of destinations with filters like name, city, category, and sort order",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin only. Override the default daily chat token quota (CHAT_DAILY_TOKEN_QUOTA) for a user; 0 means unlimited. Takes effect immediately for today's usage.",
                 "consumes": [
                     "application/json"
                 ],
@@ -332,43 +465,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Destinations"
+                    "Chat"
                 ],
-                "summary": "Get all destinations",
+                "summary": "Set a user's chat quota",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by destination name",
-                        "name": "name",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by city name",
-                        "name": "city",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by category",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort order (newest, oldest)",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "Daily token limit",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ChatQuotaInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ChatQuota"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -379,85 +526,135 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/destinations/assets": {
-            "post": {
-                "description": "Upload images and videos to a destination",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Admin only. Remove a user's quota override so the default daily chat token quota applies again. Today's usage is kept.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Destinations"
+                    "Chat"
                 ],
-                "summary": "Create assets (images and videos) for a destination",
+                "summary": "Reset a user's chat quota to the default",
                 "parameters": [
                     {
-                        "description": "Create Destination Assets",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateDestinationAssetsInput"
-                        }
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/response.ChatQuota"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/destinations/most-viewed-videos": {
-            "get": {
-                "description": "Fetch destinations ranked by the number of video views",
+        "/chat/stream": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Same as POST /chat, but the reply is streamed as Server-Sent Events. Each \"token\" event carries {\"text\"} with the next piece of the reply and each \"tool\" event carries {\"name\"} of a tool the model is running; the final \"done\" event carries {\"conversation_id\", \"usage\", \"citations\", \"route_drafts\"}. If the model fails after streaming has started an \"error\" event is sent instead. Nothing is saved when the client disconnects before the reply is complete.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "Destinations"
+                    "Chat"
+                ],
+                "summary": "Stream a chat response",
+                "parameters": [
+                    {
+                        "description": "Chat Message",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ChatInput"
+                        }
+                    }
                 ],
-                "summary": "Get most viewed video content",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Server-Sent Events stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -466,13 +663,31 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/destinations/personalized": {
+        "/city": {
             "get": {
-                "description": "Fetch destinations based on the user's preferences and categories",
+                "description": "Retrieve a list of cities from the database, one page at a time",
                 "consumes": [
                     "application/json"
                 ],
@@ -480,16 +695,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Destinations"
+                    "Cities"
                 ],
-                "summary": "Get personalized destinations for a user",
+                "summary": "Get all cities",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Filter by city name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.next_cursor; send empty to start cursor mode",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -507,13 +739,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -522,11 +747,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/destinations/{id}": {
-            "get": {
-                "description": "Fetch detailed information of a destination including city, images, and video contents",
+            },
+            "post": {
+                "description": "Create a new city in the database, optionally with its coordinates",
                 "consumes": [
                     "application/json"
                 ],
@@ -534,16 +757,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Destinations"
+                    "Cities"
                 ],
-                "summary": "Get destination details",
+                "summary": "Create a new city",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Destination ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "City name and coordinates",
+                        "name": "city",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CityInput"
+                        }
                     }
                 ],
                 "responses": {
@@ -554,28 +779,38 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
                 }
-            },
+            }
+        },
+        "/city/{id}": {
             "put": {
-                "description": "Update destination details including city, images, and video contents",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a city's name and coordinates",
                 "consumes": [
                     "application/json"
                 ],
@@ -583,24 +818,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Destinations"
+                    "Cities"
                 ],
-                "summary": "Update a destination",
+                "summary": "Update a city",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Destination ID",
+                        "description": "City ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Destination Input",
-                        "name": "input",
+                        "description": "City name and coordinates",
+                        "name": "city",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.CreateDestinationInput"
+                            "$ref": "#/definitions/controllers.CityInput"
                         }
                     }
                 ],
@@ -609,125 +844,102 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a destination and its associated images and video contents",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/dashboard/count-data": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Admin only. Retrieve a summary of users, destinations, video content, favorites, and destination categories for the dashboard",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Destinations"
-                ],
-                "summary": "Delete a destination",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Destination ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Dashboard"
                 ],
+                "summary": "Fetch dashboard data summary",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Handle user login by verifying username and password, and return a JWT token.",
-                "consumes": [
-                    "application/json"
+        "/dashboard/graphic": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Admin only. Retrieve the number of user registrations grouped by month",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
-                ],
-                "summary": "User login",
-                "parameters": [
-                    {
-                        "description": "Login credentials",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.LoginInput"
-                        }
-                    }
+                    "Dashboard"
                 ],
+                "summary": "Fetch monthly user registration data",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -736,15 +948,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -760,9 +972,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/logout": {
+        "/destination": {
             "get": {
-                "description": "Process the logout request and return a success message",
+                "description": "Fetch the destinations between two cities, based on the origin and destination city names",
                 "consumes": [
                     "application/json"
                 ],
@@ -770,9 +982,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Routes"
+                ],
+                "summary": "Get destinations for a specific route",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Origin city name",
+                        "name": "origin",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destination city name",
+                        "name": "destination",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Log out a user",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -782,25 +1010,27 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "City not found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Failed to fetch destinations",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/register": {
+            },
             "post": {
-                "description": "Handle user registration by validating input and creating a new user in the database.",
+                "description": "Create a new destination and associate it with a city, images, video contents, and sustainability attributes. The eco-score is computed from the sustainability attributes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -808,17 +1038,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Destinations"
                 ],
-                "summary": "User registration",
+                "summary": "Create a new destination",
                 "parameters": [
                     {
-                        "description": "Registration details",
+                        "description": "Destination Input",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.RegisterInput"
+                            "$ref": "#/definitions/request.CreateDestinationInput"
                         }
                     }
                 ],
@@ -826,30 +1056,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Destination"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/route": {
+        "/destination/nearby": {
             "get": {
-                "description": "Fetch all routes created by a specific user",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch destinations within radius_km of the given coordinates, sorted by distance (km)",
                 "consumes": [
                     "application/json"
                 ],
@@ -857,16 +1095,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Routes"
+                    "Destinations"
                 ],
-                "summary": "Get all routes by user",
+                "summary": "Find destinations near a point",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "type": "number",
+                        "description": "Latitude (-90 to 90)",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude (-180 to 180)",
+                        "name": "lng",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Search radius in km (default 10, max 200)",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -877,8 +1140,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "User not found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -887,7 +1150,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -896,39 +1159,60 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new route by specifying the origin and destination cities, and additional route details",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/destination/{id}/carbon": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Estimate the kg CO2e of travelling from an origin city to a destination with the given transport mode and number of travellers, along with lower-carbon alternatives",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Routes"
+                    "Destinations"
                 ],
-                "summary": "Create a new travel route",
+                "summary": "Estimate the carbon footprint of travelling to a destination",
                 "parameters": [
                     {
-                        "description": "Route details",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.CreateRouteInput"
-                        }
+                        "type": "integer",
+                        "description": "Destination ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Origin city name",
+                        "name": "origin",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transport mode: car, motorbike, bus, train, plane, bicycle, walking (default car)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of travellers (default 1)",
+                        "name": "travellers",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Route"
+                            "$ref": "#/definitions/response.CarbonEstimateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -936,8 +1220,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Failed to create route",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -948,23 +1232,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/route/{id}": {
-            "delete": {
-                "description": "Delete a route by its ID, including all related destinations",
-                "consumes": [
-                    "application/json"
+        "/destination/{id}/favorite": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Add a destination to the authenticated user's favorites. Saving a destination twice has no effect.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Routes"
+                    "Favorites"
                 ],
-                "summary": "Delete a specific route",
+                "summary": "Save a destination to favorites",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Route ID",
+                        "type": "integer",
+                        "description": "Destination ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -972,25 +1258,14 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Route successfully deleted",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid route ID",
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Route not found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -999,7 +1274,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to delete route",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1008,30 +1283,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/category": {
-            "post": {
-                "description": "Assign categories to a user by updating their profile.",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Remove a destination from the authenticated user's favorites. Removing a destination that is not a favorite has no effect.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Favorites"
                 ],
-                "summary": "Create or update user categories",
+                "summary": "Remove a destination from favorites",
                 "parameters": [
                     {
-                        "description": "User categories",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UserCategoryInput"
-                        }
+                        "type": "integer",
+                        "description": "Destination ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1042,43 +1315,154 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/destination/{id}/images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload one or more images for a destination. Files are checked by content (JPEG, PNG, WebP or GIF), limited in size (UPLOAD_MAX_BYTES, default 5 MiB) and stored under content-hash names, so the returned URLs are stable. Thumbnail, medium and large variants are generated with EXIF metadata stripped.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Destinations"
+                ],
+                "summary": "Upload destination images",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Destination ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image files (up to 10)",
+                        "name": "images",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/user/{id}": {
+        "/destination/{id}/reviews": {
             "get": {
-                "description": "Retrieve detailed information for a specific user by ID.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Fetch visible reviews of a destination, newest first. Admins can add include_hidden=true to see hidden reviews too.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Reviews"
                 ],
-                "summary": "Get user details",
+                "summary": "List reviews of a destination",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Destination ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include hidden reviews (admin only)",
+                        "name": "include_hidden",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.next_cursor; send empty to start cursor mode",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1089,26 +1473,42 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/users/change-password/{id}": {
-            "put": {
-                "description": "Allows a user to change their password by providing the current password and a new password.",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Give a destination a 1-5 rating with optional text and photo URLs. Each user can review a destination once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1116,24 +1516,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Reviews"
                 ],
-                "summary": "Change user password",
+                "summary": "Review a destination",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Destination ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Change Password Payload",
+                        "description": "Rating, comment and photo URLs",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.ChangePasswordInput"
+                            "$ref": "#/definitions/request.ReviewInput"
                         }
                     }
                 ],
@@ -1141,10 +1541,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/response.ReviewResponse"
                         }
                     },
                     "400": {
@@ -1154,15 +1551,17 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Already reviewed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1182,86 +1581,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
-            "put": {
-                "description": "Update user information such as username, email, password, and categories.",
+        "/destinations": {
+            "get": {
+                "description": "Fetch a list of destinations with filters like name, city, category, eco-score, rating, and sort order",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Destinations"
                 ],
-                "summary": "Edit user profile",
+                "summary": "Get all destinations",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Filter by destination name",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Username",
-                        "name": "username",
-                        "in": "formData"
+                        "description": "Filter by city name",
+                        "name": "city",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "First Name",
-                        "name": "first_name",
-                        "in": "formData"
+                        "description": "Filter by category",
+                        "name": "category",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Last Name",
-                        "name": "last_name",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "Only destinations with at least this eco-score (0-100)",
+                        "name": "min_eco_score",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Email",
-                        "name": "email",
-                        "in": "formData"
+                        "type": "boolean",
+                        "description": "Only destinations with at least one eco-certification",
+                        "name": "certified",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "City",
-                        "name": "city",
-                        "in": "formData"
+                        "type": "boolean",
+                        "description": "Only destinations reachable by public transport",
+                        "name": "public_transport",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Password",
-                        "name": "password",
-                        "in": "formData"
+                        "type": "number",
+                        "description": "Only reviewed destinations with at least this average rating (0-5)",
+                        "name": "min_rating",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Role",
-                        "name": "role",
-                        "in": "formData"
+                        "description": "Sort order (newest, oldest, eco_score, rating); eco_score and rating sort highest first and do not support cursor mode",
+                        "name": "sort",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Phone Number",
-                        "name": "phone_number",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Gender",
-                        "name": "gender",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "type": "file",
-                        "description": "Profile Image",
-                        "name": "file",
-                        "in": "formData"
+                        "type": "string",
+                        "description": "Opaque cursor from meta.next_cursor; send empty to start cursor mode",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1276,29 +1674,26 @@ const docTemplate = `{
                         "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/videos": {
-            "get": {
-                "description": "Fetch all video contents stored in the system",
+        "/destinations/assets": {
+            "post": {
+                "description": "Upload images and videos to a destination",
                 "consumes": [
                     "application/json"
                 ],
@@ -1306,17 +1701,92 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Video"
+                    "Destinations"
                 ],
-                "summary": "Get all video contents",
-                "responses": {
-                    "200": {
+                "summary": "Create assets (images and videos) for a destination",
+                "parameters": [
+                    {
+                        "description": "Create Destination Assets",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateDestinationAssetsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/destinations/personalized": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch destinations based on the authenticated user's preferences and categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Destinations"
+                ],
+                "summary": "Get personalized destinations for a user",
+                "responses": {
+                    "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1326,371 +1796,3526 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.ChangePasswordInput": {
+        },
+        "/destinations/{id}": {
+            "get": {
+                "description": "Fetch detailed information of a destination including city, images, and video contents",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Destinations"
+                ],
+                "summary": "Get destination details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Destination ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update destination details including city, images, video contents, and sustainability attributes. The eco-score is recomputed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Destinations"
+                ],
+                "summary": "Update a destination",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Destination ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Destination Input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateDestinationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a destination and its associated images and video contents",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Destinations"
+                ],
+                "summary": "Delete a destination",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Destination ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Handle user login by verifying username and password, and return a JWT token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke the current session so its access and refresh tokens can no longer be used",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Log out a user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/logout/all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke every active session of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Log out from all devices",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/password/forgot": {
+            "post": {
+                "description": "Send a one-time password reset link to the email address if it belongs to an account. The response is the same whether or not the email exists.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ForgotPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Set a new password using the token from the reset email. The token can only be used once and every active session of the user is logged out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset password with a one-time token",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ResetPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Handle user registration by validating input and creating a new, unverified user in the database. A verification link is sent by email; until it is opened the returned token only grants limited access. Registering as admin is not allowed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "User registration",
+                "parameters": [
+                    {
+                        "description": "Registration details",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RegisterInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/review": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch reviews across all destinations for moderation, newest first. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "List reviews for moderation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (visible, hidden)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only flagged reviews",
+                        "name": "flagged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.next_cursor; send empty to start cursor mode",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the rating, text and photos of your own review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Edit a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating, comment and photo URLs",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a review. Only the author or an admin can delete it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Delete a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/{id}/moderation": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hide, unhide, flag or unflag a review. Hidden reviews are excluded from listings and from the destination's rating. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Moderate a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moderation action and optional reason",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ModerateReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch routes created by the authenticated user, newest first, one page at a time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Get all routes by user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.next_cursor; send empty to start cursor mode",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new route for the authenticated user. The destinations are reordered into the shortest visiting order and the total distance is computed by the server. When destinationCityName is empty the route ends at the last destination.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Create a new travel route",
+                "parameters": [
+                    {
+                        "description": "Route details",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateRouteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Route"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create route",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/drafts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch the route drafts the chat assistant proposed to the authenticated user that have not been confirmed yet, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "List route drafts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch route drafts",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/drafts/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete one of your route drafts. A route already saved from it is not affected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Discard a route draft",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route draft ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Route draft not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete route draft",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/drafts/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirm a route draft proposed by the chat assistant. The route is planned again from the draft's cities and destinations, so it reflects the current catalogue, and saved like POST /route. A draft can only be confirmed once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Save a route draft as a route",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route draft ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Route"
+                        }
+                    },
+                    "400": {
+                        "description": "Draft can no longer be planned",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Route draft not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Route draft already confirmed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save route",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/optimize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Order the given destinations into the shortest itinerary from the origin city, optionally ending at destinationCityName, without saving anything. Small sets are solved exactly; larger sets use nearest-neighbour with 2-opt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Preview the optimal visiting order",
+                "parameters": [
+                    {
+                        "description": "Origin, optional end city and destination IDs",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.OptimizeRouteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.OptimizedRouteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or missing coordinates",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to optimize route",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch a route with its stops grouped by day, including planned arrival/departure times, stay durations and notes. Only the owner or an admin can view it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Get a route itinerary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.RouteDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid route ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Route belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Route not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a route by its ID, including all related destinations. Only the owner or an admin can delete it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Delete a specific route",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Route successfully deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid route ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Route belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Route not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete route",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/{id}/stops": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a destination to an existing route on the given day (default 1). Position is 1-based within that day; without it the stop is appended to the day. The route distance is recomputed in the new order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Add a stop to a route",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stop details",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RouteStopInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.RouteDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Route belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Route or destination not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add stop",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/{id}/stops/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the visiting order with the given list, which must contain every stop of the route exactly once. A day may be given per stop to move it; stops are kept grouped by day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Reorder the stops of a route",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stops in the new order",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.ReorderRouteStopsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.RouteDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Route belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Route not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reorder stops",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/{id}/stops/{stopId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the day, planned times, stay duration or notes of a stop. Only the fields that are sent are updated; an empty string clears a time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Update a route stop",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop ID",
+                        "name": "stopId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateRouteStopInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.RouteDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Route belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Route or stop not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update stop",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a stop; the remaining stops are renumbered and the route distance is recomputed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Remove a stop from a route",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop ID",
+                        "name": "stopId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.RouteDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid stop ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Route belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Route or stop not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to remove stop",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/{id}/transport": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the transport mode and number of travellers and recompute the route's carbon footprint. Only the owner or an admin can change it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routes"
+                ],
+                "summary": "Change the transport mode of a route",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Route ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transport mode and travellers",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.RouteTransportInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.RouteDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Route belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Route not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update route",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token. The refresh token is rotated and the old one can no longer be used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RefreshTokenInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/category": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign categories to the authenticated user by updating their profile.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create or update user categories",
+                "parameters": [
+                    {
+                        "description": "User categories",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UserCategoryInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch the authenticated user's favorite destinations, most recently saved first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "List favorite destinations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.next_cursor; send empty to start cursor mode",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "description": "Retrieve detailed information for a specific user by ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/change-password/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows a user to change their password by providing the current password and a new password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change user password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Change Password Payload",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ChangePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User successfully updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Incorrect password",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not the account owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update user information such as username, email, password, and categories. Users can only edit their own account; only admins can change roles.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Edit user profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First Name",
+                        "name": "first_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last Name",
+                        "name": "last_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "City",
+                        "name": "city",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role",
+                        "name": "role",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Phone Number",
+                        "name": "phone_number",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Gender",
+                        "name": "gender",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Profile Image",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/verify-email": {
+            "get": {
+                "description": "Mark the account's email as verified using the token from the verification email. Refresh or log in again to get a token with full access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Verify email address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/verify-email/resend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a new verification link to the authenticated user's email address",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Resend verification email",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/video-content/most": {
+            "get": {
+                "description": "Rank videos and destinations by the number of views within a time window",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Video"
+                ],
+                "summary": "Get most viewed videos and destinations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Time window: 24h, 7d, 30d, ... or all (default all)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum entries per ranking (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/video-content/{id}/view": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that the authenticated user watched a video. Views are grouped into fixed time buckets of VIDEO_VIEW_DEDUP_WINDOW (default 30m); repeated views by the same user within one bucket are counted once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Video"
+                ],
+                "summary": "Record a video view",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "counted is false when the view was deduplicated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/videos": {
+            "get": {
+                "description": "Fetch video contents stored in the system, one page at a time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Video"
+                ],
+                "summary": "Get all video contents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by destination ID",
+                        "name": "destination_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.next_cursor; send empty to start cursor mode",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.ChangePasswordInput": {
+            "type": "object",
+            "required": [
+                "currentPassword",
+                "newPassword"
+            ],
+            "properties": {
+                "currentPassword": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "controllers.CityInput": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateDestinationAssetsInput": {
+            "type": "object",
+            "properties": {
+                "destinationID": {
+                    "type": "integer"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "video_contents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/request.VideoInput"
+                    }
+                }
+            }
+        },
+        "controllers.ForgotPasswordInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.LoginInput": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.RefreshTokenInput": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.RegisterInput": {
+            "type": "object",
+            "required": [
+                "city",
+                "email",
+                "first_name",
+                "last_name",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "user"
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ResetPasswordInput": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "token"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UserCategoryInput": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "emissions.Alternative": {
+            "type": "object",
+            "properties": {
+                "carbon_kg": {
+                    "type": "number"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "saved_kg": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.City": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Destination": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "average_rating": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "certifications": {
+                    "type": "string"
+                },
+                "city": {
+                    "$ref": "#/definitions/models.City"
+                },
+                "city_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "eco_score": {
+                    "type": "integer"
+                },
+                "energy_practice": {
+                    "type": "string"
+                },
+                "facilities": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Image"
+                    }
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operational_hours": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "number"
+                },
+                "public_transport_accessible": {
+                    "type": "boolean"
+                },
+                "review_count": {
+                    "type": "integer"
+                },
+                "ticket_price": {
+                    "type": "number"
+                },
+                "video_contents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VideoContent"
+                    }
+                },
+                "visitor_capacity": {
+                    "type": "integer"
+                },
+                "waste_practice": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Image": {
+            "type": "object",
+            "properties": {
+                "destination_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImageVariant"
+                    }
+                },
+                "variants_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImageVariant": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Route": {
+            "type": "object",
+            "properties": {
+                "carbonKg": {
+                    "type": "number"
+                },
+                "cost": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "destinationCityName": {
+                    "type": "string"
+                },
+                "destinations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RouteDestination"
+                    }
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "openEnded": {
+                    "type": "boolean"
+                },
+                "originCityName": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "transportMode": {
+                    "type": "string"
+                },
+                "travellers": {
+                    "type": "integer"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RouteDestination": {
+            "type": "object",
+            "properties": {
+                "arrival_time": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "day": {
+                    "type": "integer"
+                },
+                "departure_time": {
+                    "type": "string"
+                },
+                "destinationID": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "routeID": {
+                    "type": "integer"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "stay_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RouteDraft": {
+            "type": "object",
+            "properties": {
+                "carbonKg": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "destinationCityName": {
+                    "type": "string"
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "messageID": {
+                    "description": "MessageID adalah jawaban asisten yang menyertakan draft ini",
+                    "type": "integer"
+                },
+                "openEnded": {
+                    "type": "boolean"
+                },
+                "originCityName": {
+                    "type": "string"
+                },
+                "routeID": {
+                    "description": "RouteID diisi setelah draft dikonfirmasi",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RouteDraftStop"
+                    }
+                },
+                "transportMode": {
+                    "type": "string"
+                },
+                "travellers": {
+                    "type": "integer"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RouteDraftStop": {
+            "type": "object",
+            "properties": {
+                "destination_id": {
+                    "type": "integer"
+                },
+                "leg_distance": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.VideoContent": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "destination_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.ChatInput": {
             "type": "object",
             "required": [
-                "currentPassword",
-                "newPassword"
+                "message"
             ],
             "properties": {
-                "currentPassword": {
-                    "type": "string",
-                    "minLength": 6
+                "conversation_id": {
+                    "description": "ConversationID melanjutkan percakapan yang sudah ada; kosong memulai percakapan baru",
+                    "type": "integer"
                 },
-                "newPassword": {
+                "message": {
                     "type": "string",
-                    "minLength": 6
+                    "maxLength": 4000
                 }
             }
         },
-        "controllers.CityInput": {
+        "request.ChatQuotaInput": {
             "type": "object",
+            "required": [
+                "daily_token_limit"
+            ],
             "properties": {
-                "name": {
-                    "type": "string"
+                "daily_token_limit": {
+                    "description": "DailyTokenLimit 0 berarti tanpa batas",
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "controllers.CreateDestinationAssetsInput": {
+        "request.CreateDestinationInput": {
             "type": "object",
+            "required": [
+                "certifications"
+            ],
             "properties": {
-                "destinationID": {
-                    "type": "integer"
+                "address": {
+                    "type": "string"
                 },
-                "images": {
+                "category": {
+                    "type": "string"
+                },
+                "certifications": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "city": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "energy_practice": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "partial_renewable",
+                        "renewable"
+                    ]
+                },
+                "facilities": {
+                    "type": "string"
+                },
+                "image": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operational_hours": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "number"
+                },
+                "public_transport_accessible": {
+                    "type": "boolean"
+                },
+                "ticket_price": {
+                    "type": "number"
+                },
                 "video_contents": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/request.VideoInput"
                     }
+                },
+                "visitor_capacity": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "waste_practice": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "basic",
+                        "recycling",
+                        "zero_waste"
+                    ]
                 }
             }
         },
-        "controllers.Input": {
+        "request.CreateRouteInput": {
             "type": "object",
+            "required": [
+                "destinations",
+                "originCityName"
+            ],
             "properties": {
-                "message": {
+                "cost": {
+                    "type": "integer"
+                },
+                "destinationCityName": {
+                    "type": "string"
+                },
+                "destinations": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "originCityName": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "transportMode": {
+                    "type": "string"
+                },
+                "travellers": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                }
+            }
+        },
+        "request.ModerateReviewInput": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "hide",
+                        "unhide",
+                        "flag",
+                        "unflag"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "request.OptimizeRouteInput": {
+            "type": "object",
+            "required": [
+                "destinations",
+                "originCityName"
+            ],
+            "properties": {
+                "destinationCityName": {
+                    "type": "string"
+                },
+                "destinations": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "originCityName": {
                     "type": "string"
                 }
             }
         },
-        "controllers.LoginInput": {
+        "request.ReorderRouteStop": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "day": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.ReorderRouteStopsInput": {
+            "type": "object",
+            "required": [
+                "stops"
+            ],
+            "properties": {
+                "stops": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/request.ReorderRouteStop"
+                    }
+                }
+            }
+        },
+        "request.ReviewInput": {
+            "type": "object",
+            "required": [
+                "photos",
+                "rating"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "photos": {
+                    "type": "array",
+                    "maxItems": 5,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                }
+            }
+        },
+        "request.RouteStopInput": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "destination_id"
             ],
             "properties": {
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "arrival_time": {
+                    "type": "string"
                 },
-                "username": {
+                "day": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "departure_time": {
                     "type": "string"
+                },
+                "destination_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "stay_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 0
                 }
             }
         },
-        "controllers.RegisterInput": {
+        "request.RouteTransportInput": {
             "type": "object",
             "required": [
-                "city",
-                "email",
-                "first_name",
-                "last_name",
-                "password",
-                "username"
+                "transportMode"
             ],
             "properties": {
-                "city": {
+                "transportMode": {
                     "type": "string"
                 },
-                "email": {
+                "travellers": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                }
+            }
+        },
+        "request.UpdateRouteStopInput": {
+            "type": "object",
+            "properties": {
+                "arrival_time": {
                     "type": "string"
                 },
-                "first_name": {
-                    "type": "string"
+                "day": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
                 },
-                "last_name": {
+                "departure_time": {
                     "type": "string"
                 },
-                "password": {
+                "notes": {
                     "type": "string",
-                    "minLength": 6
+                    "maxLength": 2000
                 },
-                "role": {
-                    "type": "string",
-                    "enum": [
-                        "admin",
-                        "user"
-                    ]
+                "stay_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 0
+                }
+            }
+        },
+        "request.VideoInput": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
                 },
-                "username": {
+                "title": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "controllers.UserCategoryInput": {
+        "response.CarbonEstimateResponse": {
             "type": "object",
             "properties": {
-                "category": {
+                "alternatives": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/emissions.Alternative"
                     }
                 },
-                "userID": {
+                "carbon_kg": {
+                    "type": "number"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "transport_mode": {
+                    "type": "string"
+                },
+                "travellers": {
                     "type": "integer"
                 }
             }
         },
-        "models.City": {
+        "response.ChatQuota": {
             "type": "object",
             "properties": {
-                "id": {
+                "custom": {
+                    "description": "Custom menandai kuota yang diatur admin, bukan default CHAT_DAILY_TOKEN_QUOTA",
+                    "type": "boolean"
+                },
+                "daily_token_limit": {
+                    "description": "DailyTokenLimit 0 berarti tanpa batas",
                     "type": "integer"
                 },
-                "lat": {
+                "day": {
                     "type": "string"
                 },
-                "long": {
-                    "type": "string"
+                "remaining_tokens": {
+                    "description": "RemainingTokens null jika tanpa batas",
+                    "type": "integer"
                 },
-                "name": {
+                "requests": {
+                    "type": "integer"
+                },
+                "resets_at": {
                     "type": "string"
+                },
+                "used_tokens": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Destination": {
+        "response.Citation": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "category": {
-                    "type": "string"
-                },
-                "city": {
-                    "$ref": "#/definitions/models.City"
-                },
-                "city_id": {
+                "id": {
                     "type": "integer"
                 },
-                "created_at": {
+                "marker": {
                     "type": "string"
                 },
-                "description": {
+                "name": {
                     "type": "string"
                 },
-                "facilities": {
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ConversationDetailResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "images": {
+                "messages": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Image"
+                        "$ref": "#/definitions/response.MessageResponse"
                     }
                 },
-                "name": {
+                "title": {
                     "type": "string"
                 },
-                "operational_hours": {
+                "updated_at": {
                     "type": "string"
-                },
-                "position": {
-                    "type": "number"
-                },
-                "ticket_price": {
-                    "type": "number"
-                },
-                "video_contents": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.VideoContent"
-                    }
                 }
             }
         },
-        "models.Image": {
+        "response.ItineraryDay": {
             "type": "object",
             "properties": {
-                "destination_id": {
-                    "type": "integer"
-                },
-                "id": {
+                "day": {
                     "type": "integer"
                 },
-                "url": {
-                    "type": "string"
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.ItineraryStop"
+                    }
                 }
             }
         },
-        "models.Route": {
+        "response.ItineraryStop": {
             "type": "object",
             "properties": {
-                "cost": {
-                    "type": "integer"
+                "arrival_time": {
+                    "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "destinationCityName": {
+                "day": {
+                    "type": "integer"
+                },
+                "departure_time": {
                     "type": "string"
                 },
-                "destinations": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.RouteDestination"
-                    }
+                "destination": {
+                    "$ref": "#/definitions/models.Destination"
                 },
-                "distance": {
-                    "type": "number"
+                "destinationID": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "originCityName": {
+                "notes": {
                     "type": "string"
                 },
-                "time": {
-                    "type": "string"
+                "routeID": {
+                    "type": "integer"
                 },
-                "userID": {
+                "sequence": {
+                    "type": "integer"
+                },
+                "stay_minutes": {
                     "type": "integer"
                 }
             }
         },
-        "models.RouteDestination": {
+        "response.MessageResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "citations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.Citation"
+                    }
+                },
+                "content": {
                     "type": "string"
                 },
-                "destinationID": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "routeID": {
-                    "type": "integer"
+                "role": {
+                    "type": "string"
+                },
+                "route_drafts": {
+                    "description": "RouteDrafts adalah draft rute yang diusulkan asisten di pesan ini",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RouteDraft"
+                    }
                 }
             }
         },
-        "models.VideoContent": {
+        "response.OptimizedRouteResponse": {
             "type": "object",
             "properties": {
-                "description": {
+                "destinationCityName": {
                     "type": "string"
                 },
-                "destination_id": {
-                    "type": "integer"
+                "distance": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "integer"
+                "final_leg_distance": {
+                    "type": "number"
                 },
-                "title": {
+                "originCityName": {
                     "type": "string"
                 },
-                "url": {
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.RouteStop"
+                    }
+                }
+            }
+        },
+        "response.ReviewAuthor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "request.CreateDestinationInput": {
+        "response.ReviewResponse": {
             "type": "object",
             "properties": {
-                "address": {
+                "comment": {
                     "type": "string"
                 },
-                "category": {
+                "created_at": {
                     "type": "string"
                 },
-                "city": {
-                    "type": "string"
+                "destination_id": {
+                    "type": "integer"
                 },
-                "description": {
+                "flag_reason": {
                     "type": "string"
                 },
-                "facilities": {
-                    "type": "string"
+                "flagged": {
+                    "type": "boolean"
                 },
-                "image": {
+                "id": {
+                    "type": "integer"
+                },
+                "photos": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "name": {
-                    "type": "string"
+                "rating": {
+                    "type": "integer"
                 },
-                "operational_hours": {
+                "status": {
                     "type": "string"
                 },
-                "position": {
-                    "type": "number"
-                },
-                "ticket_price": {
-                    "type": "number"
+                "updated_at": {
+                    "type": "string"
                 },
-                "video_contents": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/request.VideoInput"
-                    }
+                "user": {
+                    "$ref": "#/definitions/response.ReviewAuthor"
                 }
             }
         },
-        "request.CreateRouteInput": {
+        "response.RouteDetailResponse": {
             "type": "object",
             "properties": {
+                "carbonAlternatives": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/emissions.Alternative"
+                    }
+                },
+                "carbonKg": {
+                    "type": "number"
+                },
                 "cost": {
                     "type": "integer"
                 },
-                "destinationCityName": {
+                "created_at": {
                     "type": "string"
                 },
-                "destinations": {
+                "days": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/response.ItineraryDay"
                     }
                 },
+                "destinationCityName": {
+                    "type": "string"
+                },
                 "distance": {
                     "type": "number"
                 },
+                "id": {
+                    "type": "integer"
+                },
+                "openEnded": {
+                    "type": "boolean"
+                },
                 "originCityName": {
                     "type": "string"
                 },
                 "time": {
                     "type": "string"
                 },
+                "transportMode": {
+                    "type": "string"
+                },
+                "travellers": {
+                    "type": "integer"
+                },
                 "userID": {
                     "type": "integer"
                 }
             }
         },
-        "request.VideoInput": {
+        "response.RouteStop": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "destination": {
+                    "$ref": "#/definitions/models.Destination"
                 },
-                "title": {
-                    "type": "string"
+                "leg_distance": {
+                    "type": "number"
                 },
-                "url": {
-                    "type": "string"
+                "sequence": {
+                    "type": "integer"
                 }
             }
         }
@@ -1698,6 +5323,11 @@ const docTemplate = `{
     "securityDefinitions": {
         "BasicAuth": {
             "type": "basic"
+        },
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     },
     "externalDocs": {
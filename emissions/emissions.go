@@ -0,0 +1,152 @@
+package emissions
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Mode transportasi yang didukung
+const (
+	Car       = "car"
+	Motorbike = "motorbike"
+	Bus       = "bus"
+	Train     = "train"
+	Plane     = "plane"
+	Bicycle   = "bicycle"
+	Walking   = "walking"
+)
+
+// Modes adalah semua mode transportasi, urut dari yang biasanya paling rendah emisinya
+var Modes = []string{Walking, Bicycle, Train, Bus, Motorbike, Car, Plane}
+
+// Factor adalah faktor emisi satu mode transportasi.
+//
+// Untuk kendaraan pribadi (PerVehicle) emisinya per kendaraan-km dan dibagi rata ke
+// penumpang, dengan jumlah kendaraan = ceil(travellers / Capacity). Untuk angkutan
+// umum emisinya per penumpang-km.
+type Factor struct {
+	KgPerKm    float64
+	PerVehicle bool
+	Capacity   int
+	// MinDistanceKm dan MaxDistanceKm membatasi kapan mode ini masuk akal sebagai
+	// alternatif; 0 berarti tidak dibatasi.
+	MinDistanceKm float64
+	MaxDistanceKm float64
+}
+
+// DefaultFactors berdasarkan rata-rata faktor konversi DEFRA 2023 (kg CO2e)
+func DefaultFactors() map[string]Factor {
+	return map[string]Factor{
+		Car:       {KgPerKm: 0.170, PerVehicle: true, Capacity: 5},
+		Motorbike: {KgPerKm: 0.114, PerVehicle: true, Capacity: 2},
+		Bus:       {KgPerKm: 0.097},
+		Train:     {KgPerKm: 0.035},
+		Plane:     {KgPerKm: 0.246, MinDistanceKm: 200},
+		Bicycle:   {KgPerKm: 0, MaxDistanceKm: 50},
+		Walking:   {KgPerKm: 0, MaxDistanceKm: 10},
+	}
+}
+
+// Calculator menghitung emisi perjalanan dengan faktor yang bisa dikonfigurasi
+type Calculator struct {
+	Factors map[string]Factor
+}
+
+// New membuat Calculator dengan faktor default
+func New() *Calculator {
+	return &Calculator{Factors: DefaultFactors()}
+}
+
+// FromEnv membuat Calculator dengan faktor default yang bisa ditimpa lewat
+// EMISSION_FACTOR_<MODE> (kg CO2e per km), misalnya EMISSION_FACTOR_CAR=0.19
+func FromEnv() (*Calculator, error) {
+	calculator := New()
+	for _, mode := range Modes {
+		key := "EMISSION_FACTOR_" + strings.ToUpper(mode)
+		raw := os.Getenv(key)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid %s %q", key, raw)
+		}
+		factor := calculator.Factors[mode]
+		factor.KgPerKm = value
+		calculator.Factors[mode] = factor
+	}
+	return calculator, nil
+}
+
+// ValidMode mengecek apakah mode transportasi dikenal
+func (c *Calculator) ValidMode(mode string) bool {
+	_, ok := c.Factors[mode]
+	return ok
+}
+
+// Estimate menghitung total emisi (kg CO2e) seluruh rombongan
+func (c *Calculator) Estimate(mode string, distanceKm float64, travellers int) (float64, error) {
+	factor, ok := c.Factors[mode]
+	if !ok {
+		return 0, fmt.Errorf("unknown transport mode %q", mode)
+	}
+	if travellers < 1 {
+		travellers = 1
+	}
+
+	units := float64(travellers)
+	if factor.PerVehicle && factor.Capacity > 0 {
+		units = math.Ceil(float64(travellers) / float64(factor.Capacity))
+	}
+
+	return round(factor.KgPerKm * distanceKm * units), nil
+}
+
+// Alternative adalah mode lain yang emisinya lebih rendah untuk perjalanan yang sama
+type Alternative struct {
+	Mode     string  `json:"mode"`
+	CarbonKg float64 `json:"carbon_kg"`
+	SavedKg  float64 `json:"saved_kg"`
+}
+
+// Alternatives mengembalikan mode yang masuk akal untuk jarak ini dan emisinya lebih
+// rendah daripada mode yang dipilih, urut dari yang paling rendah.
+func (c *Calculator) Alternatives(mode string, distanceKm float64, travellers int) ([]Alternative, error) {
+	current, err := c.Estimate(mode, distanceKm, travellers)
+	if err != nil {
+		return nil, err
+	}
+
+	alternatives := []Alternative{}
+	for _, other := range Modes {
+		factor, ok := c.Factors[other]
+		if !ok || other == mode {
+			continue
+		}
+		if factor.MaxDistanceKm > 0 && distanceKm > factor.MaxDistanceKm {
+			continue
+		}
+		if factor.MinDistanceKm > 0 && distanceKm < factor.MinDistanceKm {
+			continue
+		}
+
+		carbon, _ := c.Estimate(other, distanceKm, travellers)
+		if carbon < current {
+			alternatives = append(alternatives, Alternative{Mode: other, CarbonKg: carbon, SavedKg: round(current - carbon)})
+		}
+	}
+
+	sort.SliceStable(alternatives, func(i, j int) bool {
+		return alternatives[i].CarbonKg < alternatives[j].CarbonKg
+	})
+	return alternatives, nil
+}
+
+func round(kg float64) float64 {
+	return math.Round(kg*100) / 100
+}
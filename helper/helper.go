@@ -1,20 +1,15 @@
 package helper
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"log"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"math"
-	"net/http"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/joho/godotenv"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -73,107 +68,69 @@ func CheckPasswordHash(password, hash string) bool {
 
 // Struct untuk JWT Claims
 type JWTClaims struct {
-	Username string `json:"username"`
-	UserID   uint   `json:"user_id"`
-	Role     string `json:"role"`
+	Username  string `json:"username"`
+	UserID    uint   `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	Verified  bool   `json:"email_verified"`
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT membuat token JWT
-func GenerateJWT(userID uint, username, role string) (string, error) {
-	claims := &JWTClaims{
-		Username: username,
-		UserID:   userID,
-		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(72 * time.Hour)),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+// AccessTokenTTL adalah masa berlaku access token (JWT_ACCESS_TTL, default 15 menit)
+func AccessTokenTTL() time.Duration {
+	return durationFromEnv("JWT_ACCESS_TTL", 15*time.Minute)
 }
 
-type GeminiResponse struct {
-	Candidates []struct {
-		Content struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
-			Role string `json:"role"`
-		} `json:"content"`
-		FinishReason string  `json:"finishReason"`
-		AvgLogprobs  float64 `json:"avgLogprobs"`
-	} `json:"candidates"`
-	UsageMetadata struct {
-		PromptTokenCount     int `json:"promptTokenCount"`
-		CandidatesTokenCount int `json:"candidatesTokenCount"`
-		TotalTokenCount      int `json:"totalTokenCount"`
-	} `json:"usageMetadata"`
-	ModelVersion string `json:"modelVersion"`
-}
-
-func CallGeminiAPI(message string) (string, error) {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
-
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	baseURL := os.Getenv("GEMINI_BASE_URL") + "?key=" + apiKey
-
-	payload := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]string{
-					{"text": message},
-				},
-			},
-		},
-	}
+// RefreshTokenTTL adalah masa berlaku refresh token (JWT_REFRESH_TTL, default 30 hari)
+func RefreshTokenTTL() time.Duration {
+	return durationFromEnv("JWT_REFRESH_TTL", 30*24*time.Hour)
+}
 
-	if strings.Contains(message, "halo") {
-		return "Selamat Datang di Tripwise, folks!", nil
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
 	}
+	return fallback
+}
 
-	jsonBytes, err := json.Marshal(payload)
+// GenerateJWT membuat access token JWT untuk satu sesi login
+func GenerateJWT(userID uint, username, role, sessionID string, verified bool) (string, error) {
+	jti, err := RandomToken(16)
 	if err != nil {
-		fmt.Println("Error marshaling to JSON:", err)
+		return "", err
 	}
 
-	req, err := http.NewRequest("POST", baseURL, bytes.NewBuffer(jsonBytes))
-	if err != nil {
-		fmt.Println("Error creating request:", err)
+	now := time.Now()
+	claims := &JWTClaims{
+		Username:  username,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+		Verified:  verified,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL())),
+		},
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	// req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Println("Error making request:", err)
-	}
-	defer resp.Body.Close()
-
-	// Handle the response
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		fmt.Println("Error response from API:")
-		fmt.Println(string(body))
-	} else {
-		fmt.Println("Response from API:")
-		fmt.Println(string(body))
-	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+}
 
-	var response GeminiResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		fmt.Println("Error unmarshaling JSON:", err)
+// RandomToken membuat string acak (hex) sepanjang n byte
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(b), nil
+}
 
-	return response.Candidates[0].Content.Parts[0].Text, nil
+// HashToken meng-hash token opaque sebelum disimpan di database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
@@ -190,3 +147,33 @@ func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
 
 	return R * c
 }
+
+// BoundingBox menghitung kotak lat/long yang memuat lingkaran radiusKm di sekitar titik,
+// dipakai sebagai pre-filter sebelum Haversine. wrapsLon bernilai true jika kotak
+// melewati garis 180° sehingga filter longitude tidak bisa dipakai.
+func BoundingBox(lat, lon, radiusKm float64) (minLat, maxLat, minLon, maxLon float64, wrapsLon bool) {
+	const R = 6371
+	latDelta := radiusKm / R * (180 / math.Pi)
+	minLat = math.Max(lat-latDelta, -90)
+	maxLat = math.Min(lat+latDelta, 90)
+
+	// Dekat kutub semua longitude termasuk
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if minLat <= -90 || maxLat >= 90 || cosLat < 1e-9 {
+		return minLat, maxLat, -180, 180, true
+	}
+
+	lonDelta := latDelta / cosLat
+	minLon = lon - lonDelta
+	maxLon = lon + lonDelta
+	if minLon < -180 || maxLon > 180 {
+		return minLat, maxLat, -180, 180, true
+	}
+
+	return minLat, maxLat, minLon, maxLon, false
+}
+
+// ValidCoordinates mengecek rentang latitude dan longitude
+func ValidCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
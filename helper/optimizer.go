@@ -0,0 +1,213 @@
+package helper
+
+import "math"
+
+// GeoPoint adalah satu titik koordinat (derajat)
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+// ExactRouteLimit adalah jumlah titik maksimum yang masih diselesaikan secara eksak
+// (Held-Karp, O(2^n * n^2)); di atas itu dipakai nearest-neighbour + 2-opt.
+const ExactRouteLimit = 10
+
+// OptimizeRoute mencari urutan kunjungan stops dengan jarak total terpendek, berangkat dari
+// start dan berakhir di end. Jika end nil rutenya terbuka (selesai di stop terakhir).
+// Mengembalikan urutan berupa index ke stops beserta total jarak (km).
+func OptimizeRoute(start GeoPoint, end *GeoPoint, stops []GeoPoint) ([]int, float64) {
+	m := newDistanceMatrix(start, end, stops)
+
+	var order []int
+	if len(stops) <= ExactRouteLimit {
+		order = m.exact()
+	} else {
+		order = m.twoOpt(m.nearestNeighbour())
+	}
+
+	return order, m.cost(order)
+}
+
+// RouteLegs mengembalikan jarak tiap leg untuk urutan yang sudah ditentukan:
+// start -> stops[order[0]] -> ... -> end. Leg terakhir hanya ada jika end tidak nil.
+func RouteLegs(start GeoPoint, end *GeoPoint, stops []GeoPoint, order []int) []float64 {
+	legs := make([]float64, 0, len(order)+1)
+	prev := start
+	for _, i := range order {
+		legs = append(legs, Haversine(prev.Lat, prev.Lon, stops[i].Lat, stops[i].Lon))
+		prev = stops[i]
+	}
+	if end != nil {
+		legs = append(legs, Haversine(prev.Lat, prev.Lon, end.Lat, end.Lon))
+	}
+	return legs
+}
+
+type distanceMatrix struct {
+	n         int
+	between   [][]float64
+	fromStart []float64
+	toEnd     []float64 // nol semua jika rute terbuka
+	direct    float64   // start -> end tanpa stop
+}
+
+func newDistanceMatrix(start GeoPoint, end *GeoPoint, stops []GeoPoint) distanceMatrix {
+	n := len(stops)
+	m := distanceMatrix{
+		n:         n,
+		between:   make([][]float64, n),
+		fromStart: make([]float64, n),
+		toEnd:     make([]float64, n),
+	}
+
+	for i, a := range stops {
+		m.between[i] = make([]float64, n)
+		for j, b := range stops {
+			if i != j {
+				m.between[i][j] = Haversine(a.Lat, a.Lon, b.Lat, b.Lon)
+			}
+		}
+		m.fromStart[i] = Haversine(start.Lat, start.Lon, a.Lat, a.Lon)
+		if end != nil {
+			m.toEnd[i] = Haversine(a.Lat, a.Lon, end.Lat, end.Lon)
+		}
+	}
+	if end != nil {
+		m.direct = Haversine(start.Lat, start.Lon, end.Lat, end.Lon)
+	}
+
+	return m
+}
+
+func (m distanceMatrix) cost(order []int) float64 {
+	if len(order) == 0 {
+		return m.direct
+	}
+
+	total := m.fromStart[order[0]]
+	for i := 1; i < len(order); i++ {
+		total += m.between[order[i-1]][order[i]]
+	}
+	return total + m.toEnd[order[len(order)-1]]
+}
+
+// exact menyelesaikan rute dengan dynamic programming Held-Karp
+func (m distanceMatrix) exact() []int {
+	n := m.n
+	if n == 0 {
+		return []int{}
+	}
+
+	full := 1<<n - 1
+	best := make([][]float64, full+1)
+	parent := make([][]int, full+1)
+	for mask := range best {
+		best[mask] = make([]float64, n)
+		parent[mask] = make([]int, n)
+		for j := range best[mask] {
+			best[mask][j] = math.Inf(1)
+			parent[mask][j] = -1
+		}
+	}
+	for j := 0; j < n; j++ {
+		best[1<<j][j] = m.fromStart[j]
+	}
+
+	for mask := 1; mask <= full; mask++ {
+		for last := 0; last < n; last++ {
+			if mask&(1<<last) == 0 || math.IsInf(best[mask][last], 1) {
+				continue
+			}
+			for next := 0; next < n; next++ {
+				if mask&(1<<next) != 0 {
+					continue
+				}
+				nextMask := mask | 1<<next
+				if cost := best[mask][last] + m.between[last][next]; cost < best[nextMask][next] {
+					best[nextMask][next] = cost
+					parent[nextMask][next] = last
+				}
+			}
+		}
+	}
+
+	last := 0
+	for j := 1; j < n; j++ {
+		if best[full][j]+m.toEnd[j] < best[full][last]+m.toEnd[last] {
+			last = j
+		}
+	}
+
+	order := make([]int, n)
+	mask := full
+	for i := n - 1; i >= 0; i-- {
+		order[i] = last
+		prev := parent[mask][last]
+		mask &^= 1 << last
+		last = prev
+	}
+	return order
+}
+
+func (m distanceMatrix) nearestNeighbour() []int {
+	visited := make([]bool, m.n)
+	order := make([]int, 0, m.n)
+
+	for len(order) < m.n {
+		next := -1
+		for j := 0; j < m.n; j++ {
+			if visited[j] {
+				continue
+			}
+			if next == -1 || m.from(order, j) < m.from(order, next) {
+				next = j
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+	}
+	return order
+}
+
+// from adalah jarak dari posisi terakhir order (atau start) ke stop j
+func (m distanceMatrix) from(order []int, j int) float64 {
+	if len(order) == 0 {
+		return m.fromStart[j]
+	}
+	return m.between[order[len(order)-1]][j]
+}
+
+// twoOpt membalik segmen rute selama masih ada yang memperpendek jarak total
+func (m distanceMatrix) twoOpt(order []int) []int {
+	// edge menghitung jarak antar posisi; -1 = start, len(order) = end
+	edge := func(a, b int) float64 {
+		switch {
+		case a == -1:
+			return m.fromStart[order[b]]
+		case b == len(order):
+			return m.toEnd[order[a]]
+		default:
+			return m.between[order[a]][order[b]]
+		}
+	}
+
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(order)-1; i++ {
+			for k := i + 1; k < len(order); k++ {
+				before := edge(i-1, i) + edge(k, k+1)
+				order[i], order[k] = order[k], order[i]
+				after := edge(i-1, i) + edge(k, k+1)
+				order[i], order[k] = order[k], order[i]
+
+				if after < before-1e-9 {
+					for l, r := i, k; l < r; l, r = l+1, r-1 {
+						order[l], order[r] = order[r], order[l]
+					}
+					improved = true
+				}
+			}
+		}
+	}
+	return order
+}
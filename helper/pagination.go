@@ -0,0 +1,171 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// PageQuery adalah parameter pagination dari query string.
+// Mode page memakai ?page=&limit=, mode cursor memakai ?cursor=&limit=.
+type PageQuery struct {
+	Page       int
+	Limit      int
+	CursorMode bool
+	AfterID    uint
+}
+
+// PageMeta dikirim bersama list response sebagai "meta"
+type PageMeta struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Sort menentukan urutan untuk mode page. Mode cursor selalu berurutan
+// berdasarkan key (id) dengan arah yang sama.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// ErrCursorUnsupported dikembalikan Paginate jika mode cursor diminta dengan
+// Sort.Column, karena cursor hanya menyimpan id
+var ErrCursorUnsupported = errors.New("cursor pagination is not supported with this sort order")
+
+type cursorPayload struct {
+	ID uint `json:"id"`
+}
+
+// ParsePageQuery membaca page, limit dan cursor. limit dibatasi maxLimit.
+func ParsePageQuery(c echo.Context, defaultLimit, maxLimit int) (PageQuery, error) {
+	pq := PageQuery{Page: 1, Limit: defaultLimit}
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return pq, errors.New("limit must be a positive number")
+		}
+		pq.Limit = limit
+	}
+	if pq.Limit > maxLimit {
+		pq.Limit = maxLimit
+	}
+
+	if c.QueryParams().Has("cursor") {
+		pq.CursorMode = true
+		pq.Page = 0
+		if raw := c.QueryParam("cursor"); raw != "" {
+			id, err := decodeCursor(raw)
+			if err != nil {
+				return pq, errors.New("invalid cursor")
+			}
+			pq.AfterID = id
+		}
+		return pq, nil
+	}
+
+	if raw := c.QueryParam("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return pq, errors.New("page must be a positive number")
+		}
+		pq.Page = page
+	}
+
+	return pq, nil
+}
+
+func encodeCursor(id uint) string {
+	b, _ := json.Marshal(cursorPayload{ID: id})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(raw string) (uint, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, err
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return 0, err
+	}
+	if payload.ID == 0 {
+		return 0, errors.New("empty cursor")
+	}
+	return payload.ID, nil
+}
+
+// Paginate menghitung total lalu mengambil satu halaman ke dest (pointer ke slice
+// struct dengan field ID). query berisi filter saja; key adalah kolom id yang
+// dipakai sebagai cursor, misalnya "destinations.id". preloads diterapkan
+// setelah penghitungan total. Mode cursor dengan sort.Column menghasilkan
+// ErrCursorUnsupported.
+func Paginate(query *gorm.DB, pq PageQuery, key string, sort Sort, dest interface{}, preloads ...string) (PageMeta, error) {
+	meta := PageMeta{Limit: pq.Limit, Page: pq.Page}
+	if pq.CursorMode && sort.Column != "" {
+		return meta, ErrCursorUnsupported
+	}
+
+	if err := query.Session(&gorm.Session{}).Model(dest).Count(&meta.Total).Error; err != nil {
+		return meta, err
+	}
+
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+
+	page := query.Session(&gorm.Session{})
+	for _, preload := range preloads {
+		page = page.Preload(preload)
+	}
+
+	if pq.CursorMode {
+		if pq.AfterID != 0 {
+			if sort.Desc {
+				page = page.Where(key+" < ?", pq.AfterID)
+			} else {
+				page = page.Where(key+" > ?", pq.AfterID)
+			}
+		}
+
+		// Ambil satu baris lebih untuk mengetahui apakah masih ada halaman berikutnya
+		if err := page.Order(key + " " + direction).Limit(pq.Limit + 1).Find(dest).Error; err != nil {
+			return meta, err
+		}
+
+		rows := reflect.ValueOf(dest).Elem()
+		if rows.Len() > pq.Limit {
+			meta.HasMore = true
+			rows.SetLen(pq.Limit)
+			last := rows.Index(pq.Limit - 1)
+			meta.NextCursor = encodeCursor(uint(reflect.Indirect(last).FieldByName("ID").Uint()))
+		}
+		return meta, nil
+	}
+
+	if sort.Column != "" {
+		page = page.Order(sort.Column + " " + direction)
+	}
+	err := page.Order(key + " " + direction).
+		Offset((pq.Page - 1) * pq.Limit).
+		Limit(pq.Limit).
+		Find(dest).Error
+	if err != nil {
+		return meta, err
+	}
+
+	meta.TotalPages = int(math.Ceil(float64(meta.Total) / float64(pq.Limit)))
+	meta.HasMore = pq.Page < meta.TotalPages
+	return meta, nil
+}
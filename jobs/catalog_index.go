@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"backend/config"
+	"backend/retrieval"
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+// IndexCatalog membuat embedding untuk destinasi dan kota yang baru atau berubah
+// memakai config.Embedder. Tidak melakukan apa-apa jika embedding tidak dikonfigurasi.
+func IndexCatalog(ctx context.Context) (int, error) {
+	retriever := &retrieval.Retriever{DB: config.DB, Embedder: config.Embedder}
+	return retriever.Index(ctx, 50)
+}
+
+// CatalogIndexInterval adalah jeda antar putaran job embedding katalog
+// (CATALOG_INDEX_INTERVAL, default 1 jam; 0 mematikan job)
+func CatalogIndexInterval() time.Duration {
+	raw := os.Getenv("CATALOG_INDEX_INTERVAL")
+	if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+		return d
+	}
+	return time.Hour
+}
+
+// StartCatalogIndexer menjalankan IndexCatalog setiap interval sampai ctx selesai
+func StartCatalogIndexer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if indexed, err := IndexCatalog(ctx); err != nil {
+			log.Println("Catalog indexing failed:", err)
+		} else if indexed > 0 {
+			log.Printf("Indexed %d catalog entries", indexed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
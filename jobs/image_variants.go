@@ -0,0 +1,239 @@
+package jobs
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxVariantAttempts adalah jumlah percobaan sebelum gambar ditandai failed
+const MaxVariantAttempts = 3
+
+// HTTPClient dipakai untuk mengunduh gambar lama yang hanya berupa URL. Koneksi ke
+// alamat internal ditolak setelah DNS di-resolve, dan redirect hanya boleh ke host
+// yang diizinkan, supaya URL gambar tidak bisa dipakai untuk menjangkau jaringan
+// internal server.
+var HTTPClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{Timeout: 10 * time.Second, Control: publicAddressOnly}).DialContext,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 5 {
+			return errors.New("too many redirects")
+		}
+		return checkDownloadHost(req.URL)
+	},
+}
+
+// errDownloadHost menandai URL gambar di luar IMAGE_DOWNLOAD_HOSTS
+var errDownloadHost = errors.New("image host is not in IMAGE_DOWNLOAD_HOSTS")
+
+// checkDownloadHost hanya mengizinkan host dari IMAGE_DOWNLOAD_HOSTS (dipisah koma).
+// Tanpa daftar itu gambar berupa URL tidak diunduh sama sekali.
+func checkDownloadHost(u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range strings.Split(os.Getenv("IMAGE_DOWNLOAD_HOSTS"), ",") {
+		if allowed = strings.ToLower(strings.TrimSpace(allowed)); allowed != "" && allowed == host {
+			return nil
+		}
+	}
+	return fmt.Errorf("download %s: %w", u.Host, errDownloadHost)
+}
+
+// publicAddressOnly menolak koneksi ke loopback, jaringan privat, link-local (termasuk
+// metadata cloud 169.254.169.254) dan alamat kosong
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("refusing to connect to internal address %s", host)
+	}
+	return nil
+}
+
+// GenerateImageVariants membuat varian ukuran image dari data gambar aslinya lalu
+// menyimpannya. Jika gagal, percobaan dicatat dan status menjadi failed setelah
+// MaxVariantAttempts kali.
+func GenerateImageVariants(ctx context.Context, image *models.Image, data []byte) error {
+	variants, err := config.Uploads.Variants(ctx, data)
+	if err != nil {
+		recordVariantFailure(image)
+		return err
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("image_id = ?", image.ID).Delete(&models.ImageVariant{}).Error; err != nil {
+			return err
+		}
+
+		image.Variants = make([]models.ImageVariant, len(variants))
+		for i, variant := range variants {
+			image.Variants[i] = models.ImageVariant{
+				ImageID: image.ID,
+				Name:    variant.Name,
+				Key:     variant.Key,
+				Width:   variant.Width,
+				Height:  variant.Height,
+			}
+		}
+		if err := tx.Create(&image.Variants).Error; err != nil {
+			return err
+		}
+
+		image.VariantsStatus = models.ImageVariantsReady
+		return tx.Model(image).Update("variants_status", image.VariantsStatus).Error
+	})
+}
+
+func recordVariantFailure(image *models.Image) {
+	image.VariantAttempts++
+	if image.VariantAttempts >= MaxVariantAttempts {
+		image.VariantsStatus = models.ImageVariantsFailed
+	}
+	config.DB.Model(image).Updates(map[string]interface{}{
+		"variant_attempts": image.VariantAttempts,
+		"variants_status":  image.VariantsStatus,
+	})
+}
+
+// BackfillImageVariants memproses sampai batchSize gambar berstatus pending, termasuk
+// gambar lama yang hanya berupa URL, dan mengembalikan jumlah yang berhasil
+func BackfillImageVariants(ctx context.Context, batchSize int) (int, error) {
+	processed, _, err := backfillBatch(ctx, 0, batchSize)
+	return processed, err
+}
+
+// BackfillAllImageVariants memproses semua gambar pending per batch. Setiap gambar
+// dicoba paling banyak sekali per pemanggilan, sehingga gambar yang gagal tidak
+// menghabiskan MaxVariantAttempts dalam satu putaran karena error sementara.
+func BackfillAllImageVariants(ctx context.Context, batchSize int) (int, error) {
+	total := 0
+	var afterID uint
+	for {
+		processed, lastID, err := backfillBatch(ctx, afterID, batchSize)
+		total += processed
+		if err != nil || lastID == afterID {
+			return total, err
+		}
+		afterID = lastID
+	}
+}
+
+// backfillBatch memproses gambar pending dengan id setelah afterID dan mengembalikan
+// id terakhir yang dicoba (afterID jika tidak ada lagi)
+func backfillBatch(ctx context.Context, afterID uint, batchSize int) (int, uint, error) {
+	var images []models.Image
+	err := config.DB.Where("variants_status = ? AND id > ?", models.ImageVariantsPending, afterID).
+		Order("id").Limit(batchSize).Find(&images).Error
+	if err != nil {
+		return 0, afterID, err
+	}
+
+	processed := 0
+	lastID := afterID
+	for i := range images {
+		if ctx.Err() != nil {
+			return processed, lastID, ctx.Err()
+		}
+		lastID = images[i].ID
+
+		data, err := loadImage(ctx, images[i].URL)
+		if err == nil {
+			err = GenerateImageVariants(ctx, &images[i], data)
+		} else {
+			recordVariantFailure(&images[i])
+		}
+		if err != nil {
+			log.Printf("Failed to generate variants for image %d: %v", images[i].ID, err)
+			continue
+		}
+		processed++
+	}
+	return processed, lastID, nil
+}
+
+// BackfillInterval adalah jeda antar putaran job backfill varian gambar
+// (IMAGE_VARIANT_BACKFILL_INTERVAL, default 5 menit; 0 mematikan job)
+func BackfillInterval() time.Duration {
+	raw := os.Getenv("IMAGE_VARIANT_BACKFILL_INTERVAL")
+	if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+		return d
+	}
+	return 5 * time.Minute
+}
+
+// StartImageVariantBackfill menjalankan BackfillImageVariants setiap interval sampai ctx selesai
+func StartImageVariantBackfill(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := BackfillAllImageVariants(ctx, 20); err != nil {
+			log.Println("Image variant backfill failed:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadImage mengambil gambar asli dari Storage (key) atau mengunduhnya (URL absolut)
+func loadImage(ctx context.Context, source string) ([]byte, error) {
+	var reader io.ReadCloser
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkDownloadHost(req.URL); err != nil {
+			return nil, err
+		}
+		resp, err := HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("download %s: %s", source, resp.Status)
+		}
+		reader = resp.Body
+	} else {
+		object, err := config.Storage.Get(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		reader = object
+	}
+	defer reader.Close()
+
+	// Batas ukuran sama dengan upload biasa
+	data, err := io.ReadAll(io.LimitReader(reader, config.Uploads.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > config.Uploads.MaxBytes {
+		return nil, errors.New("image is larger than the upload limit")
+	}
+	return data, nil
+}
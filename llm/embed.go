@@ -0,0 +1,240 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Embedder mengubah teks menjadi vektor untuk pencarian kemiripan. Implementasi
+// dipilih lewat EMBEDDING_PROVIDER.
+type Embedder interface {
+	// Name mengidentifikasi model; vektor dari model berbeda tidak bisa dibandingkan
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedderFromEnv membuat Embedder sesuai EMBEDDING_PROVIDER: gemini, openai, atau
+// local. Kosong atau none mengembalikan nil (pencarian katalog hanya dengan kata kunci).
+func EmbedderFromEnv() (Embedder, error) {
+	retry, err := retryFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(os.Getenv("EMBEDDING_PROVIDER")) {
+	case "", "none":
+		return nil, nil
+	case "gemini":
+		model := os.Getenv("GEMINI_EMBEDDING_MODEL")
+		if model == "" {
+			model = "text-embedding-004"
+		}
+		url := os.Getenv("GEMINI_EMBEDDING_URL")
+		if url == "" {
+			url = "https://generativelanguage.googleapis.com/v1beta/models/" + model + ":batchEmbedContents"
+		}
+		if os.Getenv("GEMINI_API_KEY") == "" {
+			return nil, fmt.Errorf("GEMINI_API_KEY is required for EMBEDDING_PROVIDER=gemini")
+		}
+		return &GeminiEmbedder{URL: url, Model: model, APIKey: os.Getenv("GEMINI_API_KEY"), Retry: retry}, nil
+	case "openai":
+		embedder := &OpenAIEmbedder{
+			BaseURL: os.Getenv("OPENAI_BASE_URL"),
+			APIKey:  os.Getenv("OPENAI_API_KEY"),
+			Model:   os.Getenv("OPENAI_EMBEDDING_MODEL"),
+			Retry:   retry,
+		}
+		if embedder.BaseURL == "" {
+			embedder.BaseURL = "https://api.openai.com/v1"
+		}
+		if embedder.Model == "" {
+			embedder.Model = "text-embedding-3-small"
+		}
+		return embedder, nil
+	case "local":
+		return NewLocalEmbedder(256), nil
+	default:
+		return nil, fmt.Errorf("unsupported EMBEDDING_PROVIDER %q", os.Getenv("EMBEDDING_PROVIDER"))
+	}
+}
+
+// GeminiEmbedder memanggil endpoint batchEmbedContents Gemini
+type GeminiEmbedder struct {
+	URL    string
+	Model  string
+	APIKey string
+	Retry  Retry
+	Client *http.Client
+}
+
+func (g *GeminiEmbedder) Name() string { return "gemini/" + g.Model }
+
+func (g *GeminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	requests := make([]map[string]interface{}, len(texts))
+	for i, text := range texts {
+		requests[i] = map[string]interface{}{
+			"model":   "models/" + g.Model,
+			"content": geminiContent{Parts: []geminiPart{{Text: text}}},
+		}
+	}
+
+	var vectors [][]float32
+	err := g.Retry.do(ctx, func(ctx context.Context) error {
+		resp, err := postJSON(ctx, g.Client, "gemini", g.URL, geminiHeader(g.APIKey), map[string]interface{}{"requests": requests})
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		var body struct {
+			Embeddings []struct {
+				Values []float32 `json:"values"`
+			} `json:"embeddings"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return fmt.Errorf("gemini: decode embeddings: %w", err)
+		}
+		if len(body.Embeddings) != len(texts) {
+			return fmt.Errorf("gemini: %w: got %d embeddings for %d texts", ErrEmptyResponse, len(body.Embeddings), len(texts))
+		}
+		vectors = make([][]float32, len(texts))
+		for i, embedding := range body.Embeddings {
+			vectors[i] = embedding.Values
+		}
+		return nil
+	})
+	return vectors, err
+}
+
+// OpenAIEmbedder memanggil endpoint /embeddings yang kompatibel dengan API OpenAI
+type OpenAIEmbedder struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	Retry   Retry
+	Client  *http.Client
+}
+
+func (o *OpenAIEmbedder) Name() string { return "openai/" + o.Model }
+
+func (o *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	header := http.Header{}
+	if o.APIKey != "" {
+		header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+
+	var vectors [][]float32
+	err := o.Retry.do(ctx, func(ctx context.Context) error {
+		url := strings.TrimSuffix(o.BaseURL, "/") + "/embeddings"
+		resp, err := postJSON(ctx, o.Client, "openai", url, header, map[string]interface{}{"model": o.Model, "input": texts})
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		var body struct {
+			Data []struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return fmt.Errorf("openai: decode embeddings: %w", err)
+		}
+		vectors = make([][]float32, len(texts))
+		for _, item := range body.Data {
+			if item.Index >= 0 && item.Index < len(vectors) {
+				vectors[item.Index] = item.Embedding
+			}
+		}
+		for _, vector := range vectors {
+			if vector == nil {
+				return fmt.Errorf("openai: %w: missing embeddings", ErrEmptyResponse)
+			}
+		}
+		return nil
+	})
+	return vectors, err
+}
+
+// LocalEmbedder membuat vektor tanpa API eksternal dengan feature hashing kata dan
+// trigram huruf. Hasilnya deterministik sehingga cocok untuk test dan pengembangan
+// lokal; kemiripannya leksikal, bukan semantik.
+type LocalEmbedder struct {
+	Dimensions int
+}
+
+func NewLocalEmbedder(dimensions int) *LocalEmbedder {
+	return &LocalEmbedder{Dimensions: dimensions}
+}
+
+func (l *LocalEmbedder) Name() string { return fmt.Sprintf("local/hash-%d", l.Dimensions) }
+
+func (l *LocalEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = l.embed(text)
+	}
+	return vectors, ctx.Err()
+}
+
+func (l *LocalEmbedder) embed(text string) []float32 {
+	vector := make([]float32, l.Dimensions)
+	add := func(feature string, weight float32) {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+		// Bit teratas menentukan tanda supaya tabrakan hash saling meniadakan
+		if sum&(1<<31) != 0 {
+			weight = -weight
+		}
+		vector[int(sum%uint32(l.Dimensions))] += weight
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		add("w:"+word, 1)
+		padded := []rune("_" + word + "_")
+		for j := 0; j+3 <= len(padded); j++ {
+			add("t:"+string(padded[j:j+3]), 0.5)
+		}
+	}
+
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for j := range vector {
+			vector[j] *= scale
+		}
+	}
+	return vector
+}
+
+// Cosine menghitung kemiripan kosinus dua vektor; 0 jika panjangnya berbeda
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,251 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Gemini memanggil API generateContent Google Gemini
+type Gemini struct {
+	// URL adalah endpoint generateContent model, mis.
+	// https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash-latest:generateContent
+	URL string
+	// StreamURL adalah endpoint streamGenerateContent; default diturunkan dari URL
+	StreamURL string
+	APIKey    string
+	Retry     Retry
+	// Client default http.DefaultClient; batas waktu diatur lewat Retry.Timeout
+	Client *http.Client
+}
+
+type geminiContent struct {
+	Role  string       `json:"role"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+func (r *geminiResponse) text() string {
+	var b strings.Builder
+	for _, candidate := range r.Candidates {
+		for _, part := range candidate.Content.Parts {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
+
+// toolCalls mengumpulkan functionCall dari jawaban. Gemini tidak memberi ID sehingga
+// ID dibuat dari urutannya; hasilnya dikirim balik berdasarkan nama tool.
+func (r *geminiResponse) toolCalls(offset int) []ToolCall {
+	var calls []ToolCall
+	for _, candidate := range r.Candidates {
+		for _, part := range candidate.Content.Parts {
+			if part.FunctionCall == nil {
+				continue
+			}
+			calls = append(calls, ToolCall{
+				ID:        fmt.Sprintf("call_%d", offset+len(calls)+1),
+				Name:      part.FunctionCall.Name,
+				Arguments: toolArguments(string(part.FunctionCall.Args)),
+			})
+		}
+	}
+	return calls
+}
+
+func (r *geminiResponse) usage() Usage {
+	return Usage{
+		PromptTokens:     r.UsageMetadata.PromptTokenCount,
+		CompletionTokens: r.UsageMetadata.CandidatesTokenCount,
+		TotalTokens:      r.UsageMetadata.TotalTokenCount,
+	}
+}
+
+// blocked mengembalikan ErrRejected jika filter keamanan Gemini memblokir prompt atau jawaban
+func (r *geminiResponse) blocked() error {
+	if r.PromptFeedback.BlockReason != "" {
+		return fmt.Errorf("gemini: %w: prompt blocked (%s)", ErrRejected, r.PromptFeedback.BlockReason)
+	}
+	for _, candidate := range r.Candidates {
+		if candidate.FinishReason == "SAFETY" || candidate.FinishReason == "PROHIBITED_CONTENT" {
+			return fmt.Errorf("gemini: %w: response blocked (%s)", ErrRejected, candidate.FinishReason)
+		}
+	}
+	return nil
+}
+
+func (g *Gemini) Chat(ctx context.Context, messages []Message, tools ...Tool) (Reply, error) {
+	var reply Reply
+	err := g.Retry.do(ctx, func(ctx context.Context) error {
+		resp, err := g.post(ctx, g.URL, messages, tools)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		var body geminiResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return fmt.Errorf("gemini: decode response: %w", err)
+		}
+		reply.Text = body.text()
+		reply.ToolCalls = body.toolCalls(0)
+		if reply.Text == "" && len(reply.ToolCalls) == 0 {
+			if err := body.blocked(); err != nil {
+				return err
+			}
+			return fmt.Errorf("gemini: %w", ErrEmptyResponse)
+		}
+		reply.Usage = body.usage()
+		return nil
+	})
+	return reply, err
+}
+
+func (g *Gemini) Stream(ctx context.Context, messages []Message, onText func(text string) error, tools ...Tool) (Reply, error) {
+	var reply Reply
+	err := g.Retry.doStream(ctx, func(ctx context.Context, alive func()) (bool, error) {
+		resp, err := g.post(ctx, g.streamURL()+"?alt=sse", messages, tools)
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+
+		reply = Reply{}
+		var text strings.Builder
+		started := false
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			alive()
+			data, ok := strings.CutPrefix(strings.TrimRight(scanner.Text(), "\r"), "data:")
+			if !ok {
+				continue
+			}
+
+			var chunk geminiResponse
+			if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &chunk); err != nil {
+				return started, fmt.Errorf("gemini: decode stream chunk: %w", err)
+			}
+			if err := chunk.blocked(); err != nil {
+				return started, err
+			}
+			if chunk.UsageMetadata.TotalTokenCount > 0 {
+				reply.Usage = chunk.usage()
+			}
+			// functionCall selalu dikirim utuh dalam satu potongan
+			reply.ToolCalls = append(reply.ToolCalls, chunk.toolCalls(len(reply.ToolCalls))...)
+			if piece := chunk.text(); piece != "" {
+				started = true
+				text.WriteString(piece)
+				if err := onText(piece); err != nil {
+					return started, callbackError{err}
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return started, fmt.Errorf("gemini: read stream: %w", err)
+		}
+		reply.Text = text.String()
+		if !started && len(reply.ToolCalls) == 0 {
+			return false, fmt.Errorf("gemini: %w", ErrEmptyResponse)
+		}
+		return started, nil
+	})
+	return reply, err
+}
+
+// streamURL mengganti :generateContent menjadi :streamGenerateContent jika StreamURL kosong
+func (g *Gemini) streamURL() string {
+	if g.StreamURL != "" {
+		return g.StreamURL
+	}
+	return strings.Replace(g.URL, ":generateContent", ":streamGenerateContent", 1)
+}
+
+// post mengirim percakapan; pesan RoleSystem digabung menjadi systemInstruction dan
+// hasil tool yang berurutan digabung menjadi satu giliran user berisi functionResponse
+func (g *Gemini) post(ctx context.Context, url string, messages []Message, tools []Tool) (*http.Response, error) {
+	if err := checkMessages("gemini", messages); err != nil {
+		return nil, err
+	}
+
+	var system []geminiPart
+	contents := make([]geminiContent, 0, len(messages))
+	for _, message := range messages {
+		switch message.Role {
+		case RoleSystem:
+			system = append(system, geminiPart{Text: message.Content})
+		case RoleTool:
+			part := geminiPart{FunctionResponse: &geminiFunctionResponse{Name: message.ToolName, Response: toolResult(message.Content)}}
+			if n := len(contents); n > 0 && contents[n-1].Parts[0].FunctionResponse != nil {
+				contents[n-1].Parts = append(contents[n-1].Parts, part)
+				continue
+			}
+			contents = append(contents, geminiContent{Role: RoleUser, Parts: []geminiPart{part}})
+		default:
+			var parts []geminiPart
+			if message.Content != "" || len(message.ToolCalls) == 0 {
+				parts = append(parts, geminiPart{Text: message.Content})
+			}
+			for _, call := range message.ToolCalls {
+				parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{Name: call.Name, Args: call.Arguments}})
+			}
+			contents = append(contents, geminiContent{Role: message.Role, Parts: parts})
+		}
+	}
+	payload := map[string]interface{}{"contents": contents}
+	if len(system) > 0 {
+		payload["systemInstruction"] = map[string]interface{}{"parts": system}
+	}
+	if len(tools) > 0 {
+		declarations := make([]map[string]interface{}, len(tools))
+		for i, tool := range tools {
+			declarations[i] = map[string]interface{}{"name": tool.Name, "description": tool.Description}
+			if len(tool.Parameters) > 0 {
+				declarations[i]["parameters"] = tool.Parameters
+			}
+		}
+		payload["tools"] = []map[string]interface{}{{"functionDeclarations": declarations}}
+	}
+	return postJSON(ctx, g.Client, "gemini", url, geminiHeader(g.APIKey), payload)
+}
+
+// geminiHeader mengirim API key lewat header, bukan query ?key=, supaya key tidak ikut
+// tertulis di log saat *url.Error dari transport mencantumkan URL lengkap
+func geminiHeader(apiKey string) http.Header {
+	return http.Header{"X-Goog-Api-Key": {apiKey}}
+}
@@ -0,0 +1,410 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Role giliran percakapan. Provider menerjemahkannya ke istilah API masing-masing
+// (mis. "model" menjadi "assistant" di API OpenAI). Pesan RoleSystem berisi instruksi
+// untuk model dan bukan bagian dari percakapan. Pesan RoleTool berisi hasil tool yang
+// diminta model pada giliran sebelumnya.
+const (
+	RoleUser   = "user"
+	RoleModel  = "model"
+	RoleSystem = "system"
+	RoleTool   = "tool"
+)
+
+// Message adalah satu giliran percakapan yang dikirim ke provider
+type Message struct {
+	Role    string
+	Content string
+	// ToolCalls diisi pada pesan RoleModel yang meminta tool dijalankan
+	ToolCalls []ToolCall
+	// ToolCallID dan ToolName diisi pada pesan RoleTool; Content berisi hasil tool
+	// sebagai objek JSON
+	ToolCallID string
+	ToolName   string
+}
+
+// Tool adalah fungsi aplikasi yang boleh dipanggil model. Parameters adalah JSON
+// Schema (type "object") untuk argumennya.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage
+}
+
+// ToolCall adalah permintaan model untuk menjalankan tool. Arguments berupa objek JSON.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments json.RawMessage
+}
+
+// Usage adalah jumlah token yang dipakai satu permintaan
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Add menjumlahkan pemakaian beberapa permintaan
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+	}
+}
+
+// Reply adalah jawaban lengkap dari provider. Jika ToolCalls diisi, model meminta
+// tool dijalankan dan hasilnya dikirim kembali sebelum model menjawab; Text boleh kosong.
+type Reply struct {
+	Text      string
+	Usage     Usage
+	ToolCalls []ToolCall
+}
+
+// ChatProvider mengirim percakapan ke model bahasa. Giliran terakhir adalah pesan
+// user yang ingin dijawab (atau hasil tool), giliran sebelumnya menjadi konteks.
+// tools adalah fungsi yang boleh diminta model. Implementasi dipilih lewat LLM_PROVIDER.
+type ChatProvider interface {
+	Chat(ctx context.Context, messages []Message, tools ...Tool) (Reply, error)
+	// Stream memanggil onText untuk setiap potongan jawaban. Jika onText mengembalikan
+	// error atau ctx dibatalkan, stream dihentikan. Reply berisi teks lengkap, usage,
+	// dan tool yang diminta model.
+	Stream(ctx context.Context, messages []Message, onText func(text string) error, tools ...Tool) (Reply, error)
+}
+
+// Error yang dikembalikan provider; cek dengan errors.Is
+var (
+	// ErrRateLimited: provider membatasi permintaan (HTTP 429)
+	ErrRateLimited = errors.New("provider rate limit exceeded")
+	// ErrTimeout: provider tidak menjawab dalam batas waktu
+	ErrTimeout = errors.New("provider timed out")
+	// ErrUnavailable: provider error (5xx) atau tidak bisa dihubungi
+	ErrUnavailable = errors.New("provider unavailable")
+	// ErrRejected: provider menolak isi permintaan (terlalu panjang, diblokir filter keamanan)
+	ErrRejected = errors.New("provider rejected the request")
+	// ErrMisconfigured: API key atau endpoint provider salah (401, 403, 404)
+	ErrMisconfigured = errors.New("provider misconfigured")
+	// ErrEmptyResponse: provider menjawab tanpa teks maupun pemanggilan tool
+	ErrEmptyResponse = errors.New("provider returned an empty response")
+)
+
+// StatusError adalah respons non-2xx dari provider. Unwrap mengembalikan salah satu
+// error di atas sesuai status code.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	// RetryAfter diisi dari header Retry-After jika ada
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusNotFound:
+		return ErrMisconfigured
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	default:
+		return ErrRejected
+	}
+}
+
+// newStatusError membaca (sebagian) body respons gagal sebagai pesan error
+func newStatusError(provider string, resp *http.Response) *StatusError {
+	body := make([]byte, 512)
+	n, _ := resp.Body.Read(body)
+	err := &StatusError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body[:n])),
+	}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		err.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+// postJSON mengirim payload sebagai JSON; respons non-200 dikembalikan sebagai *StatusError
+func postJSON(ctx context.Context, client *http.Client, provider, url string, header http.Header, payload interface{}) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s: marshal request: %w", provider, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", provider, ErrMisconfigured, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", provider, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newStatusError(provider, resp)
+	}
+	return resp, nil
+}
+
+// Retry mengatur timeout dan percobaan ulang untuk error sementara
+// (ErrRateLimited, ErrTimeout, ErrUnavailable)
+type Retry struct {
+	// Timeout per percobaan; untuk Stream berlaku sebagai batas waktu tanpa data.
+	// Nol berarti DefaultRetry.Timeout.
+	Timeout time.Duration
+	// MaxRetries adalah jumlah percobaan ulang setelah percobaan pertama
+	MaxRetries int
+	// Backoff adalah jeda sebelum percobaan ulang pertama, dua kali lipat setiap
+	// percobaan berikutnya dan tidak lebih dari MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetry dipakai jika LLM_TIMEOUT, LLM_MAX_RETRIES, atau LLM_RETRY_BACKOFF tidak diisi
+var DefaultRetry = Retry{
+	Timeout:    60 * time.Second,
+	MaxRetries: 2,
+	Backoff:    500 * time.Millisecond,
+	MaxBackoff: 8 * time.Second,
+}
+
+// timeout adalah Timeout, atau DefaultRetry.Timeout jika tidak diisi, supaya provider
+// yang dibuat tanpa Retry tidak langsung timeout
+func (r Retry) timeout() time.Duration {
+	if r.Timeout <= 0 {
+		return DefaultRetry.Timeout
+	}
+	return r.Timeout
+}
+
+// retryable menandai error sementara yang layak dicoba ulang
+func retryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTimeout) || errors.Is(err, ErrUnavailable)
+}
+
+// do menjalankan attempt sampai berhasil, error-nya bukan error sementara, atau
+// percobaan habis. Setiap percobaan mendapat context dengan batas waktu Timeout.
+func (r Retry) do(ctx context.Context, attempt func(ctx context.Context) error) error {
+	for n := 0; ; n++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, r.timeout())
+		err := attempt(attemptCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		err = classify(ctx, err)
+		if !retryable(err) || n >= r.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.delay(n, err)):
+		}
+	}
+}
+
+// errIdle membatalkan stream yang terlalu lama tidak mengirim data
+var errIdle = errors.New("stream idle")
+
+// callbackError membungkus error dari onText supaya dikembalikan apa adanya
+type callbackError struct{ err error }
+
+func (e callbackError) Error() string { return e.err.Error() }
+
+// doStream seperti do, tetapi Timeout berlaku sebagai batas waktu tanpa data: attempt
+// memanggil alive setiap kali data diterima. Percobaan ulang hanya dilakukan selama
+// belum ada teks yang diteruskan ke pemanggil (started false).
+func (r Retry) doStream(ctx context.Context, attempt func(ctx context.Context, alive func()) (started bool, err error)) error {
+	for n := 0; ; n++ {
+		attemptCtx, cancel := context.WithCancelCause(ctx)
+		timer := time.AfterFunc(r.timeout(), func() { cancel(errIdle) })
+		started, err := attempt(attemptCtx, func() { timer.Reset(r.timeout()) })
+		timer.Stop()
+		idle := errors.Is(context.Cause(attemptCtx), errIdle)
+		cancel(nil)
+		if err == nil {
+			return nil
+		}
+
+		var callbackErr callbackError
+		if errors.As(err, &callbackErr) {
+			return callbackErr.err
+		}
+		if idle && ctx.Err() == nil {
+			err = fmt.Errorf("%w: no data for %s", ErrTimeout, r.timeout())
+		}
+		err = classify(ctx, err)
+		if started || !retryable(err) || n >= r.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.delay(n, err)):
+		}
+	}
+}
+
+// delay menghitung jeda sebelum percobaan ulang ke-n+1: backoff eksponensial dengan
+// jitter, atau Retry-After dari provider jika lebih lama
+func (r Retry) delay(n int, err error) time.Duration {
+	d := r.Backoff << n
+	// d <= 0 berarti shift overflow
+	if r.MaxBackoff > 0 && (d > r.MaxBackoff || (r.Backoff > 0 && d <= 0)) {
+		d = r.MaxBackoff
+	}
+	if d > 0 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > d && (r.MaxBackoff == 0 || statusErr.RetryAfter <= r.MaxBackoff) {
+		d = statusErr.RetryAfter
+	}
+	return d
+}
+
+// classify mengubah error jaringan menjadi error bertipe. Pembatalan dari pemanggil
+// (client terputus) dikembalikan apa adanya dan tidak dicoba ulang.
+func classify(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if retryable(err) || errors.Is(err, ErrRejected) || errors.Is(err, ErrMisconfigured) || errors.Is(err, ErrEmptyResponse) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
+
+// FromEnv membuat ChatProvider sesuai LLM_PROVIDER: gemini (default), openai, atau mock
+func FromEnv() (ChatProvider, error) {
+	retry, err := retryFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(os.Getenv("LLM_PROVIDER")) {
+	case "", "gemini":
+		gemini := &Gemini{
+			URL:       os.Getenv("GEMINI_BASE_URL"),
+			StreamURL: os.Getenv("GEMINI_STREAM_URL"),
+			APIKey:    os.Getenv("GEMINI_API_KEY"),
+			Retry:     retry,
+		}
+		if gemini.URL == "" || gemini.APIKey == "" {
+			return nil, fmt.Errorf("GEMINI_BASE_URL and GEMINI_API_KEY are required for LLM_PROVIDER=gemini")
+		}
+		return gemini, nil
+	case "openai":
+		openAI := &OpenAI{
+			BaseURL: os.Getenv("OPENAI_BASE_URL"),
+			APIKey:  os.Getenv("OPENAI_API_KEY"),
+			Model:   os.Getenv("OPENAI_MODEL"),
+			Retry:   retry,
+		}
+		if openAI.BaseURL == "" {
+			openAI.BaseURL = "https://api.openai.com/v1"
+		}
+		if openAI.Model == "" {
+			return nil, fmt.Errorf("OPENAI_MODEL is required for LLM_PROVIDER=openai")
+		}
+		return openAI, nil
+	case "mock":
+		return NewMock(), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM_PROVIDER %q", os.Getenv("LLM_PROVIDER"))
+	}
+}
+
+func retryFromEnv() (Retry, error) {
+	retry := DefaultRetry
+	if raw := os.Getenv("LLM_TIMEOUT"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return retry, fmt.Errorf("invalid LLM_TIMEOUT %q", raw)
+		}
+		retry.Timeout = d
+	}
+	if raw := os.Getenv("LLM_MAX_RETRIES"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return retry, fmt.Errorf("invalid LLM_MAX_RETRIES %q", raw)
+		}
+		retry.MaxRetries = n
+	}
+	if raw := os.Getenv("LLM_RETRY_BACKOFF"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return retry, fmt.Errorf("invalid LLM_RETRY_BACKOFF %q", raw)
+		}
+		retry.Backoff = d
+	}
+	return retry, nil
+}
+
+// checkMessages memastikan percakapan berakhir dengan pesan user atau hasil tool
+func checkMessages(provider string, messages []Message) error {
+	if len(messages) == 0 {
+		return fmt.Errorf("%s: %w: conversation is empty", provider, ErrRejected)
+	}
+	if last := messages[len(messages)-1].Role; last != RoleUser && last != RoleTool {
+		return fmt.Errorf("%s: %w: conversation must end with a user message or tool result", provider, ErrRejected)
+	}
+	return nil
+}
+
+// toolArguments mengembalikan argumen tool sebagai objek JSON; kosong menjadi {}
+func toolArguments(raw string) json.RawMessage {
+	if strings.TrimSpace(raw) == "" {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(raw)
+}
+
+// toolResult memastikan hasil tool berupa objek JSON; teks lain dibungkus sebagai
+// {"result": ...} karena Gemini hanya menerima objek
+func toolResult(content string) json.RawMessage {
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	wrapped, _ := json.Marshal(map[string]string{"result": content})
+	return wrapped
+}
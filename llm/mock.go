@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Mock adalah ChatProvider deterministik untuk test dan pengembangan lokal
+// (LLM_PROVIDER=mock). Jawaban diambil dari Respond; default menggemakan pesan
+// terakhir. Token dihitung per kata.
+type Mock struct {
+	// Respond menentukan jawaban atau error untuk satu permintaan
+	Respond func(messages []Message) (string, error)
+	// RespondWithTools, jika diisi, dipakai sebagai ganti Respond dan bisa meminta
+	// tool dijalankan lewat Reply.ToolCalls
+	RespondWithTools func(messages []Message, tools []Tool) (Reply, error)
+
+	mu    sync.Mutex
+	calls [][]Message
+}
+
+func NewMock() *Mock {
+	return &Mock{Respond: func(messages []Message) (string, error) {
+		return "You said: " + messages[len(messages)-1].Content, nil
+	}}
+}
+
+// Calls mengembalikan semua percakapan yang pernah dikirim ke Mock
+func (m *Mock) Calls() [][]Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]Message(nil), m.calls...)
+}
+
+func (m *Mock) Chat(ctx context.Context, messages []Message, tools ...Tool) (Reply, error) {
+	return m.respond(ctx, messages, tools)
+}
+
+// Stream mengirim teks jawaban per kata
+func (m *Mock) Stream(ctx context.Context, messages []Message, onText func(text string) error, tools ...Tool) (Reply, error) {
+	reply, err := m.respond(ctx, messages, tools)
+	if err != nil || reply.Text == "" {
+		return reply, err
+	}
+	// Seperti provider sungguhan, pemakaian token tetap dilaporkan jika stream terputus
+	for _, word := range strings.SplitAfter(reply.Text, " ") {
+		if err := ctx.Err(); err != nil {
+			return Reply{Usage: reply.Usage}, err
+		}
+		if err := onText(word); err != nil {
+			return Reply{Usage: reply.Usage}, err
+		}
+	}
+	return reply, nil
+}
+
+func (m *Mock) respond(ctx context.Context, messages []Message, tools []Tool) (Reply, error) {
+	if err := checkMessages("mock", messages); err != nil {
+		return Reply{}, err
+	}
+	m.mu.Lock()
+	m.calls = append(m.calls, append([]Message(nil), messages...))
+	m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return Reply{}, err
+	}
+	var reply Reply
+	var err error
+	if m.RespondWithTools != nil {
+		reply, err = m.RespondWithTools(messages, tools)
+	} else {
+		reply.Text, err = m.Respond(messages)
+	}
+	if err != nil {
+		return Reply{}, err
+	}
+	if reply.Text == "" && len(reply.ToolCalls) == 0 {
+		return Reply{}, ErrEmptyResponse
+	}
+	reply.Usage = mockUsage(messages, reply.Text)
+	return reply, nil
+}
+
+func mockUsage(messages []Message, reply string) Usage {
+	prompt := 0
+	for _, message := range messages {
+		prompt += len(strings.Fields(message.Content))
+	}
+	completion := len(strings.Fields(reply))
+	return Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+}
@@ -0,0 +1,224 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OpenAI memanggil endpoint /chat/completions yang kompatibel dengan API OpenAI
+// (OpenAI, Azure OpenAI, OpenRouter, Ollama, vLLM, dan sejenisnya)
+type OpenAI struct {
+	// BaseURL tanpa /chat/completions, mis. https://api.openai.com/v1
+	BaseURL string
+	// APIKey dikirim sebagai Bearer token; boleh kosong untuk server lokal
+	APIKey string
+	Model  string
+	Retry  Retry
+	// Client default http.DefaultClient; batas waktu diatur lewat Retry.Timeout
+	Client *http.Client
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	// Index hanya ada di potongan stream untuk menyambung argumen yang terpecah
+	Index    *int   `json:"index,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		Delta        openAIMessage `json:"delta"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+}
+
+// maxStreamToolCalls membatasi index tool call dari stream yang disambung
+const maxStreamToolCalls = 32
+
+// rejected mengembalikan ErrRejected jika jawaban dihentikan filter konten
+func (r *openAIResponse) rejected() error {
+	for _, choice := range r.Choices {
+		if choice.FinishReason == "content_filter" {
+			return fmt.Errorf("openai: %w: response blocked (content_filter)", ErrRejected)
+		}
+	}
+	return nil
+}
+
+func (o *OpenAI) Chat(ctx context.Context, messages []Message, tools ...Tool) (Reply, error) {
+	var reply Reply
+	err := o.Retry.do(ctx, func(ctx context.Context) error {
+		resp, err := o.post(ctx, messages, tools, false)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		var body openAIResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return fmt.Errorf("openai: decode response: %w", err)
+		}
+		if len(body.Choices) == 0 || (body.Choices[0].Message.Content == "" && len(body.Choices[0].Message.ToolCalls) == 0) {
+			if err := body.rejected(); err != nil {
+				return err
+			}
+			return fmt.Errorf("openai: %w", ErrEmptyResponse)
+		}
+		reply.Text = body.Choices[0].Message.Content
+		reply.ToolCalls = nil
+		for _, call := range body.Choices[0].Message.ToolCalls {
+			reply.ToolCalls = append(reply.ToolCalls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: toolArguments(call.Function.Arguments)})
+		}
+		if body.Usage != nil {
+			reply.Usage = *body.Usage
+		}
+		return nil
+	})
+	return reply, err
+}
+
+func (o *OpenAI) Stream(ctx context.Context, messages []Message, onText func(text string) error, tools ...Tool) (Reply, error) {
+	var reply Reply
+	err := o.Retry.doStream(ctx, func(ctx context.Context, alive func()) (bool, error) {
+		resp, err := o.post(ctx, messages, tools, true)
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+
+		reply = Reply{}
+		var text strings.Builder
+		// Argumen tool dikirim sepotong-sepotong; disambung berdasarkan index
+		var calls []*openAIToolCall
+		started := false
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			alive()
+			data, ok := strings.CutPrefix(strings.TrimRight(scanner.Text(), "\r"), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				break
+			}
+
+			var chunk openAIResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return started, fmt.Errorf("openai: decode stream chunk: %w", err)
+			}
+			if err := chunk.rejected(); err != nil {
+				return started, err
+			}
+			if chunk.Usage != nil {
+				reply.Usage = *chunk.Usage
+			}
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+			for _, delta := range chunk.Choices[0].Delta.ToolCalls {
+				index := len(calls)
+				if delta.Index != nil {
+					index = *delta.Index
+				}
+				if index < 0 || index >= maxStreamToolCalls {
+					continue
+				}
+				for len(calls) <= index {
+					calls = append(calls, &openAIToolCall{})
+				}
+				call := calls[index]
+				if delta.ID != "" {
+					call.ID = delta.ID
+				}
+				if delta.Function.Name != "" {
+					call.Function.Name = delta.Function.Name
+				}
+				call.Function.Arguments += delta.Function.Arguments
+			}
+			if piece := chunk.Choices[0].Delta.Content; piece != "" {
+				started = true
+				text.WriteString(piece)
+				if err := onText(piece); err != nil {
+					return started, callbackError{err}
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return started, fmt.Errorf("openai: read stream: %w", err)
+		}
+		reply.Text = text.String()
+		for _, call := range calls {
+			if call.Function.Name != "" {
+				reply.ToolCalls = append(reply.ToolCalls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: toolArguments(call.Function.Arguments)})
+			}
+		}
+		if !started && len(reply.ToolCalls) == 0 {
+			return false, fmt.Errorf("openai: %w", ErrEmptyResponse)
+		}
+		return started, nil
+	})
+	return reply, err
+}
+
+// post mengirim percakapan ke /chat/completions
+func (o *OpenAI) post(ctx context.Context, messages []Message, tools []Tool, stream bool) (*http.Response, error) {
+	if err := checkMessages("openai", messages); err != nil {
+		return nil, err
+	}
+
+	chat := make([]openAIMessage, len(messages))
+	for i, message := range messages {
+		role := message.Role
+		if role == RoleModel {
+			role = "assistant"
+		}
+		chat[i] = openAIMessage{Role: role, Content: message.Content, ToolCallID: message.ToolCallID}
+		for _, call := range message.ToolCalls {
+			converted := openAIToolCall{ID: call.ID, Type: "function"}
+			converted.Function.Name = call.Name
+			converted.Function.Arguments = string(call.Arguments)
+			chat[i].ToolCalls = append(chat[i].ToolCalls, converted)
+		}
+	}
+	request := map[string]interface{}{"model": o.Model, "messages": chat}
+	if len(tools) > 0 {
+		functions := make([]map[string]interface{}, len(tools))
+		for i, tool := range tools {
+			function := map[string]interface{}{"name": tool.Name, "description": tool.Description}
+			if len(tool.Parameters) > 0 {
+				function["parameters"] = tool.Parameters
+			}
+			functions[i] = map[string]interface{}{"type": "function", "function": function}
+		}
+		request["tools"] = functions
+	}
+	if stream {
+		request["stream"] = true
+		// Usage hanya dikirim di chunk terakhir jika diminta
+		request["stream_options"] = map[string]bool{"include_usage": true}
+	}
+	header := http.Header{}
+	if o.APIKey != "" {
+		header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+	return postJSON(ctx, o.Client, "openai", strings.TrimSuffix(o.BaseURL, "/")+"/chat/completions", header, request)
+}
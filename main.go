@@ -3,7 +3,12 @@ package main
 import (
 	"backend/config"
 	_ "backend/docs"
+	"backend/jobs"
+	"backend/middlewares"
+	"backend/migrations"
 	"backend/routes"
+	"backend/storage"
+	"context"
 	"log"
 	"os"
 
@@ -30,16 +35,75 @@ import (
 
 // @securityDefinitions.basic  BasicAuth
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 
 func main() {
+	// Subcommand: ./main migrate up|down [steps]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		config.ConnectDB()
+		if err := migrations.Run(config.DB, os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Subcommand: ./main backfill-image-variants
+	if len(os.Args) > 1 && os.Args[1] == "backfill-image-variants" {
+		config.ConnectDB()
+		config.InitUploads()
+		total, err := jobs.BackfillAllImageVariants(context.Background(), 50)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Generated variants for %d images", total)
+		return
+	}
+
+	// Subcommand: ./main index-catalog
+	if len(os.Args) > 1 && os.Args[1] == "index-catalog" {
+		config.ConnectDB()
+		config.InitLLM()
+		if config.Embedder == nil {
+			log.Fatal("EMBEDDING_PROVIDER is not configured")
+		}
+		indexed, err := jobs.IndexCatalog(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Indexed %d catalog entries", indexed)
+		return
+	}
+
 	e := echo.New()
 
 	// Initialize Database
 	config.InitDB()
+	config.InitMailer()
+	config.InitEmissions()
+	config.InitUploads()
+	config.InitLLM()
 
-	os.Mkdir("assets", 0777)
+	// IP client untuk rate limit; jangan percaya X-Forwarded-For kecuali dari TRUSTED_PROXIES
+	ipExtractor, err := middlewares.IPExtractor()
+	if err != nil {
+		log.Fatal(err)
+	}
+	e.IPExtractor = ipExtractor
+
+	// Buat varian gambar lama (dan yang gagal diproses saat upload) di background
+	if interval := jobs.BackfillInterval(); interval > 0 {
+		go jobs.StartImageVariantBackfill(context.Background(), interval)
+	}
+
+	// Perbarui embedding katalog untuk pencarian chat jika EMBEDDING_PROVIDER diisi
+	if interval := jobs.CatalogIndexInterval(); interval > 0 && config.Embedder != nil {
+		go jobs.StartCatalogIndexer(context.Background(), interval)
+	}
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
@@ -54,14 +118,17 @@ func main() {
 		},
 	}))
 
-	e.Static("/assets", "./assets")
+	// Storage lokal disajikan langsung oleh server; storage S3 punya URL publik sendiri
+	if local, ok := config.Storage.(*storage.Local); ok {
+		os.MkdirAll(local.Dir, 0777)
+		e.Static("/assets", local.Dir)
+	}
 
 	// Register Routes
 	routes.InitRoutes(e)
 
 	// Start Server
-	err := godotenv.Load()
-	if err != nil {
+	if err := godotenv.Load(); err != nil {
 		log.Fatal("Error loading .env file")
 	}
 
@@ -0,0 +1,156 @@
+package media
+
+import (
+	"backend/storage"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// DefaultMaxBytes adalah batas ukuran file upload jika UPLOAD_MAX_BYTES kosong (5 MiB)
+const DefaultMaxBytes int64 = 5 << 20
+
+// ImageTypes adalah MIME type gambar yang diterima secara default
+var ImageTypes = []string{"image/jpeg", "image/png", "image/webp", "image/gif"}
+
+var (
+	ErrTooLarge        = errors.New("file is too large")
+	ErrUnsupportedType = errors.New("unsupported file type")
+	ErrEmpty           = errors.New("file is empty")
+)
+
+// File adalah hasil upload yang sudah tersimpan. Key disimpan di database;
+// URL dibangun ulang lewat Storage saat ditampilkan.
+type File struct {
+	Key      string `json:"key"`
+	URL      string `json:"url"`
+	MIMEType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
+// Uploader memvalidasi file upload lalu menyimpannya ke Storage dengan key berbasis
+// hash konten, sehingga file yang sama selalu mendapat URL yang sama dan upload lain
+// tidak bisa menimpanya
+type Uploader struct {
+	Storage      storage.Storage
+	Prefix       string
+	MaxBytes     int64
+	AllowedTypes []string
+}
+
+// New membuat Uploader gambar di bawah prefix "uploads" dengan batas ukuran default
+func New(store storage.Storage) *Uploader {
+	return &Uploader{
+		Storage:      store,
+		Prefix:       "uploads",
+		MaxBytes:     DefaultMaxBytes,
+		AllowedTypes: ImageTypes,
+	}
+}
+
+// FromEnv membuat Uploader di atas store dengan batas ukuran dari UPLOAD_MAX_BYTES
+func FromEnv(store storage.Storage) (*Uploader, error) {
+	uploader := New(store)
+	if raw := os.Getenv("UPLOAD_MAX_BYTES"); raw != "" {
+		maxBytes, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || maxBytes <= 0 {
+			return nil, fmt.Errorf("invalid UPLOAD_MAX_BYTES %q", raw)
+		}
+		uploader.MaxBytes = maxBytes
+	}
+	return uploader, nil
+}
+
+// SaveFile menyimpan file dari form multipart
+func (u *Uploader) SaveFile(ctx context.Context, header *multipart.FileHeader) (File, error) {
+	data, err := u.ReadFile(header)
+	if err != nil {
+		return File{}, err
+	}
+	return u.SaveData(ctx, data)
+}
+
+// Save membaca lalu menyimpan isi r, lihat SaveData
+func (u *Uploader) Save(ctx context.Context, r io.Reader) (File, error) {
+	data, err := u.read(r)
+	if err != nil {
+		return File{}, err
+	}
+	return u.SaveData(ctx, data)
+}
+
+// ReadFile membaca isi file dari form multipart dengan batas MaxBytes
+func (u *Uploader) ReadFile(header *multipart.FileHeader) ([]byte, error) {
+	if header.Size > u.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return u.read(src)
+}
+
+func (u *Uploader) read(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, u.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+	if int64(len(data)) > u.MaxBytes {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// SaveData mengecek MIME type dari isi file lalu menyimpannya sebagai
+// <prefix>/<2 karakter hash>/<sha256><ext>
+func (u *Uploader) SaveData(ctx context.Context, data []byte) (File, error) {
+	// MIME type ditentukan dari isi file, bukan dari nama atau header dari client
+	detected := mimetype.Detect(data)
+	if !u.allowed(detected) {
+		return File{}, fmt.Errorf("%w: %s", ErrUnsupportedType, detected.String())
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	key := path.Join(u.Prefix, hash[:2], hash+detected.Extension())
+	mimeType := strings.SplitN(detected.String(), ";", 2)[0]
+
+	// Key yang sama berarti isi yang sama, jadi menulis ulang aman
+	if err := u.Storage.Put(ctx, key, bytes.NewReader(data), mimeType); err != nil {
+		return File{}, err
+	}
+
+	return File{
+		Key:      key,
+		URL:      u.Storage.URL(key),
+		MIMEType: mimeType,
+		Size:     int64(len(data)),
+	}, nil
+}
+
+func (u *Uploader) allowed(detected *mimetype.MIME) bool {
+	for _, allowed := range u.AllowedTypes {
+		if detected.Is(allowed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"path"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Size adalah satu varian gambar; sisi terpanjang diperkecil hingga MaxDimension
+// (gambar yang lebih kecil tidak diperbesar)
+type Size struct {
+	Name         string
+	MaxDimension int
+}
+
+// Sizes adalah varian yang dibuat untuk setiap gambar destinasi
+var Sizes = []Size{
+	{Name: "thumbnail", MaxDimension: 320},
+	{Name: "medium", MaxDimension: 800},
+	{Name: "large", MaxDimension: 1600},
+}
+
+// MaxPixels membatasi dimensi gambar yang mau didekode supaya file kecil dengan
+// dimensi raksasa tidak menghabiskan memori
+const MaxPixels = 50_000_000
+
+// jpegQuality dipakai untuk varian tanpa transparansi
+const jpegQuality = 82
+
+var ErrUndecodable = errors.New("image cannot be decoded")
+
+// Variant adalah satu ukuran gambar yang sudah tersimpan di Storage
+type Variant struct {
+	Name   string `json:"name"`
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Variants mendekode gambar, memutarnya sesuai orientasi EXIF, lalu menyimpan setiap
+// ukuran di Sizes sebagai <prefix>/<2 karakter hash>/<sha256>_<nama>.<ext>. Varian
+// di-encode ulang (JPEG, atau PNG jika ada transparansi) sehingga metadata seperti
+// EXIF dan lokasi GPS tidak ikut tersimpan.
+func (u *Uploader) Variants(ctx context.Context, data []byte) ([]Variant, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUndecodable, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds the pixel limit", ErrUndecodable, config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUndecodable, err)
+	}
+
+	orientation := jpegOrientation(data)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	variants := make([]Variant, 0, len(Sizes))
+	for _, size := range Sizes {
+		resized := orient(resize(src, size.MaxDimension), orientation)
+
+		var encoded bytes.Buffer
+		ext, contentType := ".jpg", "image/jpeg"
+		if resized.Opaque() {
+			err = jpeg.Encode(&encoded, resized, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			ext, contentType = ".png", "image/png"
+			err = png.Encode(&encoded, resized)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key := path.Join(u.Prefix, hash[:2], hash+"_"+size.Name+ext)
+		if err := u.Storage.Put(ctx, key, &encoded, contentType); err != nil {
+			return nil, err
+		}
+		variants = append(variants, Variant{
+			Name:   size.Name,
+			Key:    key,
+			URL:    u.Storage.URL(key),
+			Width:  resized.Bounds().Dx(),
+			Height: resized.Bounds().Dy(),
+		})
+	}
+	return variants, nil
+}
+
+// resize memperkecil src supaya sisi terpanjangnya <= maxDimension. Dilakukan sebelum
+// orient karena memutar gambar tidak mengubah sisi terpanjang dan lebih murah pada gambar kecil.
+func resize(src image.Image, maxDimension int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	scale := math.Min(1, float64(maxDimension)/float64(max(width, height)))
+	targetWidth := max(1, int(math.Round(float64(width)*scale)))
+	targetHeight := max(1, int(math.Round(float64(height)*scale)))
+
+	dst := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+// orient memutar/membalik gambar sesuai tag Orientation EXIF (1-8)
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // cermin horizontal
+				dx, dy = width-1-x, y
+			case 3: // putar 180
+				dx, dy = width-1-x, height-1-y
+			case 4: // cermin vertikal
+				dx, dy = x, height-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // putar 90 searah jarum jam
+				dx, dy = height-1-y, x
+			case 7: // transverse
+				dx, dy = height-1-y, width-1-x
+			case 8: // putar 90 berlawanan jarum jam
+				dx, dy = y, width-1-x
+			}
+			dst.SetRGBA(dx, dy, img.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation membaca tag Orientation dari segmen EXIF (APP1) file JPEG;
+// 1 (normal) jika tidak ada
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan / end of image: metadata sudah lewat
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for k := 0; k < entries; k++ {
+		entry := ifd + 2 + k*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
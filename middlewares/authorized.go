@@ -1,16 +1,46 @@
 package middlewares
 
 import (
-	"errors"
+	"backend/config"
+	"backend/helper"
+	"backend/models"
 	"net/http"
 	"os"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
+const claimsContextKey = "auth_claims"
+
+// AuthUser adalah identitas pengguna yang diambil dari access token
+type AuthUser struct {
+	ID        uint
+	Username  string
+	Role      string
+	SessionID string
+	Verified  bool
+}
+
+// IsAdmin mengecek apakah pengguna memiliki role admin
+func (u AuthUser) IsAdmin() bool {
+	return u.Role == "admin"
+}
+
+// AuthorizedAccess memvalidasi access token sekali dan menyimpan claims-nya di context.
+// Pengguna yang belum memverifikasi email ditolak.
 func AuthorizedAccess(next echo.HandlerFunc) echo.HandlerFunc {
+	return authenticate(next, true)
+}
+
+// AllowUnverified seperti AuthorizedAccess, tetapi juga meloloskan pengguna yang
+// belum memverifikasi email (misalnya untuk logout dan kirim ulang verifikasi)
+func AllowUnverified(next echo.HandlerFunc) echo.HandlerFunc {
+	return authenticate(next, false)
+}
+
+func authenticate(next echo.HandlerFunc, requireVerified bool) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		authHeader := c.Request().Header.Get("Authorization")
 		const bearerPrefix = "Bearer "
@@ -25,22 +55,88 @@ func AuthorizedAccess(next echo.HandlerFunc) echo.HandlerFunc {
 			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Unauthorized Access"})
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		claims := &helper.JWTClaims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 			return []byte(secretKey), nil
-		})
-		if err != nil {
-			var validationErr *jwt.ValidationError
-			if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized Access"})
-			}
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+		if err != nil || !token.Valid {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized Access"})
 		}
 
-		_, ok := token.Claims.(jwt.MapClaims)
-		if !ok || !token.Valid {
+		// Token tanpa sesi atau user tidak bisa dicabut, jadi tidak diterima
+		if claims.SessionID == "" || claims.UserID == 0 {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized Access"})
 		}
 
+		if isRevoked(claims.SessionID, claims.ID) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Token has been revoked"})
+		}
+
+		if requireVerified && !claims.Verified {
+			return Forbidden(c, "Please verify your email address first")
+		}
+
+		c.Set(claimsContextKey, claims)
+
 		return next(c)
 	}
 }
+
+// RoleBasedAccess hanya meloloskan pengguna dengan salah satu role yang diizinkan.
+// Jika belum ada claims di context, token divalidasi terlebih dahulu.
+func RoleBasedAccess(allowedRoles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		check := func(c echo.Context) error {
+			user, _ := CurrentUser(c)
+			for _, role := range allowedRoles {
+				if user.Role == role {
+					return next(c)
+				}
+			}
+			return Forbidden(c, "Access forbidden: insufficient role")
+		}
+
+		return func(c echo.Context) error {
+			if _, ok := CurrentUser(c); !ok {
+				return AuthorizedAccess(check)(c)
+			}
+			return check(c)
+		}
+	}
+}
+
+// AdminOnly hanya meloloskan pengguna dengan role admin
+func AdminOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return RoleBasedAccess("admin")(next)
+}
+
+// CurrentUser mengembalikan pengguna yang sudah diautentikasi oleh AuthorizedAccess
+func CurrentUser(c echo.Context) (AuthUser, bool) {
+	claims, ok := c.Get(claimsContextKey).(*helper.JWTClaims)
+	if !ok || claims == nil {
+		return AuthUser{}, false
+	}
+
+	return AuthUser{
+		ID:        claims.UserID,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+		Verified:  claims.Verified,
+	}, true
+}
+
+// isRevoked memeriksa revocation list untuk sesi atau jti token
+func isRevoked(sessionID, tokenID string) bool {
+	ids := []string{sessionID}
+	if tokenID != "" {
+		ids = append(ids, tokenID)
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.RevokedToken{}).Where("token_id IN ?", ids).Count(&count).Error; err != nil {
+		// Gagal membaca revocation list: tolak daripada meloloskan token yang mungkin dicabut
+		return true
+	}
+	return count > 0
+}
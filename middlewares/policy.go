@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"backend/helper"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CanActOn mengecek apakah pengguna boleh mengubah resource milik ownerID.
+// Pengguna biasa hanya boleh mengubah miliknya sendiri, admin boleh semuanya.
+func CanActOn(user AuthUser, ownerID uint) bool {
+	return user.IsAdmin() || (user.ID != 0 && user.ID == ownerID)
+}
+
+// CanChangeRole mengecek apakah pengguna boleh mengubah role akun
+func CanChangeRole(user AuthUser) bool {
+	return user.IsAdmin()
+}
+
+// Forbidden adalah response 403 yang dipakai oleh semua penolakan akses
+func Forbidden(c echo.Context, message string) error {
+	response := helper.APIResponse(message, http.StatusForbidden, "error", nil)
+	return c.JSON(http.StatusForbidden, response)
+}
+
+// SelfOrAdmin hanya meloloskan request jika path param berisi ID pengguna yang login,
+// atau jika pengguna adalah admin
+func SelfOrAdmin(param string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := CurrentUser(c)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Unauthorized Access"})
+			}
+
+			ownerID, err := strconv.ParseUint(c.Param(param), 10, 64)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid user ID"})
+			}
+
+			if !CanActOn(user, uint(ownerID)) {
+				return Forbidden(c, "Access forbidden: you can only manage your own account")
+			}
+
+			return next(c)
+		}
+	}
+}
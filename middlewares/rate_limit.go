@@ -0,0 +1,170 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimiter membatasi jumlah request per key dengan token bucket di memori: setiap
+// key boleh mengirim limit request sekaligus, lalu satu request lagi setiap
+// window/limit. Batasnya berlaku per instance server.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter membuat limiter limit request per window; limit <= 0 berarti tanpa
+// batas dan mengembalikan nil
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+	return &RateLimiter{limit: limit, window: window, buckets: map[string]*tokenBucket{}, lastSweep: time.Now()}
+}
+
+// Allow memakai satu token milik key. Jika token habis, Allow mengembalikan false dan
+// lama waktu sampai token berikutnya tersedia.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+	interval := l.window / time.Duration(l.limit)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.limit), updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(float64(l.limit), bucket.tokens+float64(now.Sub(bucket.updated))/float64(interval))
+	bucket.updated = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - bucket.tokens) * float64(interval))
+}
+
+// sweep membuang bucket yang sudah penuh kembali supaya map tidak terus membesar
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= l.window {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// RateLimit menolak request dengan 429 jika key-nya melebihi limiter. Limiter nil
+// atau key kosong tidak dibatasi.
+func RateLimit(limiter *RateLimiter, key func(c echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if limiter == nil {
+				return next(c)
+			}
+			id := key(c)
+			if id == "" {
+				return next(c)
+			}
+			if ok, wait := limiter.Allow(id); !ok {
+				return TooManyRequests(c, wait, "Too many requests, please slow down")
+			}
+			return next(c)
+		}
+	}
+}
+
+// ByIP membatasi per alamat IP client dari e.IPExtractor (lihat IPExtractor)
+func ByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// IPExtractor menentukan IP client. Tanpa TRUSTED_PROXIES dipakai alamat koneksi
+// langsung, karena header X-Forwarded-For bisa diisi sendiri oleh client. Jika server
+// berada di belakang proxy, isi TRUSTED_PROXIES dengan daftar CIDR proxy dipisah koma;
+// hanya hop dari proxy itu yang dipercaya di X-Forwarded-For.
+func IPExtractor() (echo.IPExtractor, error) {
+	raw := strings.TrimSpace(os.Getenv("TRUSTED_PROXIES"))
+	if raw == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range strings.Split(raw, ",") {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: %w", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// ByUser membatasi per user yang login; dipasang setelah AuthorizedAccess
+func ByUser(c echo.Context) string {
+	user, ok := CurrentUser(c)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("user:%d", user.ID)
+}
+
+// ChatRateLimits adalah batas request chat per user (CHAT_USER_RATE_LIMIT, default 20)
+// dan per IP (CHAT_IP_RATE_LIMIT, default 60) setiap menit; 0 mematikan batasnya.
+// Setiap pemanggilan membuat limiter baru yang dipakai bersama oleh semua endpoint
+// yang memasangnya.
+func ChatRateLimits() []echo.MiddlewareFunc {
+	return []echo.MiddlewareFunc{
+		RateLimit(NewRateLimiter(envLimit("CHAT_IP_RATE_LIMIT", 60), time.Minute), ByIP),
+		RateLimit(NewRateLimiter(envLimit("CHAT_USER_RATE_LIMIT", 20), time.Minute), ByUser),
+	}
+}
+
+func envLimit(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n >= 0 {
+		return n
+	}
+	return fallback
+}
+
+// TooManyRequests adalah response 429 dengan header Retry-After (detik, dibulatkan ke
+// atas) dan retry_after yang sama di body
+func TooManyRequests(c echo.Context, retryAfter time.Duration, message string) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+	return c.JSON(http.StatusTooManyRequests, map[string]interface{}{
+		"message":     message,
+		"retry_after": seconds,
+	})
+}
@@ -0,0 +1,138 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Snapshot skema saat migration ini dibuat. Jangan diubah: perubahan model
+// berikutnya harus ditulis sebagai migration baru.
+
+type city0001 struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+	Lat  string
+	Long string
+}
+
+func (city0001) TableName() string { return "cities" }
+
+type user0001 struct {
+	gorm.Model
+	Username    string `gorm:"unique"`
+	FirstName   string
+	LastName    string
+	Email       string `gorm:"unique"`
+	City        string
+	Password    string
+	Role        string
+	Category    string
+	Picture     string
+	File        string
+	PhoneNumber string
+	Gender      string
+}
+
+func (user0001) TableName() string { return "users" }
+
+type destination0001 struct {
+	ID               uint `gorm:"primaryKey"`
+	Name             string
+	CityID           uint
+	City             city0001 `gorm:"foreignKey:CityID;references:ID"`
+	Position         float64
+	Address          string
+	OperationalHours string
+	TicketPrice      float64
+	Category         string
+	Description      string
+	Facilities       string
+	CreatedAt        time.Time
+	Images           []image0001        `gorm:"foreignKey:DestinationID"`
+	VideoContents    []videoContent0001 `gorm:"foreignKey:DestinationID"`
+}
+
+func (destination0001) TableName() string { return "destinations" }
+
+type image0001 struct {
+	ID            uint `gorm:"primaryKey"`
+	DestinationID uint
+	URL           string
+}
+
+func (image0001) TableName() string { return "images" }
+
+type videoContent0001 struct {
+	ID            uint `gorm:"primaryKey"`
+	DestinationID uint
+	Title         string
+	URL           string
+	Description   string
+}
+
+func (videoContent0001) TableName() string { return "video_contents" }
+
+type videoContentView0001 struct {
+	gorm.Model
+	VideoContentID uint
+	UserID         uint
+}
+
+func (videoContentView0001) TableName() string { return "video_content_views" }
+
+type route0001 struct {
+	ID                  uint `gorm:"primaryKey"`
+	UserID              uint
+	OriginCityName      string
+	DestinationCityName string
+	Distance            float64
+	Time                string
+	Cost                int
+	CreatedAt           time.Time
+	Destinations        []routeDestination0001 `gorm:"foreignKey:RouteID"`
+}
+
+func (route0001) TableName() string { return "routes" }
+
+type routeDestination0001 struct {
+	ID            uint `gorm:"primaryKey"`
+	RouteID       uint
+	DestinationID uint
+	CreatedAt     time.Time
+}
+
+func (routeDestination0001) TableName() string { return "route_destinations" }
+
+func init() {
+	register(Migration{
+		Version: "0001",
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			// AutoMigrate dipakai agar database lama yang dibuat oleh
+			// DB.AutoMigrate bisa diadopsi tanpa membuat ulang tabel
+			return tx.AutoMigrate(
+				&user0001{},
+				&city0001{},
+				&destination0001{},
+				&videoContent0001{},
+				&image0001{},
+				&videoContentView0001{},
+				&route0001{},
+				&routeDestination0001{},
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(
+				&routeDestination0001{},
+				&route0001{},
+				&videoContentView0001{},
+				&image0001{},
+				&videoContent0001{},
+				&destination0001{},
+				&city0001{},
+				&user0001{},
+			)
+		},
+	})
+}
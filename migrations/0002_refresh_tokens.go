@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type refreshToken0002 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	SessionID string `gorm:"size:64;index"`
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (refreshToken0002) TableName() string { return "refresh_tokens" }
+
+type revokedToken0002 struct {
+	ID        uint      `gorm:"primaryKey"`
+	TokenID   string    `gorm:"size:64;uniqueIndex"`
+	UserID    uint      `gorm:"index"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (revokedToken0002) TableName() string { return "revoked_tokens" }
+
+func init() {
+	register(Migration{
+		Version: "0002",
+		Name:    "refresh_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&refreshToken0002{}, &revokedToken0002{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&revokedToken0002{}, &refreshToken0002{})
+		},
+	})
+}
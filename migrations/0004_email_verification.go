@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0004 struct {
+	ID              uint `gorm:"primaryKey"`
+	EmailVerifiedAt *time.Time
+}
+
+func (user0004) TableName() string { return "users" }
+
+type emailVerificationToken0004 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (emailVerificationToken0004) TableName() string { return "email_verification_tokens" }
+
+func init() {
+	register(Migration{
+		Version: "0004",
+		Name:    "email_verification",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&user0004{}, "EmailVerifiedAt"); err != nil {
+				return err
+			}
+
+			// Akun yang sudah ada sebelum verifikasi email diberlakukan dianggap terverifikasi
+			if err := tx.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL").Error; err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateTable(&emailVerificationToken0004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&emailVerificationToken0004{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&user0004{}, "EmailVerifiedAt")
+		},
+	})
+}
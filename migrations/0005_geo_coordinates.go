@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+type destination0005 struct {
+	ID        uint     `gorm:"primaryKey"`
+	Latitude  *float64 `gorm:"index:idx_destinations_location,priority:1"`
+	Longitude *float64 `gorm:"index:idx_destinations_location,priority:2"`
+}
+
+func (destination0005) TableName() string { return "destinations" }
+
+type city0005 struct {
+	ID        uint `gorm:"primaryKey"`
+	Latitude  *float64
+	Longitude *float64
+}
+
+func (city0005) TableName() string { return "cities" }
+
+// cityLegacy0005 adalah kolom lat/long string sebelum migration ini
+type cityLegacy0005 struct {
+	ID   uint `gorm:"primaryKey"`
+	Lat  string
+	Long string
+}
+
+func (cityLegacy0005) TableName() string { return "cities" }
+
+func init() {
+	register(Migration{
+		Version: "0005",
+		Name:    "geo_coordinates",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range []string{"Latitude", "Longitude"} {
+				if err := tx.Migrator().AddColumn(&destination0005{}, column); err != nil {
+					return err
+				}
+				if err := tx.Migrator().AddColumn(&city0005{}, column); err != nil {
+					return err
+				}
+			}
+			if err := tx.Migrator().CreateIndex(&destination0005{}, "idx_destinations_location"); err != nil {
+				return err
+			}
+
+			// Pindahkan lat/long string lama ke kolom angka; nilai yang tidak valid dibiarkan kosong
+			var cities []cityLegacy0005
+			if err := tx.Find(&cities).Error; err != nil {
+				return err
+			}
+			for _, city := range cities {
+				lat, latErr := strconv.ParseFloat(city.Lat, 64)
+				long, longErr := strconv.ParseFloat(city.Long, 64)
+				if latErr != nil || longErr != nil || lat < -90 || lat > 90 || long < -180 || long > 180 {
+					continue
+				}
+				err := tx.Model(&city0005{}).Where("id = ?", city.ID).
+					Updates(map[string]interface{}{"latitude": lat, "longitude": long}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Migrator().DropColumn(&cityLegacy0005{}, "Lat"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&cityLegacy0005{}, "Long")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&cityLegacy0005{}, "Lat"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&cityLegacy0005{}, "Long"); err != nil {
+				return err
+			}
+
+			var cities []city0005
+			if err := tx.Where("latitude IS NOT NULL AND longitude IS NOT NULL").Find(&cities).Error; err != nil {
+				return err
+			}
+			for _, city := range cities {
+				err := tx.Model(&cityLegacy0005{}).Where("id = ?", city.ID).Updates(map[string]interface{}{
+					"lat":  strconv.FormatFloat(*city.Latitude, 'f', -1, 64),
+					"long": strconv.FormatFloat(*city.Longitude, 'f', -1, 64),
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := dropIndexIfExists(tx, &destination0005{}, "idx_destinations_location"); err != nil {
+				return err
+			}
+			for _, column := range []string{"Latitude", "Longitude"} {
+				if err := tx.Migrator().DropColumn(&city0005{}, column); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&destination0005{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
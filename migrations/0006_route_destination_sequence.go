@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type routeDestination0006 struct {
+	ID       uint `gorm:"primaryKey"`
+	RouteID  uint
+	Sequence int `gorm:"not null;default:0"`
+}
+
+func (routeDestination0006) TableName() string { return "route_destinations" }
+
+func init() {
+	register(Migration{
+		Version: "0006",
+		Name:    "route_destination_sequence",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&routeDestination0006{}, "Sequence"); err != nil {
+				return err
+			}
+
+			// Rute lama disimpan sesuai urutan insert, jadi urutan id dipakai sebagai sequence
+			var rows []routeDestination0006
+			if err := tx.Order("route_id, id").Find(&rows).Error; err != nil {
+				return err
+			}
+			var routeID uint
+			sequence := 0
+			for _, row := range rows {
+				if row.RouteID != routeID {
+					routeID, sequence = row.RouteID, 0
+				}
+				sequence++
+				if err := tx.Model(&routeDestination0006{}).Where("id = ?", row.ID).Update("sequence", sequence).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&routeDestination0006{}, "Sequence")
+		},
+	})
+}
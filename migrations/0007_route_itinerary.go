@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type routeDestination0007 struct {
+	ID            uint   `gorm:"primaryKey"`
+	Day           int    `gorm:"not null;default:1"`
+	ArrivalTime   string `gorm:"size:5"`
+	DepartureTime string `gorm:"size:5"`
+	StayMinutes   int    `gorm:"not null;default:0"`
+	Notes         string `gorm:"type:text"`
+}
+
+func (routeDestination0007) TableName() string { return "route_destinations" }
+
+type route0007 struct {
+	ID        uint `gorm:"primaryKey"`
+	OpenEnded bool `gorm:"not null;default:false"`
+}
+
+func (route0007) TableName() string { return "routes" }
+
+var itineraryColumns0007 = []string{"Day", "ArrivalTime", "DepartureTime", "StayMinutes", "Notes"}
+
+func init() {
+	register(Migration{
+		Version: "0007",
+		Name:    "route_itinerary",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range itineraryColumns0007 {
+				if err := tx.Migrator().AddColumn(&routeDestination0007{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().AddColumn(&route0007{}, "OpenEnded")
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, column := range itineraryColumns0007 {
+				if err := tx.Migrator().DropColumn(&routeDestination0007{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().DropColumn(&route0007{}, "OpenEnded")
+		},
+	})
+}
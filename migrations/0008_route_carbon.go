@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type route0008 struct {
+	ID            uint   `gorm:"primaryKey"`
+	TransportMode string `gorm:"size:20;not null;default:car"`
+	Travellers    int    `gorm:"not null;default:1"`
+	CarbonKg      float64
+}
+
+func (route0008) TableName() string { return "routes" }
+
+// carFactor0008 adalah faktor mobil saat migration ini ditulis, untuk mengisi rute lama
+const carFactor0008 = 0.170
+
+var carbonColumns0008 = []string{"TransportMode", "Travellers", "CarbonKg"}
+
+func init() {
+	register(Migration{
+		Version: "0008",
+		Name:    "route_carbon",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range carbonColumns0008 {
+				if err := tx.Migrator().AddColumn(&route0008{}, column); err != nil {
+					return err
+				}
+			}
+
+			// Rute lama dianggap perjalanan satu orang dengan mobil. PostgreSQL hanya punya
+			// ROUND(numeric, int), jadi hasil kalinya di-cast ke DECIMAL dulu.
+			return tx.Exec("UPDATE routes SET carbon_kg = ROUND(CAST(distance * ? AS DECIMAL(14,4)), 2)", carFactor0008).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, column := range carbonColumns0008 {
+				if err := tx.Migrator().DropColumn(&route0008{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
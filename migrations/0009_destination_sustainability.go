@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type destination0009 struct {
+	ID                        uint `gorm:"primaryKey"`
+	Certifications            string
+	WastePractice             string `gorm:"size:20;not null;default:'none'"`
+	EnergyPractice            string `gorm:"size:20;not null;default:'none'"`
+	VisitorCapacity           int    `gorm:"not null;default:0"`
+	PublicTransportAccessible bool   `gorm:"not null;default:false"`
+	EcoScore                  int    `gorm:"not null;default:0;index"`
+}
+
+func (destination0009) TableName() string { return "destinations" }
+
+var sustainabilityColumns0009 = []string{
+	"Certifications", "WastePractice", "EnergyPractice",
+	"VisitorCapacity", "PublicTransportAccessible", "EcoScore",
+}
+
+func init() {
+	register(Migration{
+		Version: "0009",
+		Name:    "destination_sustainability",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range sustainabilityColumns0009 {
+				if err := tx.Migrator().AddColumn(&destination0009{}, column); err != nil {
+					return err
+				}
+			}
+			// Destinasi lama belum punya data keberlanjutan sehingga eco_score 0 sudah benar
+			return tx.Migrator().CreateIndex(&destination0009{}, "EcoScore")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &destination0009{}, "EcoScore"); err != nil {
+				return err
+			}
+			for _, column := range sustainabilityColumns0009 {
+				if err := tx.Migrator().DropColumn(&destination0009{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
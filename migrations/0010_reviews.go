@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type review0010 struct {
+	ID            uint   `gorm:"primaryKey"`
+	DestinationID uint   `gorm:"uniqueIndex:idx_reviews_destination_user,priority:1;not null"`
+	UserID        uint   `gorm:"uniqueIndex:idx_reviews_destination_user,priority:2;not null"`
+	Rating        int    `gorm:"not null"`
+	Comment       string `gorm:"type:text"`
+	Status        string `gorm:"size:20;not null;default:'visible';index"`
+	Flagged       bool   `gorm:"not null;default:false"`
+	FlagReason    string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (review0010) TableName() string { return "reviews" }
+
+type reviewPhoto0010 struct {
+	ID       uint `gorm:"primaryKey"`
+	ReviewID uint `gorm:"index"`
+	URL      string
+}
+
+func (reviewPhoto0010) TableName() string { return "review_photos" }
+
+type destination0010 struct {
+	ID            uint    `gorm:"primaryKey"`
+	AverageRating float64 `gorm:"not null;default:0;index"`
+	ReviewCount   int     `gorm:"not null;default:0"`
+}
+
+func (destination0010) TableName() string { return "destinations" }
+
+func init() {
+	register(Migration{
+		Version: "0010",
+		Name:    "reviews",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&review0010{}, &reviewPhoto0010{}); err != nil {
+				return err
+			}
+			for _, column := range []string{"AverageRating", "ReviewCount"} {
+				if err := tx.Migrator().AddColumn(&destination0010{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateIndex(&destination0010{}, "AverageRating")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &destination0010{}, "AverageRating"); err != nil {
+				return err
+			}
+			for _, column := range []string{"AverageRating", "ReviewCount"} {
+				if err := tx.Migrator().DropColumn(&destination0010{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().DropTable(&reviewPhoto0010{}, &review0010{})
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type favorite0011 struct {
+	ID            uint `gorm:"primaryKey"`
+	UserID        uint `gorm:"uniqueIndex:idx_favorites_user_destination,priority:1;not null"`
+	DestinationID uint `gorm:"uniqueIndex:idx_favorites_user_destination,priority:2;not null;index"`
+	CreatedAt     time.Time
+}
+
+func (favorite0011) TableName() string { return "favorites" }
+
+func init() {
+	register(Migration{
+		Version: "0011",
+		Name:    "favorites",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&favorite0011{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&favorite0011{})
+		},
+	})
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type videoContentView0012 struct {
+	ID             uint      `gorm:"primaryKey"`
+	VideoContentID uint      `gorm:"index:idx_video_views_dedup,priority:1"`
+	UserID         uint      `gorm:"index:idx_video_views_dedup,priority:2"`
+	DestinationID  uint      `gorm:"index"`
+	CreatedAt      time.Time `gorm:"index:idx_video_views_dedup,priority:3"`
+}
+
+func (videoContentView0012) TableName() string { return "video_content_views" }
+
+func init() {
+	register(Migration{
+		Version: "0012",
+		Name:    "video_view_tracking",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&videoContentView0012{}, "DestinationID"); err != nil {
+				return err
+			}
+
+			// Simpan destination_id di setiap view supaya ranking destinasi tetap benar
+			// walaupun video-nya diganti
+			err := tx.Exec(`UPDATE video_content_views SET destination_id = (
+				SELECT video_contents.destination_id FROM video_contents
+				WHERE video_contents.id = video_content_views.video_content_id
+			)`).Error
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Migrator().CreateIndex(&videoContentView0012{}, "DestinationID"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&videoContentView0012{}, "idx_video_views_dedup")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &videoContentView0012{}, "idx_video_views_dedup"); err != nil {
+				return err
+			}
+			if err := dropIndexIfExists(tx, &videoContentView0012{}, "DestinationID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&videoContentView0012{}, "DestinationID")
+		},
+	})
+}
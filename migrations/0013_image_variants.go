@@ -0,0 +1,52 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type image0013 struct {
+	ID              uint   `gorm:"primaryKey"`
+	VariantsStatus  string `gorm:"size:20;not null;default:'pending';index"`
+	VariantAttempts int    `gorm:"not null;default:0"`
+}
+
+func (image0013) TableName() string { return "images" }
+
+type imageVariant0013 struct {
+	ID      uint   `gorm:"primaryKey"`
+	ImageID uint   `gorm:"uniqueIndex:idx_image_variants_image_name,priority:1;not null"`
+	Name    string `gorm:"uniqueIndex:idx_image_variants_image_name,priority:2;size:20;not null"`
+	Key     string `gorm:"not null"`
+	Width   int
+	Height  int
+}
+
+func (imageVariant0013) TableName() string { return "image_variants" }
+
+func init() {
+	register(Migration{
+		Version: "0013",
+		Name:    "image_variants",
+		// Gambar yang sudah ada otomatis berstatus pending sehingga diproses oleh job backfill
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&imageVariant0013{}); err != nil {
+				return err
+			}
+			for _, column := range []string{"VariantsStatus", "VariantAttempts"} {
+				if err := tx.Migrator().AddColumn(&image0013{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateIndex(&image0013{}, "VariantsStatus")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &image0013{}, "VariantsStatus"); err != nil {
+				return err
+			}
+			for _, column := range []string{"VariantsStatus", "VariantAttempts"} {
+				if err := tx.Migrator().DropColumn(&image0013{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().DropTable(&imageVariant0013{})
+		},
+	})
+}
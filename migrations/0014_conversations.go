@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type conversation0014 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Title     string `gorm:"size:100"`
+	CreatedAt time.Time
+	UpdatedAt time.Time `gorm:"index"`
+}
+
+func (conversation0014) TableName() string { return "conversations" }
+
+type message0014 struct {
+	ID             uint   `gorm:"primaryKey"`
+	ConversationID uint   `gorm:"not null;index"`
+	Role           string `gorm:"size:10;not null"`
+	Content        string `gorm:"type:text;not null"`
+	CreatedAt      time.Time
+}
+
+func (message0014) TableName() string { return "messages" }
+
+func init() {
+	register(Migration{
+		Version: "0014",
+		Name:    "conversations",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&conversation0014{}, &message0014{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&message0014{}, &conversation0014{})
+		},
+	})
+}
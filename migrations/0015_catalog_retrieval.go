@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type catalogEmbedding0015 struct {
+	ID          uint   `gorm:"primaryKey"`
+	SourceType  string `gorm:"size:20;not null;uniqueIndex:idx_catalog_embeddings_source,priority:1"`
+	SourceID    uint   `gorm:"not null;uniqueIndex:idx_catalog_embeddings_source,priority:2"`
+	Model       string `gorm:"size:100;not null;uniqueIndex:idx_catalog_embeddings_source,priority:3"`
+	ContentHash string `gorm:"size:64;not null"`
+	Vector      []byte `gorm:"not null"`
+	UpdatedAt   time.Time
+}
+
+func (catalogEmbedding0015) TableName() string { return "catalog_embeddings" }
+
+type message0015 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Citations string `gorm:"type:text"`
+}
+
+func (message0015) TableName() string { return "messages" }
+
+func init() {
+	register(Migration{
+		Version: "0015",
+		Name:    "catalog_retrieval",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&catalogEmbedding0015{}); err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&message0015{}, "Citations")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&message0015{}, "Citations"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&catalogEmbedding0015{})
+		},
+	})
+}
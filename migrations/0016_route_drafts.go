@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type routeDraft0016 struct {
+	ID                  uint  `gorm:"primaryKey"`
+	UserID              uint  `gorm:"not null;index"`
+	MessageID           *uint `gorm:"index"`
+	OriginCityName      string
+	DestinationCityName string
+	OpenEnded           bool   `gorm:"not null;default:false"`
+	Stops               string `gorm:"type:text"`
+	Distance            float64
+	TransportMode       string `gorm:"size:20;not null;default:car"`
+	Travellers          int    `gorm:"not null;default:1"`
+	CarbonKg            float64
+	Status              string `gorm:"size:20;not null;default:pending"`
+	RouteID             *uint
+	CreatedAt           time.Time
+}
+
+func (routeDraft0016) TableName() string { return "route_drafts" }
+
+func init() {
+	register(Migration{
+		Version: "0016",
+		Name:    "route_drafts",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&routeDraft0016{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&routeDraft0016{})
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type chatUsage0017 struct {
+	ID               uint   `gorm:"primaryKey"`
+	UserID           uint   `gorm:"not null;uniqueIndex:idx_chat_usages_user_day,priority:1"`
+	Day              string `gorm:"size:10;not null;uniqueIndex:idx_chat_usages_user_day,priority:2;index"`
+	PromptTokens     int    `gorm:"not null;default:0"`
+	CompletionTokens int    `gorm:"not null;default:0"`
+	TotalTokens      int    `gorm:"not null;default:0"`
+	Requests         int    `gorm:"not null;default:0"`
+	UpdatedAt        time.Time
+}
+
+func (chatUsage0017) TableName() string { return "chat_usages" }
+
+type chatQuota0017 struct {
+	UserID          uint `gorm:"primaryKey;autoIncrement:false"`
+	DailyTokenLimit int  `gorm:"not null"`
+	UpdatedAt       time.Time
+}
+
+func (chatQuota0017) TableName() string { return "chat_quotas" }
+
+func init() {
+	register(Migration{
+		Version: "0017",
+		Name:    "chat_quotas",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&chatUsage0017{}); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&chatQuota0017{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&chatQuota0017{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&chatUsage0017{})
+		},
+	})
+}
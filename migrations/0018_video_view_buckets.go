@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type videoContentView0018 struct {
+	ID             uint   `gorm:"primaryKey"`
+	VideoContentID uint   `gorm:"uniqueIndex:idx_video_views_bucket,priority:1"`
+	UserID         uint   `gorm:"uniqueIndex:idx_video_views_bucket,priority:2"`
+	WindowBucket   *int64 `gorm:"uniqueIndex:idx_video_views_bucket,priority:3"`
+}
+
+func (videoContentView0018) TableName() string { return "video_content_views" }
+
+func init() {
+	register(Migration{
+		Version: "0018",
+		Name:    "video_view_buckets",
+		Up: func(tx *gorm.DB) error {
+			// View lama dibiarkan NULL; NULL tidak bentrok di unique index
+			if err := tx.Migrator().AddColumn(&videoContentView0018{}, "WindowBucket"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&videoContentView0018{}, "idx_video_views_bucket")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropIndexIfExists(tx, &videoContentView0018{}, "idx_video_views_bucket"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&videoContentView0018{}, "WindowBucket")
+		},
+	})
+}
@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+)
+
+const usage = "usage: migrate up | down [steps] | status"
+
+// Run menjalankan subcommand `migrate` dari binary utama
+func Run(db *gorm.DB, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "up":
+		versions, err := Up(db)
+		for _, version := range versions {
+			fmt.Fprintf(out, "applied %s\n", version)
+		}
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid steps %q: %s", args[1], usage)
+			}
+			steps = n
+		}
+
+		versions, err := Down(db, steps)
+		for _, version := range versions {
+			fmt.Fprintf(out, "rolled back %s\n", version)
+		}
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			fmt.Fprintln(out, "no applied migrations")
+		}
+		return nil
+
+	case "status":
+		statuses, err := StatusOf(db)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown command %q: %s", args[0], usage)
+	}
+}
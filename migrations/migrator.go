@@ -0,0 +1,162 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration adalah satu langkah perubahan skema yang bernomor
+type Migration struct {
+	Version string
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration mencatat migration yang sudah dijalankan di database
+type SchemaMigration struct {
+	Version   string    `gorm:"primaryKey;size:32"`
+	Name      string    `gorm:"size:255"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status menggambarkan kondisi satu migration untuk perintah `migrate status`
+type Status struct {
+	Version   string
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+var registry []Migration
+
+// register dipanggil dari init() setiap file migration
+func register(m Migration) {
+	for _, existing := range registry {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("migrations: duplicate version %s", m.Version))
+		}
+	}
+	registry = append(registry, m)
+	sort.Slice(registry, func(i, j int) bool { return registry[i].Version < registry[j].Version })
+}
+
+// All mengembalikan seluruh migration yang terdaftar, urut berdasarkan versi
+func All() []Migration {
+	return append([]Migration(nil), registry...)
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.AutoMigrate(&SchemaMigration{})
+}
+
+func applied(db *gorm.DB) (map[string]SchemaMigration, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]SchemaMigration, len(rows))
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// Up menjalankan semua migration yang belum diterapkan dan mengembalikan versinya
+func Up(db *gorm.DB) ([]string, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, m := range registry {
+		if _, ok := done[m.Version]; ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return versions, fmt.Errorf("migration %s_%s up: %w", m.Version, m.Name, err)
+		}
+		versions = append(versions, m.Version)
+	}
+
+	return versions, nil
+}
+
+// Down membatalkan `steps` migration terakhir yang sudah diterapkan
+func Down(db *gorm.DB, steps int) ([]string, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for i := len(registry) - 1; i >= 0 && len(versions) < steps; i-- {
+		m := registry[i]
+		if _, ok := done[m.Version]; !ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{Version: m.Version}).Error
+		})
+		if err != nil {
+			return versions, fmt.Errorf("migration %s_%s down: %w", m.Version, m.Name, err)
+		}
+		versions = append(versions, m.Version)
+	}
+
+	return versions, nil
+}
+
+// StatusOf mengembalikan status setiap migration yang terdaftar
+func StatusOf(db *gorm.DB) ([]Status, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(registry))
+	for _, m := range registry {
+		status := Status{Version: m.Version, Name: m.Name}
+		if row, ok := done[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// dropIndexIfExists menghapus index hanya jika masih ada. Di SQLite DropColumn membuat
+// ulang tabel, sehingga index bisa sudah hilang saat Down migration mencapainya.
+func dropIndexIfExists(tx *gorm.DB, model interface{}, name string) error {
+	if !tx.Migrator().HasIndex(model, name) {
+		return nil
+	}
+	return tx.Migrator().DropIndex(model, name)
+}
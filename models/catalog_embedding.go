@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Jenis data katalog yang bisa dicari asisten chat
+const (
+	CatalogDestination = "destination"
+	CatalogCity        = "city"
+)
+
+// CatalogEmbedding adalah vektor embedding satu destinasi atau kota untuk model
+// tertentu. ContentHash adalah hash teks yang di-embed sehingga vektor dibuat ulang
+// hanya jika datanya berubah.
+type CatalogEmbedding struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	SourceType  string `json:"source_type" gorm:"size:20;not null;uniqueIndex:idx_catalog_embeddings_source,priority:1"`
+	SourceID    uint   `json:"source_id" gorm:"not null;uniqueIndex:idx_catalog_embeddings_source,priority:2"`
+	Model       string `json:"model" gorm:"size:100;not null;uniqueIndex:idx_catalog_embeddings_source,priority:3"`
+	ContentHash string `json:"content_hash" gorm:"size:64;not null"`
+	// Vector disimpan sebagai float32 little-endian
+	Vector    []byte    `json:"-" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
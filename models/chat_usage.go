@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ChatUsage adalah pemakaian token chat satu user dalam satu hari (UTC). Token
+// diambil dari usage yang dilaporkan provider LLM.
+type ChatUsage struct {
+	ID     uint `gorm:"primaryKey" json:"id"`
+	UserID uint `json:"user_id" gorm:"not null;uniqueIndex:idx_chat_usages_user_day,priority:1"`
+	// Day berformat YYYY-MM-DD
+	Day              string    `json:"day" gorm:"size:10;not null;uniqueIndex:idx_chat_usages_user_day,priority:2;index"`
+	PromptTokens     int       `json:"prompt_tokens" gorm:"not null;default:0"`
+	CompletionTokens int       `json:"completion_tokens" gorm:"not null;default:0"`
+	TotalTokens      int       `json:"total_tokens" gorm:"not null;default:0"`
+	Requests         int       `json:"requests" gorm:"not null;default:0"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ChatQuota mengganti kuota token harian default untuk satu user.
+// DailyTokenLimit 0 berarti tanpa batas.
+type ChatQuota struct {
+	UserID          uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	DailyTokenLimit int       `json:"daily_token_limit" gorm:"not null"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName ditulis eksplisit karena GORM tidak menjamakkan "quota"
+func (ChatQuota) TableName() string { return "chat_quotas" }
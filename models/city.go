@@ -1,8 +1,16 @@
 package models
 
 type City struct {
-	ID   uint   `gorm:"primaryKey" json:"id"`
-	Name string `json:"name"`
-	Lat  string `json:"lat"`
-	Long string `json:"long"`
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	Name      string   `json:"name"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// Coordinates mengembalikan koordinat kota jika sudah diisi
+func (c City) Coordinates() (float64, float64, bool) {
+	if c.Latitude == nil || c.Longitude == nil {
+		return 0, 0, false
+	}
+	return *c.Latitude, *c.Longitude, true
 }
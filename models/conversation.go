@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Role pesan dalam percakapan, sama dengan role di API Gemini
+const (
+	MessageRoleUser  = "user"
+	MessageRoleModel = "model"
+)
+
+// Conversation adalah satu sesi chat milik user dengan asisten
+type Conversation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Title     string    `json:"title" gorm:"size:100"`
+	Messages  []Message `json:"messages" gorm:"foreignKey:ConversationID"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"index"`
+}
+
+type Message struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	ConversationID uint       `json:"conversation_id" gorm:"not null;index"`
+	Role           string     `json:"role" gorm:"size:10;not null"`
+	Content        string     `json:"content" gorm:"type:text;not null"`
+	Citations      []Citation `json:"citations" gorm:"serializer:json;type:text"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Citation adalah data katalog yang dirujuk jawaban asisten. Marker adalah penanda
+// di teks jawaban, mis. "D12" untuk destinasi 12.
+type Citation struct {
+	Type   string `json:"type"`
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Marker string `json:"marker"`
+}
@@ -7,22 +7,52 @@ import (
 )
 
 type Destination struct {
-	ID               uint           `gorm:"primaryKey" json:"id"`
-	Name             string         `json:"name"`
-	CityID           uint           `json:"city_id"`
-	City             City           `json:"city" gorm:"foreignKey:CityID;references:ID"`
-	Position         float64        `json:"position"`
-	Address          string         `json:"address"`
-	OperationalHours string         `json:"operational_hours"`
-	TicketPrice      float64        `json:"ticket_price"`
-	Category         string         `json:"category"`
-	Description      string         `json:"description"`
-	Facilities       string         `json:"facilities"`
-	CreatedAt        time.Time      `json:"created_at"`
-	Images           []Image        `json:"images" gorm:"foreignKey:DestinationID"`
-	VideoContents    []VideoContent `json:"video_contents" gorm:"foreignKey:DestinationID"`
+	ID                        uint           `gorm:"primaryKey" json:"id"`
+	Name                      string         `json:"name"`
+	CityID                    uint           `json:"city_id"`
+	City                      City           `json:"city" gorm:"foreignKey:CityID;references:ID"`
+	Position                  float64        `json:"position"`
+	Latitude                  *float64       `json:"latitude" gorm:"index:idx_destinations_location,priority:1"`
+	Longitude                 *float64       `json:"longitude" gorm:"index:idx_destinations_location,priority:2"`
+	Address                   string         `json:"address"`
+	OperationalHours          string         `json:"operational_hours"`
+	TicketPrice               float64        `json:"ticket_price"`
+	Category                  string         `json:"category"`
+	Description               string         `json:"description"`
+	Facilities                string         `json:"facilities"`
+	Certifications            string         `json:"certifications"`
+	WastePractice             string         `json:"waste_practice" gorm:"size:20;not null;default:'none'"`
+	EnergyPractice            string         `json:"energy_practice" gorm:"size:20;not null;default:'none'"`
+	VisitorCapacity           int            `json:"visitor_capacity" gorm:"not null;default:0"`
+	PublicTransportAccessible bool           `json:"public_transport_accessible" gorm:"not null;default:false"`
+	EcoScore                  int            `json:"eco_score" gorm:"not null;default:0;index"`
+	AverageRating             float64        `json:"average_rating" gorm:"not null;default:0;index"`
+	ReviewCount               int            `json:"review_count" gorm:"not null;default:0"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	Images                    []Image        `json:"images" gorm:"foreignKey:DestinationID"`
+	VideoContents             []VideoContent `json:"video_contents" gorm:"foreignKey:DestinationID"`
+}
+
+// BeforeSave menghitung ulang EcoScore setiap kali destinasi dibuat atau disimpan
+func (b *Destination) BeforeSave(tx *gorm.DB) (err error) {
+	if b.WastePractice == "" {
+		b.WastePractice = "none"
+	}
+	if b.EnergyPractice == "" {
+		b.EnergyPractice = "none"
+	}
+	b.EcoScore = b.ComputeEcoScore()
+	return nil
 }
 
 func (b *Destination) AfterCreate(tx *gorm.DB) (err error) {
 	return tx.Model(b).Preload("Images").Error
 }
+
+// Coordinates mengembalikan koordinat destinasi jika sudah diisi
+func (b Destination) Coordinates() (float64, float64, bool) {
+	if b.Latitude == nil || b.Longitude == nil {
+		return 0, 0, false
+	}
+	return *b.Latitude, *b.Longitude, true
+}
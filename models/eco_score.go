@@ -0,0 +1,55 @@
+package models
+
+import "strings"
+
+// Praktik pengelolaan sampah dan energi yang diakui, beserta poinnya
+var (
+	WastePracticePoints = map[string]int{
+		"none":       0,
+		"basic":      5,
+		"recycling":  12,
+		"zero_waste": 20,
+	}
+	EnergyPracticePoints = map[string]int{
+		"none":              0,
+		"partial_renewable": 10,
+		"renewable":         20,
+	}
+)
+
+// ComputeEcoScore menghitung skor keberlanjutan 0-100:
+// sertifikasi (10 per sertifikasi, maks 30), sampah (maks 20), energi (maks 20),
+// batas kapasitas pengunjung (15), dan akses transportasi umum (15).
+func (b Destination) ComputeEcoScore() int {
+	score := 0
+
+	certifications := len(b.CertificationList())
+	if certifications > 3 {
+		certifications = 3
+	}
+	score += certifications * 10
+
+	score += WastePracticePoints[b.WastePractice]
+	score += EnergyPracticePoints[b.EnergyPractice]
+
+	if b.VisitorCapacity > 0 {
+		score += 15
+	}
+	if b.PublicTransportAccessible {
+		score += 15
+	}
+
+	return score
+}
+
+// CertificationList memecah Certifications (dipisah koma, seperti Facilities)
+// menjadi slice tanpa entri kosong
+func (b Destination) CertificationList() []string {
+	list := []string{}
+	for _, certification := range strings.Split(b.Certifications, ",") {
+		if certification = strings.TrimSpace(certification); certification != "" {
+			list = append(list, certification)
+		}
+	}
+	return list
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+type Favorite struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	UserID        uint        `json:"user_id" gorm:"uniqueIndex:idx_favorites_user_destination,priority:1;not null"`
+	DestinationID uint        `json:"destination_id" gorm:"uniqueIndex:idx_favorites_user_destination,priority:2;not null;index"`
+	Destination   Destination `json:"-" gorm:"foreignKey:DestinationID;references:ID"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
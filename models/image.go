@@ -1,7 +1,27 @@
 package models
 
+// Status pembuatan varian ukuran gambar
+const (
+	ImageVariantsPending = "pending"
+	ImageVariantsReady   = "ready"
+	ImageVariantsFailed  = "failed"
+)
+
 type Image struct {
-	ID            uint   `gorm:"primaryKey" json:"id"`
-	DestinationID uint   `json:"destination_id"`
-	URL           string `json:"url"`
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	DestinationID   uint           `json:"destination_id"`
+	URL             string         `json:"url"`
+	VariantsStatus  string         `json:"variants_status" gorm:"size:20;not null;default:'pending';index"`
+	VariantAttempts int            `json:"-" gorm:"not null;default:0"`
+	Variants        []ImageVariant `json:"variants" gorm:"foreignKey:ImageID"`
+}
+
+// ImageVariant adalah satu ukuran (thumbnail, medium, large) dari Image
+type ImageVariant struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	ImageID uint   `json:"image_id" gorm:"uniqueIndex:idx_image_variants_image_name,priority:1;not null"`
+	Name    string `json:"name" gorm:"uniqueIndex:idx_image_variants_image_name,priority:2;size:20;not null"`
+	Key     string `json:"key" gorm:"not null"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
 }
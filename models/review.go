@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// Status review yang dimoderasi admin
+const (
+	ReviewVisible = "visible"
+	ReviewHidden  = "hidden"
+)
+
+type Review struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	DestinationID uint          `json:"destination_id" gorm:"uniqueIndex:idx_reviews_destination_user,priority:1;not null"`
+	UserID        uint          `json:"user_id" gorm:"uniqueIndex:idx_reviews_destination_user,priority:2;not null"`
+	User          User          `json:"-" gorm:"foreignKey:UserID;references:ID"`
+	Rating        int           `json:"rating" gorm:"not null"`
+	Comment       string        `json:"comment" gorm:"type:text"`
+	Status        string        `json:"status" gorm:"size:20;not null;default:'visible';index"`
+	Flagged       bool          `json:"flagged" gorm:"not null;default:false"`
+	FlagReason    string        `json:"flag_reason"`
+	Photos        []ReviewPhoto `json:"photos" gorm:"foreignKey:ReviewID"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type ReviewPhoto struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	ReviewID uint   `json:"review_id" gorm:"index"`
+	URL      string `json:"url"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// RevokedToken menyimpan jti atau session id (sid) access token yang sudah tidak berlaku
+type RevokedToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TokenID   string    `gorm:"size:64;uniqueIndex" json:"token_id"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -12,6 +12,10 @@ type Route struct {
 	Distance            float64            `json:"distance"`
 	Time                string             `json:"time"`
 	Cost                int                `json:"cost"`
+	OpenEnded           bool               `json:"openEnded" gorm:"not null;default:false"`
+	TransportMode       string             `json:"transportMode" gorm:"size:20;not null;default:car"`
+	Travellers          int                `json:"travellers" gorm:"not null;default:1"`
+	CarbonKg            float64            `json:"carbonKg"`
 	CreatedAt           time.Time          `json:"created_at"`
 	Destinations        []RouteDestination `json:"destinations" gorm:"foreignKey:RouteID"`
 }
@@ -8,5 +8,11 @@ type RouteDestination struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
 	RouteID       uint      `json:"routeID"`
 	DestinationID uint      `json:"destinationID"`
+	Sequence      int       `json:"sequence" gorm:"not null;default:0"`
+	Day           int       `json:"day" gorm:"not null;default:1"`
+	ArrivalTime   string    `json:"arrival_time" gorm:"size:5"`
+	DepartureTime string    `json:"departure_time" gorm:"size:5"`
+	StayMinutes   int       `json:"stay_minutes" gorm:"not null;default:0"`
+	Notes         string    `json:"notes" gorm:"type:text"`
 	CreatedAt     time.Time `json:"created_at"`
 }
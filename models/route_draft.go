@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Status draft rute
+const (
+	RouteDraftPending   = "pending"
+	RouteDraftConfirmed = "confirmed"
+)
+
+// RouteDraft adalah rute yang disusun asisten chat untuk user. Draft baru menjadi
+// Route setelah user mengonfirmasinya lewat POST /route/drafts/{id}/confirm.
+type RouteDraft struct {
+	ID     uint `gorm:"primaryKey" json:"id"`
+	UserID uint `json:"userID" gorm:"not null;index"`
+	// MessageID adalah jawaban asisten yang menyertakan draft ini
+	MessageID           *uint            `json:"messageID" gorm:"index"`
+	OriginCityName      string           `json:"originCityName"`
+	DestinationCityName string           `json:"destinationCityName"`
+	OpenEnded           bool             `json:"openEnded" gorm:"not null;default:false"`
+	Stops               []RouteDraftStop `json:"stops" gorm:"serializer:json;type:text"`
+	Distance            float64          `json:"distance"`
+	TransportMode       string           `json:"transportMode" gorm:"size:20;not null;default:car"`
+	Travellers          int              `json:"travellers" gorm:"not null;default:1"`
+	CarbonKg            float64          `json:"carbonKg"`
+	Status              string           `json:"status" gorm:"size:20;not null;default:pending"`
+	// RouteID diisi setelah draft dikonfirmasi
+	RouteID   *uint     `json:"routeID"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RouteDraftStop adalah destinasi di draft sesuai urutan kunjungan
+type RouteDraftStop struct {
+	Sequence      int     `json:"sequence"`
+	DestinationID uint    `json:"destination_id"`
+	Name          string  `json:"name"`
+	LegDistance   float64 `json:"leg_distance"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	gorm.Model
@@ -16,4 +20,11 @@ type User struct {
 	File        string `json:"file"`
 	PhoneNumber string `json:"phone_number"`
 	Gender      string `json:"gender"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// IsEmailVerified mengecek apakah pengguna sudah memverifikasi email
+func (u User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
@@ -1,9 +1,20 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type VideoContentView struct {
-	gorm.Model
-	VideoContentID uint `json:"video_content_id"`
-	UserID         uint `json:"user_id"`
+	ID             uint      `gorm:"primaryKey"`
+	CreatedAt      time.Time `gorm:"index:idx_video_views_dedup,priority:3"`
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+	VideoContentID uint           `json:"video_content_id" gorm:"index:idx_video_views_dedup,priority:1;uniqueIndex:idx_video_views_bucket,priority:1"`
+	UserID         uint           `json:"user_id" gorm:"index:idx_video_views_dedup,priority:2;uniqueIndex:idx_video_views_bucket,priority:2"`
+	DestinationID  uint           `json:"destination_id" gorm:"index"`
+	// WindowBucket adalah nomor jendela dedup saat view dicatat (unix / panjang jendela).
+	// NULL untuk view yang dicatat sebelum kolom ini ada.
+	WindowBucket *int64 `json:"-" gorm:"uniqueIndex:idx_video_views_bucket,priority:3"`
 }
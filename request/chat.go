@@ -0,0 +1,12 @@
+package request
+
+type ChatInput struct {
+	Message string `json:"message" validate:"required,max=4000"`
+	// ConversationID melanjutkan percakapan yang sudah ada; kosong memulai percakapan baru
+	ConversationID uint `json:"conversation_id"`
+}
+
+type ChatQuotaInput struct {
+	// DailyTokenLimit 0 berarti tanpa batas
+	DailyTokenLimit *int `json:"daily_token_limit" validate:"required,min=0"`
+}
@@ -1,17 +1,24 @@
 package request
 
 type CreateDestinationInput struct {
-	Name             string       `json:"name"`
-	City             string       `json:"city"`
-	Position         float64      `json:"position"`
-	Address          string       `json:"address"`
-	OperationalHours string       `json:"operational_hours"`
-	TicketPrice      float64      `json:"ticket_price"`
-	Category         string       `json:"category"`
-	Description      string       `json:"description"`
-	Facilities       string       `json:"facilities"`
-	Image            []string     `json:"image"`
-	Video            []VideoInput `json:"video_contents"`
+	Name                      string       `json:"name"`
+	City                      string       `json:"city"`
+	Position                  float64      `json:"position"`
+	Latitude                  *float64     `json:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude                 *float64     `json:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+	Address                   string       `json:"address"`
+	OperationalHours          string       `json:"operational_hours"`
+	TicketPrice               float64      `json:"ticket_price"`
+	Category                  string       `json:"category"`
+	Description               string       `json:"description"`
+	Facilities                string       `json:"facilities"`
+	Certifications            []string     `json:"certifications" validate:"max=10,dive,required,max=100,excludes=0x2C"`
+	WastePractice             string       `json:"waste_practice" validate:"omitempty,oneof=none basic recycling zero_waste"`
+	EnergyPractice            string       `json:"energy_practice" validate:"omitempty,oneof=none partial_renewable renewable"`
+	VisitorCapacity           int          `json:"visitor_capacity" validate:"min=0"`
+	PublicTransportAccessible bool         `json:"public_transport_accessible"`
+	Image                     []string     `json:"image"`
+	Video                     []VideoInput `json:"video_contents"`
 }
 
 // video
@@ -0,0 +1,12 @@
+package request
+
+type ReviewInput struct {
+	Rating  int      `json:"rating" validate:"required,min=1,max=5"`
+	Comment string   `json:"comment" validate:"max=5000"`
+	Photos  []string `json:"photos" validate:"max=5,dive,required,url"`
+}
+
+type ModerateReviewInput struct {
+	Action string `json:"action" validate:"required,oneof=hide unhide flag unflag"`
+	Reason string `json:"reason" validate:"max=500"`
+}
@@ -1,11 +1,50 @@
 package request
 
 type CreateRouteInput struct {
-	UserID              uint    `json:"userID"`
-	OriginCityName      string  `json:"originCityName"`
-	DestinationCityName string  `json:"destinationCityName"`
-	Destinations        []uint  `json:"destinations"`
-	Distance            float64 `json:"distance"`
-	Time                string  `json:"time"`
-	Cost                int     `json:"cost"`
+	OriginCityName      string `json:"originCityName" validate:"required"`
+	DestinationCityName string `json:"destinationCityName"`
+	Destinations        []uint `json:"destinations" validate:"max=50,dive,required"`
+	TransportMode       string `json:"transportMode"`
+	Travellers          int    `json:"travellers" validate:"omitempty,min=1,max=100"`
+	Time                string `json:"time"`
+	Cost                int    `json:"cost"`
+}
+
+type OptimizeRouteInput struct {
+	OriginCityName      string `json:"originCityName" validate:"required"`
+	DestinationCityName string `json:"destinationCityName"`
+	Destinations        []uint `json:"destinations" validate:"max=50,dive,required"`
+}
+
+type RouteStopInput struct {
+	DestinationID uint   `json:"destination_id" validate:"required"`
+	Day           int    `json:"day" validate:"omitempty,min=1,max=365"`
+	Position      int    `json:"position" validate:"omitempty,min=1"`
+	ArrivalTime   string `json:"arrival_time" validate:"omitempty,datetime=15:04"`
+	DepartureTime string `json:"departure_time" validate:"omitempty,datetime=15:04"`
+	StayMinutes   *int   `json:"stay_minutes" validate:"omitempty,min=0,max=1440"`
+	Notes         string `json:"notes" validate:"max=2000"`
+}
+
+// UpdateRouteStopInput hanya mengubah field yang dikirim; string kosong menghapus jam
+type UpdateRouteStopInput struct {
+	Day           *int    `json:"day" validate:"omitempty,min=1,max=365"`
+	ArrivalTime   *string `json:"arrival_time" validate:"omitempty,len=0|datetime=15:04"`
+	DepartureTime *string `json:"departure_time" validate:"omitempty,len=0|datetime=15:04"`
+	StayMinutes   *int    `json:"stay_minutes" validate:"omitempty,min=0,max=1440"`
+	Notes         *string `json:"notes" validate:"omitempty,max=2000"`
+}
+
+type ReorderRouteStop struct {
+	ID  uint `json:"id" validate:"required"`
+	Day int  `json:"day" validate:"omitempty,min=1,max=365"`
+}
+
+type ReorderRouteStopsInput struct {
+	Stops []ReorderRouteStop `json:"stops" validate:"required,min=1,dive"`
+}
+
+type RouteTransportInput struct {
+	TransportMode string `json:"transportMode" validate:"required"`
+	Travellers    int    `json:"travellers" validate:"omitempty,min=1,max=100"`
 }
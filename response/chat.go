@@ -0,0 +1,52 @@
+package response
+
+import (
+	"backend/models"
+	"time"
+)
+
+type ConversationResponse struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type MessageResponse struct {
+	ID        uint       `json:"id"`
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	Citations []Citation `json:"citations"`
+	// RouteDrafts adalah draft rute yang diusulkan asisten di pesan ini
+	RouteDrafts []models.RouteDraft `json:"route_drafts"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// Citation adalah destinasi atau kota dari katalog yang dirujuk jawaban asisten.
+// Type "destination" merujuk ke GET /destination/{id}.
+type Citation struct {
+	Type   string `json:"type"`
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Marker string `json:"marker"`
+}
+
+type ConversationDetailResponse struct {
+	ConversationResponse
+	Messages []MessageResponse `json:"messages"`
+}
+
+// ChatQuota adalah kuota token chat harian user dan pemakaiannya pada Day (UTC)
+type ChatQuota struct {
+	UserID uint   `json:"user_id"`
+	Day    string `json:"day"`
+	// DailyTokenLimit 0 berarti tanpa batas
+	DailyTokenLimit int `json:"daily_token_limit"`
+	// Custom menandai kuota yang diatur admin, bukan default CHAT_DAILY_TOKEN_QUOTA
+	Custom     bool `json:"custom"`
+	UsedTokens int  `json:"used_tokens"`
+	// RemainingTokens null jika tanpa batas
+	RemainingTokens *int      `json:"remaining_tokens"`
+	Requests        int       `json:"requests"`
+	ResetsAt        time.Time `json:"resets_at"`
+}
@@ -7,25 +7,56 @@ type DestinationResponse struct {
 	Name             string         `json:"name"`
 	City             City           `json:"city" gorm:"foreignKey:CityID;references:ID"`
 	Position         float64        `json:"position"`
+	Latitude         *float64       `json:"latitude"`
+	Longitude        *float64       `json:"longitude"`
 	Address          string         `json:"address"`
 	OperationalHours string         `json:"operational_hours"`
 	TicketPrice      float64        `json:"ticket_price"`
 	Category         string         `json:"category"`
 	Description      string         `json:"description"`
 	Facilities       []string       `json:"facilities"`
+	EcoScore         int            `json:"eco_score"`
+	AverageRating    float64        `json:"average_rating"`
+	ReviewCount      int            `json:"review_count"`
+	IsFavorite       bool           `json:"is_favorite"`
+	Sustainability   Sustainability `json:"sustainability"`
 	CreatedAt        time.Time      `json:"created_at"`
 	Images           []Image        `json:"images" gorm:"foreignKey:DestinationID"`
 	VideoContents    []VideoContent `json:"video_contents" gorm:"foreignKey:DestinationID"`
 }
 
+type Sustainability struct {
+	Certifications            []string `json:"certifications"`
+	WastePractice             string   `json:"waste_practice"`
+	EnergyPractice            string   `json:"energy_practice"`
+	VisitorCapacity           int      `json:"visitor_capacity"`
+	PublicTransportAccessible bool     `json:"public_transport_accessible"`
+}
+
 type City struct {
-	ID   uint   `gorm:"primaryKey" json:"id"`
-	Name string `json:"name"`
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	Name      string   `json:"name"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// NearbyDestination adalah destinasi beserta jaraknya dari titik pencarian
+type NearbyDestination struct {
+	DestinationResponse
+	DistanceKm float64 `json:"distance_km"`
 }
 
 type Image struct {
 	DestinationID uint   `json:"destination_id"`
 	URL           string `json:"url"`
+	// Variants berisi ukuran thumbnail, medium dan large; kosong selama belum diproses
+	Variants map[string]ImageVariant `json:"variants"`
+}
+
+type ImageVariant struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 type VideoContent struct {
@@ -34,3 +65,21 @@ type VideoContent struct {
 	URL           string `json:"url"`
 	Description   string `json:"description"`
 }
+
+type VideoViewRanking struct {
+	ID              uint   `json:"id"`
+	Title           string `json:"title"`
+	URL             string `json:"url"`
+	DestinationID   uint   `json:"destination_id"`
+	DestinationName string `json:"destination_name"`
+	ViewCount       int64  `json:"view_count"`
+}
+
+type DestinationViewRanking struct {
+	ID          uint           `json:"id"`
+	Name        string         `json:"name"`
+	Address     string         `json:"address"`
+	Description string         `json:"description"`
+	ViewCount   int64          `json:"view_count"`
+	Videos      []VideoContent `json:"videos" gorm:"-"`
+}
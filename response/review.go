@@ -0,0 +1,22 @@
+package response
+
+import "time"
+
+type ReviewAuthor struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type ReviewResponse struct {
+	ID            uint         `json:"id"`
+	DestinationID uint         `json:"destination_id"`
+	User          ReviewAuthor `json:"user"`
+	Rating        int          `json:"rating"`
+	Comment       string       `json:"comment"`
+	Photos        []string     `json:"photos"`
+	Status        string       `json:"status"`
+	Flagged       bool         `json:"flagged"`
+	FlagReason    string       `json:"flag_reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
@@ -1,18 +1,72 @@
 package response
 
 import (
+	"backend/emissions"
 	"backend/models"
 	"time"
 )
 
 type RouteResponse struct {
-	ID                  uint                 `gorm:"primaryKey" json:"id"`
-	UserID              uint                 `json:"userID"`
-	OriginCityName      string               `json:"originCityName"`
-	DestinationCityName string               `json:"destinationCityName"`
-	Distance            float64              `json:"distance"`
-	Time                string               `json:"time"`
-	Cost                int                  `json:"cost"`
-	CreatedAt           time.Time            `json:"created_at"`
-	Destinations        []models.Destination `json:"destinations"`
+	ID                  uint                    `gorm:"primaryKey" json:"id"`
+	UserID              uint                    `json:"userID"`
+	OriginCityName      string                  `json:"originCityName"`
+	DestinationCityName string                  `json:"destinationCityName"`
+	Distance            float64                 `json:"distance"`
+	Time                string                  `json:"time"`
+	Cost                int                     `json:"cost"`
+	TransportMode       string                  `json:"transportMode"`
+	Travellers          int                     `json:"travellers"`
+	CarbonKg            float64                 `json:"carbonKg"`
+	CarbonAlternatives  []emissions.Alternative `json:"carbonAlternatives"`
+	CreatedAt           time.Time               `json:"created_at"`
+	Destinations        []models.Destination    `json:"destinations"`
+}
+
+type RouteStop struct {
+	Sequence    int                `json:"sequence"`
+	LegDistance float64            `json:"leg_distance"`
+	Destination models.Destination `json:"destination"`
+}
+
+type OptimizedRouteResponse struct {
+	OriginCityName      string      `json:"originCityName"`
+	DestinationCityName string      `json:"destinationCityName"`
+	Distance            float64     `json:"distance"`
+	FinalLegDistance    float64     `json:"final_leg_distance"`
+	Stops               []RouteStop `json:"stops"`
+}
+
+type ItineraryStop struct {
+	models.RouteDestination
+	Destination models.Destination `json:"destination"`
+}
+
+type ItineraryDay struct {
+	Day   int             `json:"day"`
+	Stops []ItineraryStop `json:"stops"`
+}
+
+type RouteDetailResponse struct {
+	ID                  uint                    `json:"id"`
+	UserID              uint                    `json:"userID"`
+	OriginCityName      string                  `json:"originCityName"`
+	DestinationCityName string                  `json:"destinationCityName"`
+	OpenEnded           bool                    `json:"openEnded"`
+	Distance            float64                 `json:"distance"`
+	Time                string                  `json:"time"`
+	Cost                int                     `json:"cost"`
+	TransportMode       string                  `json:"transportMode"`
+	Travellers          int                     `json:"travellers"`
+	CarbonKg            float64                 `json:"carbonKg"`
+	CarbonAlternatives  []emissions.Alternative `json:"carbonAlternatives"`
+	CreatedAt           time.Time               `json:"created_at"`
+	Days                []ItineraryDay          `json:"days"`
+}
+
+type CarbonEstimateResponse struct {
+	DistanceKm    float64                 `json:"distance_km"`
+	TransportMode string                  `json:"transport_mode"`
+	Travellers    int                     `json:"travellers"`
+	CarbonKg      float64                 `json:"carbon_kg"`
+	Alternatives  []emissions.Alternative `json:"alternatives"`
 }
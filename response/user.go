@@ -13,4 +13,6 @@ type UserResponse struct {
 	File        string `json:"file"`
 	PhoneNumber string `json:"phone_number"`
 	Gender      string `json:"gender"`
+
+	EmailVerified bool `json:"email_verified"`
 }
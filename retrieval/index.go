@@ -0,0 +1,203 @@
+package retrieval
+
+import (
+	"backend/models"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// destinationResult meringkas destinasi untuk prompt dan embedding
+func destinationResult(destination models.Destination) Result {
+	var b strings.Builder
+	b.WriteString(destination.Name)
+	if destination.Category != "" {
+		fmt.Fprintf(&b, " (%s)", destination.Category)
+	}
+	if destination.City.Name != "" {
+		fmt.Fprintf(&b, ", %s", destination.City.Name)
+	}
+	b.WriteString(".")
+	if destination.Address != "" {
+		fmt.Fprintf(&b, " Alamat: %s.", destination.Address)
+	}
+	if destination.OperationalHours != "" {
+		fmt.Fprintf(&b, " Jam buka: %s.", destination.OperationalHours)
+	}
+	if destination.TicketPrice > 0 {
+		fmt.Fprintf(&b, " Tiket: Rp%.0f.", destination.TicketPrice)
+	} else {
+		b.WriteString(" Tiket: gratis.")
+	}
+	if destination.ReviewCount > 0 {
+		fmt.Fprintf(&b, " Rating %.1f dari %d ulasan.", destination.AverageRating, destination.ReviewCount)
+	}
+	fmt.Fprintf(&b, " Eco score %d/100.", destination.EcoScore)
+	if destination.Facilities != "" {
+		fmt.Fprintf(&b, " Fasilitas: %s.", destination.Facilities)
+	}
+	if destination.Description != "" {
+		fmt.Fprintf(&b, " %s", truncateText(destination.Description, 400))
+	}
+	if titles := videoTitles(destination); len(titles) > 0 {
+		fmt.Fprintf(&b, " Video: %s.", strings.Join(titles, "; "))
+	}
+
+	return Result{Type: models.CatalogDestination, ID: destination.ID, Name: destination.Name, Text: b.String()}
+}
+
+// cityResult meringkas kota beserta destinasi di dalamnya
+func cityResult(city models.City, destinations []string) Result {
+	text := fmt.Sprintf("Kota %s.", city.Name)
+	if len(destinations) > 0 {
+		text += " Destinasi: " + strings.Join(destinations, ", ") + "."
+	}
+	return Result{Type: models.CatalogCity, ID: city.ID, Name: city.Name, Text: text}
+}
+
+func videoTitles(destination models.Destination) []string {
+	titles := make([]string, 0, len(destination.VideoContents))
+	for _, video := range destination.VideoContents {
+		if video.Title != "" {
+			titles = append(titles, video.Title)
+		}
+	}
+	return titles
+}
+
+func truncateText(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > max {
+		return strings.TrimSpace(string(runes[:max])) + "…"
+	}
+	return text
+}
+
+// Index membuat embedding untuk destinasi dan kota yang belum punya vektor atau
+// datanya berubah sejak terakhir di-embed, lalu menghapus vektor milik data yang
+// sudah dihapus. Teks dikirim ke Embedder per batchSize. Mengembalikan jumlah
+// vektor yang dibuat.
+func (r *Retriever) Index(ctx context.Context, batchSize int) (int, error) {
+	if r.Embedder == nil {
+		return 0, nil
+	}
+	db := r.DB.WithContext(ctx)
+	model := r.Embedder.Name()
+
+	documents, err := r.documents(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var existing []models.CatalogEmbedding
+	if err := db.Select("id", "source_type", "source_id", "content_hash").Where("model = ?", model).Find(&existing).Error; err != nil {
+		return 0, err
+	}
+	hashes := map[sourceKey]string{}
+	var orphaned []uint
+	for _, embedding := range existing {
+		key := sourceKey{embedding.SourceType, embedding.SourceID}
+		if _, ok := documents[key]; !ok {
+			orphaned = append(orphaned, embedding.ID)
+			continue
+		}
+		hashes[key] = embedding.ContentHash
+	}
+	if len(orphaned) > 0 {
+		if err := db.Delete(&models.CatalogEmbedding{}, orphaned).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	var pending []models.CatalogEmbedding
+	var texts []string
+	for key, text := range documents {
+		hash := contentHash(text)
+		if hashes[key] == hash {
+			continue
+		}
+		pending = append(pending, models.CatalogEmbedding{SourceType: key.Type, SourceID: key.ID, Model: model, ContentHash: hash})
+		texts = append(texts, text)
+	}
+
+	indexed := 0
+	for start := 0; start < len(pending); start += batchSize {
+		end := min(start+batchSize, len(pending))
+		vectors, err := r.Embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			return indexed, err
+		}
+
+		batch := pending[start:end]
+		for i := range batch {
+			batch[i].Vector = encodeVector(vectors[i])
+		}
+		err = db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source_type"}, {Name: "source_id"}, {Name: "model"}},
+			DoUpdates: clause.AssignmentColumns([]string{"content_hash", "vector", "updated_at"}),
+		}).Create(&batch).Error
+		if err != nil {
+			return indexed, err
+		}
+		indexed += len(batch)
+	}
+	return indexed, nil
+}
+
+// documents menyusun teks setiap destinasi dan kota yang akan di-embed
+func (r *Retriever) documents(ctx context.Context) (map[sourceKey]string, error) {
+	db := r.DB.WithContext(ctx)
+	documents := map[sourceKey]string{}
+
+	var destinations []models.Destination
+	err := db.Preload("City").Preload("VideoContents").FindInBatches(&destinations, 200, func(tx *gorm.DB, batch int) error {
+		for _, destination := range destinations {
+			documents[sourceKey{models.CatalogDestination, destination.ID}] = destinationResult(destination).Text
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var cities []models.City
+	if err := db.Find(&cities).Error; err != nil {
+		return nil, err
+	}
+	names, err := r.destinationNames(ctx, cities)
+	if err != nil {
+		return nil, err
+	}
+	for _, city := range cities {
+		documents[sourceKey{models.CatalogCity, city.ID}] = cityResult(city, names[city.ID]).Text
+	}
+	return documents, nil
+}
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeVector(vector []float32) []byte {
+	data := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(value))
+	}
+	return data
+}
+
+func decodeVector(data []byte) []float32 {
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector
+}
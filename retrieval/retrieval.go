@@ -0,0 +1,342 @@
+package retrieval
+
+import (
+	"backend/llm"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Result adalah satu destinasi atau kota yang relevan dengan pertanyaan
+type Result struct {
+	Type string
+	ID   uint
+	Name string
+	// Text adalah ringkasan data yang disisipkan ke prompt
+	Text  string
+	Score float64
+}
+
+// Marker adalah penanda yang dipakai model untuk merujuk hasil ini, mis. "D12"
+func (r Result) Marker() string {
+	if r.Type == models.CatalogCity {
+		return fmt.Sprintf("C%d", r.ID)
+	}
+	return fmt.Sprintf("D%d", r.ID)
+}
+
+type sourceKey struct {
+	Type string
+	ID   uint
+}
+
+// Retriever mencari destinasi dan kota di database yang relevan dengan pertanyaan.
+// Pencarian kata kunci selalu dipakai; jika Embedder diisi, hasilnya digabung dengan
+// pencarian kemiripan vektor dari tabel catalog_embeddings (lihat Index).
+type Retriever struct {
+	DB       *gorm.DB
+	Embedder llm.Embedder
+	// Limit adalah jumlah hasil maksimal
+	Limit int
+	// MinSimilarity adalah kemiripan kosinus minimal hasil pencarian vektor
+	MinSimilarity float64
+}
+
+// candidateLimit membatasi kandidat tiap metode pencarian sebelum digabung
+const candidateLimit = 20
+
+// Search mengembalikan sampai Limit hasil paling relevan untuk query, urut dari
+// yang paling relevan
+func (r *Retriever) Search(ctx context.Context, query string) ([]Result, error) {
+	if r.Limit <= 0 {
+		return nil, nil
+	}
+
+	keywordResults, err := r.keywordSearch(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if r.Embedder == nil {
+		return truncate(keywordResults, r.Limit), nil
+	}
+
+	// Embedder yang gagal tidak boleh menghilangkan hasil kata kunci yang sudah ada
+	vectorResults, err := r.vectorSearch(ctx, query)
+	if err != nil {
+		log.Println("Vector search failed, using keyword results only:", err)
+		return truncate(keywordResults, r.Limit), nil
+	}
+	return truncate(fuse(keywordResults, vectorResults), r.Limit), nil
+}
+
+// fuse menggabungkan beberapa peringkat dengan reciprocal rank fusion sehingga skor
+// kata kunci dan kemiripan vektor yang skalanya berbeda tidak perlu dinormalisasi
+func fuse(rankings ...[]Result) []Result {
+	const k = 60
+	merged := map[sourceKey]*Result{}
+	var order []sourceKey
+	for _, ranking := range rankings {
+		for rank, result := range ranking {
+			key := sourceKey{result.Type, result.ID}
+			if _, ok := merged[key]; !ok {
+				copied := result
+				copied.Score = 0
+				merged[key] = &copied
+				order = append(order, key)
+			}
+			merged[key].Score += 1 / float64(k+rank+1)
+		}
+	}
+
+	results := make([]Result, 0, len(order))
+	for _, key := range order {
+		results = append(results, *merged[key])
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
+
+func truncate(results []Result, limit int) []Result {
+	if len(results) > limit {
+		return results[:limit]
+	}
+	return results
+}
+
+// stopwords adalah kata umum (Indonesia dan Inggris) yang tidak berguna untuk pencarian
+var stopwords = map[string]bool{
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "untuk": true,
+	"ada": true, "apa": true, "mana": true, "dengan": true, "ini": true, "itu": true,
+	"saya": true, "aku": true, "kami": true, "mau": true, "ingin": true, "bisa": true,
+	"tempat": true, "wisata": true, "rekomendasi": true, "paling": true, "bagus": true,
+	"dong": true, "sih": true, "nya": true, "atau": true, "juga": true, "apakah": true,
+	"the": true, "and": true, "for": true, "what": true, "where": true, "are": true,
+	"with": true, "can": true, "you": true, "some": true, "best": true, "good": true,
+	"place": true, "places": true, "recommend": true, "visit": true, "near": true,
+}
+
+// Keywords memecah query menjadi kata kunci unik (huruf kecil, minimal 3 karakter,
+// tanpa stopword)
+func Keywords(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := map[string]bool{}
+	var keywords []string
+	for _, word := range words {
+		if len([]rune(word)) < 3 || stopwords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+		if len(keywords) == 8 {
+			break
+		}
+	}
+	return keywords
+}
+
+// keywordSearch mencocokkan kata kunci dengan nama, kategori, deskripsi, kota, dan
+// judul video destinasi serta nama kota. Skor dihitung dari bobot kolom yang cocok.
+func (r *Retriever) keywordSearch(ctx context.Context, query string) ([]Result, error) {
+	keywords := Keywords(query)
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+
+	db := r.DB.WithContext(ctx)
+	var destinationClauses, cityClauses []string
+	var destinationArgs, cityArgs []interface{}
+	for _, keyword := range keywords {
+		pattern := "%" + keyword + "%"
+		destinationClauses = append(destinationClauses,
+			"LOWER(destinations.name) LIKE ? OR LOWER(destinations.category) LIKE ? OR LOWER(destinations.description) LIKE ? OR LOWER(cities.name) LIKE ? "+
+				"OR destinations.id IN (SELECT destination_id FROM video_contents WHERE LOWER(title) LIKE ?)")
+		destinationArgs = append(destinationArgs, pattern, pattern, pattern, pattern, pattern)
+		cityClauses = append(cityClauses, "LOWER(name) LIKE ?")
+		cityArgs = append(cityArgs, pattern)
+	}
+
+	var destinations []models.Destination
+	err := db.Select("destinations.*").
+		Joins("JOIN cities ON cities.id = destinations.city_id").
+		Where(strings.Join(destinationClauses, " OR "), destinationArgs...).
+		Order("destinations.average_rating DESC").Limit(100).
+		Preload("City").Preload("VideoContents").
+		Find(&destinations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var cities []models.City
+	if err := db.Where(strings.Join(cityClauses, " OR "), cityArgs...).Limit(candidateLimit).Find(&cities).Error; err != nil {
+		return nil, err
+	}
+	cityDestinations, err := r.destinationNames(ctx, cities)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, destination := range destinations {
+		result := destinationResult(destination)
+		result.Score = keywordScore(keywords,
+			field{destination.Name, 3},
+			field{destination.City.Name, 2},
+			field{destination.Category, 2},
+			field{destination.Description, 1},
+			field{strings.Join(videoTitles(destination), " "), 1},
+		)
+		results = append(results, result)
+	}
+	for _, city := range cities {
+		result := cityResult(city, cityDestinations[city.ID])
+		result.Score = keywordScore(keywords, field{city.Name, 3})
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return truncate(results, candidateLimit), nil
+}
+
+// field adalah teks yang dicocokkan dengan kata kunci beserta bobotnya
+type field struct {
+	text   string
+	weight float64
+}
+
+func keywordScore(keywords []string, fields ...field) float64 {
+	score := 0.0
+	for _, f := range fields {
+		text := strings.ToLower(f.text)
+		for _, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				score += f.weight
+			}
+		}
+	}
+	return score
+}
+
+// vectorSearch membandingkan embedding query dengan semua vektor katalog untuk model
+// yang sama. Katalog berisi ratusan sampai ribuan data sehingga perbandingan langsung
+// di aplikasi masih murah.
+func (r *Retriever) vectorSearch(ctx context.Context, query string) ([]Result, error) {
+	vectors, err := r.Embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	queryVector := vectors[0]
+
+	var embeddings []models.CatalogEmbedding
+	err = r.DB.WithContext(ctx).Select("source_type", "source_id", "vector").
+		Where("model = ?", r.Embedder.Name()).Find(&embeddings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type hit struct {
+		key        sourceKey
+		similarity float64
+	}
+	var hits []hit
+	for _, embedding := range embeddings {
+		similarity := llm.Cosine(queryVector, decodeVector(embedding.Vector))
+		if similarity >= r.MinSimilarity {
+			hits = append(hits, hit{sourceKey{embedding.SourceType, embedding.SourceID}, similarity})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].similarity > hits[j].similarity })
+	if len(hits) > candidateLimit {
+		hits = hits[:candidateLimit]
+	}
+
+	var destinationIDs, cityIDs []uint
+	for _, h := range hits {
+		if h.key.Type == models.CatalogCity {
+			cityIDs = append(cityIDs, h.key.ID)
+		} else {
+			destinationIDs = append(destinationIDs, h.key.ID)
+		}
+	}
+	loaded, err := r.load(ctx, destinationIDs, cityIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Vektor milik data yang sudah dihapus dilewati
+	results := make([]Result, 0, len(hits))
+	for _, h := range hits {
+		if result, ok := loaded[h.key]; ok {
+			result.Score = h.similarity
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// load mengambil destinasi dan kota berdasarkan ID sebagai Result
+func (r *Retriever) load(ctx context.Context, destinationIDs, cityIDs []uint) (map[sourceKey]Result, error) {
+	db := r.DB.WithContext(ctx)
+	loaded := map[sourceKey]Result{}
+
+	if len(destinationIDs) > 0 {
+		var destinations []models.Destination
+		if err := db.Preload("City").Preload("VideoContents").Find(&destinations, destinationIDs).Error; err != nil {
+			return nil, err
+		}
+		for _, destination := range destinations {
+			loaded[sourceKey{models.CatalogDestination, destination.ID}] = destinationResult(destination)
+		}
+	}
+
+	if len(cityIDs) > 0 {
+		var cities []models.City
+		if err := db.Find(&cities, cityIDs).Error; err != nil {
+			return nil, err
+		}
+		names, err := r.destinationNames(ctx, cities)
+		if err != nil {
+			return nil, err
+		}
+		for _, city := range cities {
+			loaded[sourceKey{models.CatalogCity, city.ID}] = cityResult(city, names[city.ID])
+		}
+	}
+	return loaded, nil
+}
+
+// destinationNames mengambil nama destinasi (rating tertinggi dulu) untuk setiap kota
+func (r *Retriever) destinationNames(ctx context.Context, cities []models.City) (map[uint][]string, error) {
+	names := map[uint][]string{}
+	if len(cities) == 0 {
+		return names, nil
+	}
+	cityIDs := make([]uint, len(cities))
+	for i, city := range cities {
+		cityIDs[i] = city.ID
+	}
+
+	var rows []struct {
+		CityID uint
+		Name   string
+	}
+	err := r.DB.WithContext(ctx).Model(&models.Destination{}).Select("city_id", "name").
+		Where("city_id IN ?", cityIDs).Order("average_rating DESC").Order("id").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if len(names[row.CityID]) < 10 {
+			names[row.CityID] = append(names[row.CityID], row.Name)
+		}
+	}
+	return names, nil
+}
@@ -8,44 +8,88 @@ import (
 )
 
 func InitRoutes(e *echo.Echo) {
-	e.GET("/dashboard/count-data", controllers.GetDashboardDataHandler)
-	e.GET("/dashboard/graphic", controllers.GetDashboardGraphicDataHandler)
+	dashboardGroup := e.Group("/dashboard", middlewares.AuthorizedAccess, middlewares.AdminOnly)
+	dashboardGroup.GET("/count-data", controllers.GetDashboardDataHandler)
+	dashboardGroup.GET("/graphic", controllers.GetDashboardGraphicDataHandler)
 
 	userGroup := e.Group("/user", middlewares.AuthorizedAccess)
 	userGroup.GET("", controllers.GetAllUserHandler)
 	userGroup.POST("/category", controllers.CreateUserCategoryHandler)
+	userGroup.GET("/me/favorites", controllers.GetMyFavorites)
 	userGroup.GET("/:id", controllers.GetDetailUserHandler)
-	userGroup.PUT("/change-password/:id", controllers.ChangePasswordHandler)
-	userGroup.PUT("/:id", controllers.EditUserHandler)
-	userGroup.DELETE("/:id", controllers.DeleteUser)
+	userGroup.PUT("/change-password/:id", controllers.ChangePasswordHandler, middlewares.SelfOrAdmin("id"))
+	userGroup.PUT("/:id", controllers.EditUserHandler, middlewares.SelfOrAdmin("id"))
+	userGroup.DELETE("/:id", controllers.DeleteUser, middlewares.SelfOrAdmin("id"))
 
 	e.POST("/register", controllers.RegisterHandler)
 	e.POST("/login", controllers.LoginHandler)
-	e.GET("/logout", controllers.LogoutHandler)
+	e.GET("/logout", controllers.LogoutHandler, middlewares.AllowUnverified)
+	e.POST("/logout/all", controllers.LogoutAllHandler, middlewares.AllowUnverified)
+	e.GET("/verify-email", controllers.VerifyEmailHandler)
+	e.POST("/verify-email/resend", controllers.ResendVerificationHandler, middlewares.AllowUnverified)
+	e.POST("/token/refresh", controllers.RefreshTokenHandler)
+	e.POST("/password/forgot", controllers.ForgotPasswordHandler)
+	e.POST("/password/reset", controllers.ResetPasswordHandler)
 
 	destinationGroup := e.Group("/destination", middlewares.AuthorizedAccess)
 	destinationGroup.GET("", controllers.GetAllDestinations)
 	destinationGroup.GET("/personalized", controllers.GetPersonalizedDestinationByUser)
+	destinationGroup.GET("/nearby", controllers.GetNearbyDestinations)
 	destinationGroup.GET("/:id", controllers.GetDetailDestination)
+	destinationGroup.GET("/:id/carbon", controllers.GetDestinationCarbon)
+	destinationGroup.GET("/:id/reviews", controllers.GetDestinationReviews)
+	destinationGroup.POST("/:id/reviews", controllers.CreateReview)
+	destinationGroup.POST("/:id/favorite", controllers.AddFavorite)
+	destinationGroup.DELETE("/:id/favorite", controllers.RemoveFavorite)
+
+	reviewGroup := e.Group("/review", middlewares.AuthorizedAccess)
+	reviewGroup.GET("", controllers.GetReviewsForModeration, middlewares.AdminOnly)
+	reviewGroup.PUT("/:id", controllers.UpdateReview)
+	reviewGroup.DELETE("/:id", controllers.DeleteReview)
+	reviewGroup.PUT("/:id/moderation", controllers.ModerateReview, middlewares.AdminOnly)
 
 	destinationVideoContentGroup := e.Group("/video-content")
 	destinationVideoContentGroup.GET("", controllers.GetAllVideoContents)
 	destinationVideoContentGroup.GET("/most", controllers.GetMostViewedVideoContent)
+	destinationVideoContentGroup.POST("/:id/view", controllers.RecordVideoView, middlewares.AuthorizedAccess)
 
 	e.POST("/city", controllers.CreateCity)
 	e.GET("/city", controllers.GetCity)
+	e.PUT("/city/:id", controllers.UpdateCity, middlewares.AdminOnly)
 
-	e.POST("/chat", controllers.ChatHandler)
+	chatGroup := e.Group("/chat", middlewares.AuthorizedAccess)
+	chatLimits := middlewares.ChatRateLimits()
+	chatGroup.POST("", controllers.ChatHandler, chatLimits...)
+	chatGroup.POST("/stream", controllers.ChatStreamHandler, chatLimits...)
+	chatGroup.GET("/quota", controllers.GetMyChatQuota)
+	chatGroup.GET("/quotas", controllers.GetChatQuotas, middlewares.AdminOnly)
+	chatGroup.GET("/quotas/:id", controllers.GetChatQuota, middlewares.AdminOnly)
+	chatGroup.PUT("/quotas/:id", controllers.UpdateChatQuota, middlewares.AdminOnly)
+	chatGroup.DELETE("/quotas/:id", controllers.ResetChatQuota, middlewares.AdminOnly)
+	chatGroup.GET("/conversations", controllers.GetConversations)
+	chatGroup.GET("/conversations/:id", controllers.GetConversation)
+	chatGroup.DELETE("/conversations/:id", controllers.DeleteConversation)
 
 	destinationGroup.POST("", controllers.CreateDestination, middlewares.AdminOnly)
 	destinationGroup.POST("/assets", controllers.CreateDestinationAssetsHandler, middlewares.AdminOnly)
-	destinationGroup.PUT("/assets", controllers.UpdateDestinationAssetsHandler)
+	destinationGroup.POST("/:id/images", controllers.UploadDestinationImages, middlewares.AdminOnly)
+	destinationGroup.PUT("/assets", controllers.UpdateDestinationAssetsHandler, middlewares.AdminOnly)
 	destinationGroup.PUT("/:id", controllers.UpdateDestination, middlewares.AdminOnly)
 	destinationGroup.DELETE("/:id", controllers.DeleteDestination, middlewares.AdminOnly)
 
 	routeGroup := e.Group("/route", middlewares.AuthorizedAccess)
 	routeGroup.POST("", controllers.CreateRoute)
 	routeGroup.GET("", controllers.GetRouteByUser)
+	routeGroup.POST("/optimize", controllers.OptimizeRoute)
 	routeGroup.GET("/destination", controllers.GetDestinationsByRoute)
+	routeGroup.GET("/drafts", controllers.GetRouteDrafts)
+	routeGroup.POST("/drafts/:id/confirm", controllers.ConfirmRouteDraft)
+	routeGroup.DELETE("/drafts/:id", controllers.DeleteRouteDraft)
+	routeGroup.GET("/:id", controllers.GetRouteDetail)
 	routeGroup.DELETE("/:id", controllers.DeleteRoute)
+	routeGroup.PUT("/:id/transport", controllers.UpdateRouteTransport)
+	routeGroup.POST("/:id/stops", controllers.AddRouteStop)
+	routeGroup.PUT("/:id/stops/order", controllers.ReorderRouteStops)
+	routeGroup.PUT("/:id/stops/:stopId", controllers.UpdateRouteStop)
+	routeGroup.DELETE("/:id/stops/:stopId", controllers.RemoveRouteStop)
 }
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Storage menyimpan file (gambar destinasi, foto profil) berdasarkan key berbentuk
+// path relatif seperti "uploads/ab/abcdef.png". Implementasi dipilih lewat STORAGE_DRIVER.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL mengembalikan URL publik untuk key
+	URL(key string) string
+}
+
+// FromEnv membuat Storage sesuai STORAGE_DRIVER: local (default) atau s3
+func FromEnv() (Storage, error) {
+	switch strings.ToLower(os.Getenv("STORAGE_DRIVER")) {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "assets"
+		}
+		baseURL := os.Getenv("STORAGE_BASE_URL")
+		if baseURL == "" {
+			baseURL = os.Getenv("APP_BASE") + "/assets"
+		}
+		return NewLocal(dir, baseURL), nil
+	case "s3":
+		s3 := &S3{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") != "false",
+		}
+		if s3.Endpoint == "" || s3.Bucket == "" || s3.AccessKey == "" || s3.SecretKey == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for STORAGE_DRIVER=s3")
+		}
+		if s3.Region == "" {
+			s3.Region = "us-east-1"
+		}
+		if _, err := url.Parse(s3.Endpoint); err != nil {
+			return nil, fmt.Errorf("invalid S3_ENDPOINT %q", s3.Endpoint)
+		}
+		return s3, nil
+	default:
+		return nil, fmt.Errorf("unsupported STORAGE_DRIVER %q", os.Getenv("STORAGE_DRIVER"))
+	}
+}
+
+// validKey menolak key kosong, absolut, atau yang keluar dari root lewat ".."
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// escapeKey meng-escape tiap segmen key untuk dipakai di URL
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
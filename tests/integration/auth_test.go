@@ -0,0 +1,312 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/mailer"
+	"backend/middlewares"
+	"backend/models"
+	"backend/routes"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func doJSON(e *echo.Echo, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTestServer() (*echo.Echo, *bytes.Buffer) {
+	config.TestInitDB()
+
+	outbox := &bytes.Buffer{}
+	config.Mailer = mailer.NewLogMailer(outbox, "test@tripwise.local")
+
+	e := echo.New()
+	e.IPExtractor, _ = middlewares.IPExtractor()
+	routes.InitRoutes(e)
+	return e, outbox
+}
+
+type tokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func registerUnverifiedUser(t *testing.T, e *echo.Echo, username string) tokenPair {
+	rec := doJSON(e, http.MethodPost, "/register", "", map[string]interface{}{
+		"username":   username,
+		"first_name": "John",
+		"last_name":  "Doe",
+		"email":      username + "@example.com",
+		"city":       "Jakarta",
+		"password":   "password123",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Register failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	var response struct {
+		Data tokenPair `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response.Data
+}
+
+// lastMailToken mengambil token dari link terakhir di outbox
+func lastMailToken(t *testing.T, outbox *bytes.Buffer, path string) string {
+	matches := regexp.MustCompile(regexp.QuoteMeta(path)+`\?token=([0-9a-f]+)`).FindAllStringSubmatch(outbox.String(), -1)
+	if len(matches) == 0 {
+		t.Fatalf("No %s link in outbox: %q", path, outbox.String())
+	}
+	return matches[len(matches)-1][1]
+}
+
+func login(t *testing.T, e *echo.Echo, username string) tokenPair {
+	rec := doJSON(e, http.MethodPost, "/login", "", map[string]string{"username": username, "password": "password123"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Login failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	var response struct {
+		Data tokenPair `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response.Data
+}
+
+// registerUser mendaftarkan user, memverifikasi email-nya, lalu login
+func registerUser(t *testing.T, e *echo.Echo, outbox *bytes.Buffer, username string) (string, string) {
+	registerUnverifiedUser(t, e, username)
+
+	rec := doJSON(e, http.MethodGet, "/verify-email?token="+lastMailToken(t, outbox, "/verify-email"), "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Verify email failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	pair := login(t, e, username)
+	return pair.Token, pair.RefreshToken
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	e, outbox := newTestServer()
+
+	_, refreshToken := registerUser(t, e, outbox, "refreshuser")
+
+	rec := doJSON(e, http.MethodPost, "/token/refresh", "", map[string]string{"refresh_token": refreshToken})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data tokenPair `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	assert.NotEmpty(t, response.Data.Token)
+	assert.NotEqual(t, refreshToken, response.Data.RefreshToken)
+
+	// Reusing a rotated refresh token revokes the whole session
+	rec = doJSON(e, http.MethodPost, "/token/refresh", "", map[string]string{"refresh_token": refreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, "/token/refresh", "", map[string]string{"refresh_token": response.Data.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doJSON(e, http.MethodGet, "/city", response.Data.Token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = doJSON(e, http.MethodGet, "/route", response.Data.Token, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	e, outbox := newTestServer()
+
+	token, refreshToken := registerUser(t, e, outbox, "logoutuser")
+
+	rec := doJSON(e, http.MethodGet, "/logout", token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doJSON(e, http.MethodGet, "/logout", token, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, "/token/refresh", "", map[string]string{"refresh_token": refreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestLogoutAllDevices(t *testing.T) {
+	e, outbox := newTestServer()
+
+	firstToken, _ := registerUser(t, e, outbox, "alldevices")
+	second := login(t, e, "alldevices")
+
+	rec := doJSON(e, http.MethodPost, "/logout/all", second.Token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doJSON(e, http.MethodGet, "/logout", firstToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = doJSON(e, http.MethodGet, "/logout", second.Token, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuthorizedAccessRejectsUnexpectedAlgorithm(t *testing.T) {
+	e, _ := newTestServer()
+
+	claims := &helper.JWTClaims{
+		Username:  "admin",
+		UserID:    1,
+		Role:      "admin",
+		SessionID: "forged",
+		Verified:  true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	rec := doJSON(e, http.MethodGet, "/route", forged, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAdminOnlyForbidsUsers(t *testing.T) {
+	e, outbox := newTestServer()
+
+	token, _ := registerUser(t, e, outbox, "notanadmin")
+
+	rec := doJSON(e, http.MethodDelete, "/destination/1", token, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doJSON(e, http.MethodPut, "/destination/assets", token, map[string]interface{}{})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doJSON(e, http.MethodGet, "/route", token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestUserOwnershipPolicy(t *testing.T) {
+	e, outbox := newTestServer()
+
+	aliceToken, _ := registerUser(t, e, outbox, "alice")
+	registerUser(t, e, outbox, "bob")
+
+	var alice, bob models.User
+	config.DB.First(&alice, "username = ?", "alice")
+	config.DB.First(&bob, "username = ?", "bob")
+
+	rec := doJSON(e, http.MethodDelete, fmt.Sprintf("/user/%d", bob.ID), aliceToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/user/change-password/%d", bob.ID), aliceToken, map[string]string{
+		"currentPassword": "password123",
+		"newPassword":     "hacked123",
+	})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	form := url.Values{
+		"username":     {"alice"},
+		"first_name":   {"Alice"},
+		"last_name":    {"Doe"},
+		"email":        {"alice@example.com"},
+		"city":         {"Jakarta"},
+		"phone_number": {"0800"},
+		"gender":       {"F"},
+		"role":         {"admin"},
+	}
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/user/%d", alice.ID), strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+aliceToken)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	config.DB.First(&alice, alice.ID)
+	assert.Equal(t, "user", alice.Role)
+
+	// Email yang salah ketik ditolak sebelum status verifikasi direset
+	form.Del("role")
+	form.Set("email", "alice@")
+	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/user/%d", alice.ID), strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+aliceToken)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	config.DB.First(&alice, alice.ID)
+	assert.Equal(t, "alice@example.com", alice.Email)
+	assert.NotNil(t, alice.EmailVerifiedAt)
+
+	rec = doJSON(e, http.MethodDelete, fmt.Sprintf("/user/%d", alice.ID), aliceToken, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEmailVerificationLimitsAccess(t *testing.T) {
+	e, outbox := newTestServer()
+
+	rec := doJSON(e, http.MethodPost, "/register", "", map[string]interface{}{
+		"username":   "wannabeadmin",
+		"first_name": "Eve",
+		"last_name":  "Doe",
+		"email":      "wannabeadmin@example.com",
+		"city":       "Jakarta",
+		"password":   "password123",
+		"role":       "admin",
+	})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	pending := registerUnverifiedUser(t, e, "unverified")
+
+	rec = doJSON(e, http.MethodGet, "/route", pending.Token, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	outbox.Reset()
+	rec = doJSON(e, http.MethodPost, "/verify-email/resend", pending.Token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doJSON(e, http.MethodGet, "/verify-email?token=bogus", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(e, http.MethodGet, "/verify-email?token="+lastMailToken(t, outbox, "/verify-email"), "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The refreshed token carries the verified status
+	rec = doJSON(e, http.MethodPost, "/token/refresh", "", map[string]string{"refresh_token": pending.RefreshToken})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var refreshed struct {
+		Data tokenPair `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &refreshed)
+
+	rec = doJSON(e, http.MethodGet, "/route", refreshed.Data.Token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,109 @@
+package controllers_test
+
+import (
+	"backend/emissions"
+	"backend/models"
+	"backend/response"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCarbonFootprint(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "ecotraveller")
+
+	_, _, ids := seedLine(2)
+
+	rec := doJSON(e, http.MethodPost, "/route", token, map[string]interface{}{
+		"originCityName":      "Origin",
+		"destinationCityName": "End",
+		"destinations":        ids,
+		"transportMode":       "car",
+		"travellers":          6,
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var route models.Route
+	json.Unmarshal(rec.Body.Bytes(), &route)
+	// Enam orang butuh dua mobil
+	assert.InDelta(t, 0.170*route.Distance*2, route.CarbonKg, 0.01)
+	assert.Equal(t, 6, route.Travellers)
+
+	rec = doJSON(e, http.MethodGet, "/route", token, nil)
+	var listed struct {
+		Data []response.RouteResponse `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &listed)
+	if assert.Len(t, listed.Data, 1) {
+		modes := []string{}
+		for _, alternative := range listed.Data[0].CarbonAlternatives {
+			modes = append(modes, alternative.Mode)
+		}
+		// ~33 km: sepeda masih masuk akal, jalan kaki dan pesawat tidak; motor dan bus
+		// untuk enam orang justru lebih boros dari dua mobil
+		assert.Equal(t, []string{"bicycle", "train"}, modes)
+		assert.Equal(t, route.CarbonKg, listed.Data[0].CarbonKg)
+	}
+
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/route/%d/transport", route.ID), token, map[string]interface{}{"transportMode": "train"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	detail := parseItinerary(t, rec.Body.Bytes())
+	assert.Equal(t, "train", detail.TransportMode)
+	assert.InDelta(t, 0.035*route.Distance*6, detail.CarbonKg, 0.01)
+	assert.Len(t, detail.CarbonAlternatives, 1)
+
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/route/%d/transport", route.ID), token, map[string]interface{}{"transportMode": "rocket"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doJSON(e, http.MethodPost, "/route", token, map[string]interface{}{
+		"originCityName": "Origin",
+		"destinations":   ids,
+		"transportMode":  "rocket",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Menghapus stop mengubah jarak sehingga emisinya ikut dihitung ulang
+	rec = doJSON(e, http.MethodDelete, fmt.Sprintf("/route/%d/stops/%d", route.ID, detail.Days[0].Stops[1].ID), token, nil)
+	detail = parseItinerary(t, rec.Body.Bytes())
+	assert.InDelta(t, 0.035*detail.Distance*6, detail.CarbonKg, 0.01)
+}
+
+func TestDestinationCarbonEstimate(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "daytripper")
+
+	_, _, ids := seedLine(1)
+
+	rec := doJSON(e, http.MethodGet, fmt.Sprintf("/destination/%d/carbon?origin=Origin&mode=bus&travellers=2", ids[0]), token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var estimate response.CarbonEstimateResponse
+	json.Unmarshal(rec.Body.Bytes(), &estimate)
+	assert.InDelta(t, 11.05, estimate.DistanceKm, 0.1)
+	assert.InDelta(t, 0.097*estimate.DistanceKm*2, estimate.CarbonKg, 0.01)
+	if assert.NotEmpty(t, estimate.Alternatives) {
+		assert.Equal(t, "bicycle", estimate.Alternatives[0].Mode)
+		assert.Equal(t, estimate.CarbonKg, estimate.Alternatives[0].SavedKg)
+	}
+
+	rec = doJSON(e, http.MethodGet, fmt.Sprintf("/destination/%d/carbon?origin=Origin&mode=teleport", ids[0]), token, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestEmissionFactorsFromEnv(t *testing.T) {
+	t.Setenv("EMISSION_FACTOR_CAR", "0.2")
+
+	calculator, err := emissions.FromEnv()
+	if err != nil {
+		t.Fatalf("Failed to configure emissions: %v", err)
+	}
+	carbon, _ := calculator.Estimate(emissions.Car, 100, 1)
+	assert.Equal(t, 20.0, carbon)
+
+	t.Setenv("EMISSION_FACTOR_CAR", "-1")
+	_, err = emissions.FromEnv()
+	assert.Error(t, err)
+}
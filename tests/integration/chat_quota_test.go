@@ -0,0 +1,195 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/llm"
+	"backend/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type chatQuotaReply struct {
+	UserID          uint   `json:"user_id"`
+	DailyTokenLimit int    `json:"daily_token_limit"`
+	Custom          bool   `json:"custom"`
+	UsedTokens      int    `json:"used_tokens"`
+	RemainingTokens *int   `json:"remaining_tokens"`
+	Requests        int    `json:"requests"`
+	Day             string `json:"day"`
+}
+
+func getChatQuota(t *testing.T, e *echo.Echo, path, token string) chatQuotaReply {
+	rec := doJSON(e, http.MethodGet, path, token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data chatQuotaReply `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	return body.Data
+}
+
+func TestChatRateLimits(t *testing.T) {
+	t.Setenv("CHAT_USER_RATE_LIMIT", "2")
+	t.Setenv("CHAT_IP_RATE_LIMIT", "4")
+	e, outbox := newTestServer()
+	useLLM(t, llm.NewMock())
+	token, _ := registerUser(t, e, outbox, "fastchatter")
+	otherToken, _ := registerUser(t, e, outbox, "slowchatter")
+
+	message := map[string]string{"message": "Halo"}
+	assert.Equal(t, http.StatusUnauthorized, doJSON(e, http.MethodPost, "/chat", "", message).Code)
+
+	// Batas per user berlaku bersama untuk /chat dan /chat/stream
+	assert.Equal(t, http.StatusOK, doJSON(e, http.MethodPost, "/chat", token, message).Code)
+	assert.Equal(t, http.StatusOK, doJSON(e, http.MethodPost, "/chat/stream", token, message).Code)
+	rec := doJSON(e, http.MethodPost, "/chat", token, message)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	assert.NoError(t, err)
+	assert.True(t, retryAfter >= 1 && retryAfter <= 30)
+
+	// User lain masih boleh, sampai batas per IP habis (request yang ditolak ikut dihitung)
+	assert.Equal(t, http.StatusOK, doJSON(e, http.MethodPost, "/chat", otherToken, message).Code)
+	rec = doJSON(e, http.MethodPost, "/chat", otherToken, message)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// X-Forwarded-For dari client tidak dipercaya tanpa TRUSTED_PROXIES
+	rec = chatFrom(e, otherToken, "203.0.113.7")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// Endpoint chat lain tidak dibatasi
+	assert.Equal(t, http.StatusOK, doJSON(e, http.MethodGet, "/chat/conversations", token, nil).Code)
+}
+
+func TestChatDailyTokenQuota(t *testing.T) {
+	e, outbox := newTestServer()
+	mock := llm.NewMock()
+	useLLM(t, mock)
+	adminToken := registerAdmin(t, e, outbox, "quotaadmin")
+	token, _ := registerUser(t, e, outbox, "quotauser")
+	var user models.User
+	config.DB.Where("username = ?", "quotauser").First(&user)
+	quotaPath := fmt.Sprintf("/chat/quotas/%d", user.ID)
+
+	quota := getChatQuota(t, e, "/chat/quota", token)
+	assert.Equal(t, 100000, quota.DailyTokenLimit)
+	assert.False(t, quota.Custom)
+	assert.Zero(t, quota.UsedTokens)
+
+	assert.Equal(t, http.StatusForbidden, doJSON(e, http.MethodPut, quotaPath, token, map[string]int{"daily_token_limit": 1}).Code)
+	rec := doJSON(e, http.MethodPut, quotaPath, adminToken, map[string]int{"daily_token_limit": -1})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doJSON(e, http.MethodPut, "/chat/quotas/9999", adminToken, map[string]int{"daily_token_limit": 1})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doJSON(e, http.MethodPut, quotaPath, adminToken, map[string]int{"daily_token_limit": 1})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Kuota dicek sebelum pesan dikirim, jadi pesan pertama masih dijawab
+	rec = doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Halo"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	calls := len(mock.Calls())
+
+	quota = getChatQuota(t, e, quotaPath, adminToken)
+	assert.True(t, quota.Custom)
+	assert.Equal(t, 1, quota.Requests)
+	assert.Greater(t, quota.UsedTokens, 1)
+	if assert.NotNil(t, quota.RemainingTokens) {
+		assert.Zero(t, *quota.RemainingTokens)
+	}
+
+	for _, path := range []string{"/chat", "/chat/stream"} {
+		rec = doJSON(e, http.MethodPost, path, token, map[string]string{"message": "Halo lagi"})
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Daily chat token quota exceeded")
+		retryAfter, _ := strconv.Atoi(rec.Header().Get("Retry-After"))
+		assert.True(t, retryAfter >= 1 && retryAfter <= 86400)
+	}
+	assert.Len(t, mock.Calls(), calls)
+
+	rec = doJSON(e, http.MethodGet, "/chat/quotas", adminToken, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data []chatQuotaReply `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	if assert.Len(t, list.Data, 1) {
+		assert.Equal(t, user.ID, list.Data[0].UserID)
+		assert.Equal(t, quota.UsedTokens, list.Data[0].UsedTokens)
+	}
+	assert.Equal(t, http.StatusForbidden, doJSON(e, http.MethodGet, "/chat/quotas", token, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, doJSON(e, http.MethodGet, "/chat/quotas?date=kemarin", adminToken, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, doJSON(e, http.MethodGet, "/chat/quotas?cursor=", adminToken, nil).Code)
+	rec = doJSON(e, http.MethodGet, "/chat/quotas?date=2000-01-01", adminToken, nil)
+	assert.Contains(t, rec.Body.String(), `"data":[]`)
+
+	// 0 berarti tanpa batas
+	doJSON(e, http.MethodPut, quotaPath, adminToken, map[string]int{"daily_token_limit": 0})
+	assert.Nil(t, getChatQuota(t, e, "/chat/quota", token).RemainingTokens)
+	rec = doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Halo lagi"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Pesan kedua di hari yang sama ditambahkan ke baris pemakaian yang sudah ada
+	second := getChatQuota(t, e, "/chat/quota", token)
+	assert.Equal(t, 2, second.Requests)
+	assert.Greater(t, second.UsedTokens, quota.UsedTokens)
+	var rows int64
+	config.DB.Model(&models.ChatUsage{}).Where("user_id = ?", user.ID).Count(&rows)
+	assert.EqualValues(t, 1, rows)
+
+	// Reset mengembalikan kuota default tanpa menghapus pemakaian hari ini
+	rec = doJSON(e, http.MethodDelete, quotaPath, adminToken, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	quota = getChatQuota(t, e, "/chat/quota", token)
+	assert.False(t, quota.Custom)
+	assert.Equal(t, 100000, quota.DailyTokenLimit)
+	assert.Equal(t, 2, quota.Requests)
+
+	// Token dari pesan yang gagal tetap dihitung
+	mock.RespondWithTools = func(messages []llm.Message, tools []llm.Tool) (llm.Reply, error) {
+		if len(toolResults(messages)) > 0 {
+			return llm.Reply{}, llm.ErrUnavailable
+		}
+		return llm.Reply{ToolCalls: []llm.ToolCall{toolCall("1", "list_cities", `{}`)}}, nil
+	}
+	rec = doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Kota apa saja?"})
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	failed := getChatQuota(t, e, "/chat/quota", token)
+	assert.Greater(t, failed.UsedTokens, quota.UsedTokens)
+	assert.Equal(t, 3, failed.Requests)
+}
+
+// chatFrom mengirim pesan chat dengan header X-Forwarded-For
+func chatFrom(e *echo.Echo, token, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(`{"message":"Halo"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestChatRateLimitTrustedProxies(t *testing.T) {
+	t.Setenv("CHAT_USER_RATE_LIMIT", "0")
+	t.Setenv("CHAT_IP_RATE_LIMIT", "1")
+	// httptest memakai 192.0.2.1 sebagai alamat koneksi
+	t.Setenv("TRUSTED_PROXIES", "192.0.2.0/24")
+	e, outbox := newTestServer()
+	useLLM(t, llm.NewMock())
+	token, _ := registerUser(t, e, outbox, "proxiedchatter")
+
+	// Di belakang proxy tepercaya, batas per IP mengikuti client asli
+	assert.Equal(t, http.StatusOK, chatFrom(e, token, "198.51.100.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, chatFrom(e, token, "198.51.100.1").Code)
+	assert.Equal(t, http.StatusOK, chatFrom(e, token, "198.51.100.2").Code)
+}
@@ -0,0 +1,335 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/llm"
+	"backend/models"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type geminiTurn struct {
+	Role  string `json:"role"`
+	Parts []struct {
+		Text string `json:"text"`
+	} `json:"parts"`
+}
+
+// fakeGemini mencatat contents yang dikirim dan membalas dengan nomor giliran
+type fakeGemini struct {
+	mu       sync.Mutex
+	requests [][]geminiTurn
+	fail     bool
+	// stall membuat stream berhenti setelah potongan pertama sampai client memutus koneksi
+	stall   bool
+	aborted chan struct{}
+}
+
+func (f *fakeGemini) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Contents []geminiTurn `json:"contents"`
+	}
+	json.NewDecoder(r.Body).Decode(&payload)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail {
+		http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+		return
+	}
+	f.requests = append(f.requests, payload.Contents)
+
+	reply := fmt.Sprintf("reply %d", len(f.requests))
+	if strings.HasSuffix(r.URL.Path, "/stream") {
+		f.stream(w, r, reply)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"candidates": []map[string]interface{}{
+			{"content": map[string]interface{}{"role": "model", "parts": []map[string]string{{"text": reply}}}},
+		},
+	})
+}
+
+// stream mengirim reply per kata sebagai event SSE. Seperti Gemini, setiap potongan
+// membawa usage sejauh ini.
+func (f *fakeGemini) stream(w http.ResponseWriter, r *http.Request, reply string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	words := strings.SplitAfter(reply, " ")
+	for i, word := range words {
+		chunk := map[string]interface{}{
+			"candidates": []map[string]interface{}{
+				{"content": map[string]interface{}{"role": "model", "parts": []map[string]string{{"text": word}}}},
+			},
+		}
+		chunk["usageMetadata"] = map[string]int{"promptTokenCount": 7, "candidatesTokenCount": i + 1, "totalTokenCount": 8 + i}
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\r\n\r\n", data)
+		w.(http.Flusher).Flush()
+
+		if f.stall {
+			f.mu.Unlock()
+			<-r.Context().Done()
+			f.mu.Lock()
+			close(f.aborted)
+			return
+		}
+	}
+}
+
+func (f *fakeGemini) last() []geminiTurn {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1]
+}
+
+func (f *fakeGemini) setFail(fail bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fail = fail
+}
+
+func useFakeGemini(t *testing.T) *fakeGemini {
+	fake := &fakeGemini{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	useLLM(t, &llm.Gemini{
+		URL:       server.URL,
+		StreamURL: server.URL + "/stream",
+		APIKey:    "test-key",
+		Retry:     llm.Retry{Timeout: 5 * time.Second, MaxRetries: 1},
+	})
+	return fake
+}
+
+type chatReply struct {
+	Data           string `json:"data"`
+	ConversationID uint   `json:"conversation_id"`
+}
+
+func sendChat(e *echo.Echo, token string, body map[string]interface{}) (int, chatReply) {
+	rec := doJSON(e, http.MethodPost, "/chat", token, body)
+	var reply chatReply
+	json.Unmarshal(rec.Body.Bytes(), &reply)
+	return rec.Code, reply
+}
+
+func TestChatConversationHistory(t *testing.T) {
+	e, outbox := newTestServer()
+	gemini := useFakeGemini(t)
+	token, _ := registerUser(t, e, outbox, "chatter")
+	otherToken, _ := registerUser(t, e, outbox, "otherchatter")
+
+	rec := doJSON(e, http.MethodPost, "/chat", "", map[string]string{"message": "Where should I go?"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	code, first := sendChat(e, token, map[string]interface{}{"message": "Rekomendasi pantai di Bali?"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "reply 1", first.Data)
+	assert.NotZero(t, first.ConversationID)
+	assert.Len(t, gemini.last(), 1)
+
+	// Pertanyaan lanjutan mengirim giliran sebelumnya sebagai konteks
+	code, second := sendChat(e, token, map[string]interface{}{"message": "Yang paling sepi?", "conversation_id": first.ConversationID})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, first.ConversationID, second.ConversationID)
+	contents := gemini.last()
+	if assert.Len(t, contents, 3) {
+		assert.Equal(t, []string{"user", "model", "user"}, []string{contents[0].Role, contents[1].Role, contents[2].Role})
+		assert.Equal(t, "Rekomendasi pantai di Bali?", contents[0].Parts[0].Text)
+		assert.Equal(t, "reply 1", contents[1].Parts[0].Text)
+		assert.Equal(t, "Yang paling sepi?", contents[2].Parts[0].Text)
+	}
+
+	// Riwayat dibatasi CHAT_HISTORY_LIMIT pesan terakhir
+	t.Setenv("CHAT_HISTORY_LIMIT", "2")
+	sendChat(e, token, map[string]interface{}{"message": "Berapa harga tiketnya?", "conversation_id": first.ConversationID})
+	contents = gemini.last()
+	if assert.Len(t, contents, 3) {
+		assert.Equal(t, "Yang paling sepi?", contents[0].Parts[0].Text)
+	}
+
+	// Percakapan user lain tidak bisa dilanjutkan, dibaca, atau dihapus
+	code, _ = sendChat(e, otherToken, map[string]interface{}{"message": "Hi", "conversation_id": first.ConversationID})
+	assert.Equal(t, http.StatusNotFound, code)
+	path := fmt.Sprintf("/chat/conversations/%d", first.ConversationID)
+	assert.Equal(t, http.StatusNotFound, doJSON(e, http.MethodGet, path, otherToken, nil).Code)
+	assert.Equal(t, http.StatusNotFound, doJSON(e, http.MethodDelete, path, otherToken, nil).Code)
+
+	// Gemini gagal: tidak ada pesan yang tersimpan
+	gemini.setFail(true)
+	code, _ = sendChat(e, token, map[string]interface{}{"message": "Masih di sana?", "conversation_id": first.ConversationID})
+	assert.Equal(t, http.StatusBadGateway, code)
+	gemini.setFail(false)
+
+	var detail struct {
+		Data struct {
+			Title    string `json:"title"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		} `json:"data"`
+	}
+	rec = doJSON(e, http.MethodGet, path, token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &detail)
+	assert.Equal(t, "Rekomendasi pantai di Bali?", detail.Data.Title)
+	if assert.Len(t, detail.Data.Messages, 6) {
+		assert.Equal(t, "user", detail.Data.Messages[0].Role)
+		assert.Equal(t, "reply 3", detail.Data.Messages[5].Content)
+	}
+
+	sendChat(e, token, map[string]interface{}{"message": "Kuliner di Bandung?"})
+	var list struct {
+		Conversations []struct {
+			ID    uint   `json:"id"`
+			Title string `json:"title"`
+		} `json:"conversations"`
+	}
+	rec = doJSON(e, http.MethodGet, "/chat/conversations", token, nil)
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	assert.Len(t, list.Conversations, 2)
+	rec = doJSON(e, http.MethodGet, "/chat/conversations", otherToken, nil)
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	assert.Empty(t, list.Conversations)
+	rec = doJSON(e, http.MethodGet, "/chat/conversations?cursor=", token, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(e, http.MethodDelete, path, token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusNotFound, doJSON(e, http.MethodGet, path, token, nil).Code)
+	var remaining int64
+	config.DB.Model(&models.Message{}).Where("conversation_id = ?", first.ConversationID).Count(&remaining)
+	assert.Zero(t, remaining)
+}
+
+type sseEvent struct {
+	Name string
+	Data string
+}
+
+// readEvents membaca event SSE dari body sampai stream berakhir
+func readEvents(body io.Reader) []sseEvent {
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.Name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.Data = strings.TrimPrefix(line, "data: ")
+		case line == "" && current.Name != "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	return events
+}
+
+func TestChatStream(t *testing.T) {
+	e, outbox := newTestServer()
+	gemini := useFakeGemini(t)
+	token, _ := registerUser(t, e, outbox, "streamer")
+
+	rec := doJSON(e, http.MethodPost, "/chat/stream", token, map[string]string{"message": "Pantai terbaik di Lombok?"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+	events := readEvents(rec.Body)
+	if !assert.Len(t, events, 3, rec.Body.String()) {
+		return
+	}
+	assert.Equal(t, "token", events[0].Name)
+	assert.JSONEq(t, `{"text":"reply "}`, events[0].Data)
+	assert.JSONEq(t, `{"text":"1"}`, events[1].Data)
+	assert.Equal(t, "done", events[2].Name)
+
+	var done struct {
+		ConversationID uint      `json:"conversation_id"`
+		Usage          llm.Usage `json:"usage"`
+	}
+	json.Unmarshal([]byte(events[2].Data), &done)
+	assert.NotZero(t, done.ConversationID)
+	assert.Equal(t, llm.Usage{PromptTokens: 7, CompletionTokens: 2, TotalTokens: 9}, done.Usage)
+
+	// Jawaban lengkap tersimpan dan menjadi konteks pesan berikutnya
+	var messages []models.Message
+	config.DB.Where("conversation_id = ?", done.ConversationID).Order("id").Find(&messages)
+	if assert.Len(t, messages, 2) {
+		assert.Equal(t, "reply 1", messages[1].Content)
+	}
+	code, _ := sendChat(e, token, map[string]interface{}{"message": "Yang dekat bandara?", "conversation_id": done.ConversationID})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, gemini.last(), 3)
+
+	// Gemini gagal sebelum streaming dimulai: response JSON biasa
+	gemini.setFail(true)
+	rec = doJSON(e, http.MethodPost, "/chat/stream", token, map[string]string{"message": "Halo?"})
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+}
+
+func TestChatStreamClientDisconnect(t *testing.T) {
+	e, outbox := newTestServer()
+	gemini := useFakeGemini(t)
+	gemini.stall = true
+	gemini.aborted = make(chan struct{})
+	token, _ := registerUser(t, e, outbox, "leaver")
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/chat/stream", strings.NewReader(`{"message":"Ceritakan tentang Bromo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	// Tunggu token pertama lalu putuskan koneksi di tengah jawaban
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: token\n", line)
+	cancel()
+
+	// Pembatalan diteruskan ke Gemini dan jawaban yang terpotong tidak disimpan
+	select {
+	case <-gemini.aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("upstream request was not cancelled")
+	}
+	assert.Never(t, func() bool {
+		var count int64
+		config.DB.Model(&models.Message{}).Count(&count)
+		return count > 0
+	}, 200*time.Millisecond, 20*time.Millisecond)
+	var conversations int64
+	config.DB.Model(&models.Conversation{}).Count(&conversations)
+	assert.Zero(t, conversations)
+
+	// Token yang sudah dipakai sebelum koneksi terputus tetap dihitung ke kuota
+	assert.Eventually(t, func() bool {
+		var usage models.ChatUsage
+		config.DB.Limit(1).Find(&usage)
+		return usage.TotalTokens == 8
+	}, 2*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,236 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/llm"
+	"backend/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// toolResults mengambil hasil tool yang sudah dikirim ke model
+func toolResults(messages []llm.Message) []llm.Message {
+	var results []llm.Message
+	for _, message := range messages {
+		if message.Role == llm.RoleTool {
+			results = append(results, message)
+		}
+	}
+	return results
+}
+
+func toolCall(id, name, arguments string) llm.ToolCall {
+	return llm.ToolCall{ID: id, Name: name, Arguments: json.RawMessage(arguments)}
+}
+
+func placeCity(city *models.City, lat, lon float64) {
+	city.Latitude, city.Longitude = &lat, &lon
+	config.DB.Save(city)
+}
+
+type routeDraftReply struct {
+	ID            uint   `json:"id"`
+	Status        string `json:"status"`
+	RouteID       *uint  `json:"routeID"`
+	TransportMode string `json:"transportMode"`
+	Travellers    int    `json:"travellers"`
+	Stops         []struct {
+		Sequence      int    `json:"sequence"`
+		DestinationID uint   `json:"destination_id"`
+		Name          string `json:"name"`
+	} `json:"stops"`
+}
+
+func TestChatToolsDraftRoute(t *testing.T) {
+	e, outbox := newTestServer()
+	catalog := seedCatalog(t)
+	placeCity(&catalog.bali, -8.65, 115.2167)
+	placeCity(&catalog.bandung, -6.9175, 107.6191)
+	mock := llm.NewMock()
+	useLLM(t, mock)
+	token, _ := registerUser(t, e, outbox, "tripplanner")
+	otherToken, _ := registerUser(t, e, outbox, "otherplanner")
+
+	var offered []string
+	var results []llm.Message
+	mock.RespondWithTools = func(messages []llm.Message, tools []llm.Tool) (llm.Reply, error) {
+		offered = offered[:0]
+		for _, tool := range tools {
+			offered = append(offered, tool.Name)
+		}
+		results = toolResults(messages)
+		switch len(results) {
+		case 0:
+			return llm.Reply{Text: "Sebentar, saya cek dulu. ", ToolCalls: []llm.ToolCall{
+				toolCall("1", "list_cities", `{}`),
+				toolCall("2", "calculate_distance", `{"origin_city":"bali","destination_city":"Bandung"}`),
+			}}, nil
+		case 2:
+			return llm.Reply{ToolCalls: []llm.ToolCall{toolCall("3", "search_destinations", `{"query":"pantai di bali"}`)}}, nil
+		case 3:
+			return llm.Reply{ToolCalls: []llm.ToolCall{
+				toolCall("4", "draft_route", `{"originCityName":"Atlantis","destinations":[1]}`),
+				toolCall("5", "draft_route", fmt.Sprintf(`{"originCityName":"Bali","destinations":[%d,%d],"transportMode":"car","travellers":2}`, catalog.ubud.ID, catalog.kuta.ID)),
+			}}, nil
+		default:
+			return llm.Reply{Text: fmt.Sprintf("Draft rute ke Pantai Kuta [D%d] siap, silakan konfirmasi.", catalog.kuta.ID)}, nil
+		}
+	}
+
+	rec := doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Buatkan rute ke pantai di Bali untuk 2 orang"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var reply struct {
+		citedReply
+		RouteDrafts []routeDraftReply `json:"route_drafts"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &reply)
+
+	assert.Equal(t, []string{"search_destinations", "list_cities", "calculate_distance", "draft_route"}, offered)
+	assert.Contains(t, systemPrompt(mock.Calls()[0]), "draft_route")
+	if assert.Len(t, results, 5) {
+		assert.Contains(t, results[0].Content, `"name":"Bandung"`)
+		assert.Equal(t, "calculate_distance", results[1].ToolName)
+		assert.Contains(t, results[1].Content, `"origin_city":"Bali"`)
+		assert.Contains(t, results[1].Content, `"distance_km":858.87`)
+		assert.Contains(t, results[2].Content, fmt.Sprintf(`"marker":"D%d"`, catalog.kuta.ID))
+		// Argumen yang salah dilaporkan ke model tanpa menggagalkan chat
+		assert.JSONEq(t, `{"error":"Origin City not found"}`, results[3].Content)
+		assert.Contains(t, results[4].Content, `"draft_id"`)
+	}
+
+	// Teks dari semua putaran digabung; sumber dari tool ikut menjadi citation
+	assert.Equal(t, fmt.Sprintf("Sebentar, saya cek dulu. Draft rute ke Pantai Kuta [D%d] siap, silakan konfirmasi.", catalog.kuta.ID), reply.Data)
+	if assert.Len(t, reply.Citations, 1) {
+		assert.Equal(t, catalog.kuta.ID, reply.Citations[0].ID)
+	}
+
+	// Draft belum menjadi rute sampai dikonfirmasi
+	if !assert.Len(t, reply.RouteDrafts, 1) {
+		return
+	}
+	draft := reply.RouteDrafts[0]
+	assert.Equal(t, models.RouteDraftPending, draft.Status)
+	assert.Equal(t, 2, draft.Travellers)
+	assert.Len(t, draft.Stops, 2)
+	var routes int64
+	config.DB.Model(&models.Route{}).Count(&routes)
+	assert.Zero(t, routes)
+
+	rec = doJSON(e, http.MethodGet, "/route/drafts", token, nil)
+	assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"id":%d`, draft.ID))
+
+	confirmPath := fmt.Sprintf("/route/drafts/%d/confirm", draft.ID)
+	rec = doJSON(e, http.MethodPost, confirmPath, otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, confirmPath, token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var route models.Route
+	json.Unmarshal(rec.Body.Bytes(), &route)
+	assert.Equal(t, "Bali", route.OriginCityName)
+	assert.Equal(t, 2, route.Travellers)
+	assert.True(t, route.OpenEnded)
+	if assert.Len(t, route.Destinations, 2) {
+		assert.Equal(t, draft.Stops[0].DestinationID, route.Destinations[0].DestinationID)
+	}
+
+	rec = doJSON(e, http.MethodPost, confirmPath, token, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	config.DB.Model(&models.Route{}).Count(&routes)
+	assert.EqualValues(t, 1, routes)
+
+	rec = doJSON(e, http.MethodGet, "/route/drafts", token, nil)
+	assert.Contains(t, rec.Body.String(), `"data":[]`)
+
+	// Draft tetap terlihat di percakapan bersama rute yang dibuat darinya
+	var detail struct {
+		Data struct {
+			Messages []struct {
+				RouteDrafts []routeDraftReply `json:"route_drafts"`
+			} `json:"messages"`
+		} `json:"data"`
+	}
+	rec = doJSON(e, http.MethodGet, fmt.Sprintf("/chat/conversations/%d", reply.ConversationID), token, nil)
+	json.Unmarshal(rec.Body.Bytes(), &detail)
+	if assert.Len(t, detail.Data.Messages, 2) && assert.Len(t, detail.Data.Messages[1].RouteDrafts, 1) {
+		confirmed := detail.Data.Messages[1].RouteDrafts[0]
+		assert.Equal(t, models.RouteDraftConfirmed, confirmed.Status)
+		if assert.NotNil(t, confirmed.RouteID) {
+			assert.Equal(t, route.ID, *confirmed.RouteID)
+		}
+	}
+}
+
+func TestChatToolsStreamAndLimits(t *testing.T) {
+	e, outbox := newTestServer()
+	mock := llm.NewMock()
+	useLLM(t, mock)
+	token, _ := registerUser(t, e, outbox, "toolstreamer")
+
+	// Model yang terus meminta tool dihentikan setelah maxToolRounds putaran
+	mock.RespondWithTools = func(messages []llm.Message, tools []llm.Tool) (llm.Reply, error) {
+		if len(tools) == 0 {
+			return llm.Reply{Text: "Ini daftar kotanya."}, nil
+		}
+		return llm.Reply{ToolCalls: []llm.ToolCall{toolCall("1", "list_cities", `{}`)}}, nil
+	}
+	rec := doJSON(e, http.MethodPost, "/chat/stream", token, map[string]string{"message": "Kota apa saja?"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	events := readEvents(rec.Body)
+	var tools, tokens []string
+	for _, event := range events {
+		switch event.Name {
+		case "tool":
+			tools = append(tools, event.Data)
+		case "token":
+			tokens = append(tokens, event.Data)
+		}
+	}
+	assert.Len(t, tools, 5)
+	assert.JSONEq(t, `{"name":"list_cities"}`, tools[0])
+	assert.NotEmpty(t, tokens)
+	if assert.NotEmpty(t, events) {
+		done := events[len(events)-1]
+		assert.Equal(t, "done", done.Name)
+		assert.Contains(t, done.Data, `"route_drafts":[]`)
+	}
+	assert.Len(t, mock.Calls(), 6)
+
+	// Draft dari turn yang gagal dihapus lagi
+	catalog := seedCatalog(t)
+	placeCity(&catalog.bali, -8.65, 115.2167)
+	var drafted string
+	mock.RespondWithTools = func(messages []llm.Message, tools []llm.Tool) (llm.Reply, error) {
+		if results := toolResults(messages); len(results) > 0 {
+			drafted = results[0].Content
+			return llm.Reply{}, llm.ErrUnavailable
+		}
+		return llm.Reply{ToolCalls: []llm.ToolCall{
+			toolCall("1", "draft_route", fmt.Sprintf(`{"originCityName":"Bali","destinations":[%d]}`, catalog.kuta.ID)),
+		}}, nil
+	}
+	rec = doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Rute ke Kuta"})
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, drafted, `"draft_id"`)
+	var drafts int64
+	config.DB.Model(&models.RouteDraft{}).Count(&drafts)
+	assert.Zero(t, drafts)
+
+	// CHAT_TOOLS=false: tidak ada tool yang ditawarkan ke model
+	t.Setenv("CHAT_TOOLS", "false")
+	var offered int
+	mock.RespondWithTools = func(messages []llm.Message, tools []llm.Tool) (llm.Reply, error) {
+		offered = len(tools)
+		return llm.Reply{Text: "Oke"}, nil
+	}
+	rec = doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Halo lagi"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, offered)
+	calls := mock.Calls()
+	assert.False(t, strings.Contains(systemPrompt(calls[len(calls)-1]), "draft_route"))
+}
@@ -0,0 +1,55 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/controllers"
+	"backend/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDashboardGraphicDataHandler(t *testing.T) {
+	config.TestInitDB()
+
+	users := []models.User{
+		{Username: "graphjan1", Email: "graphjan1@example.com"},
+		{Username: "graphjan2", Email: "graphjan2@example.com"},
+		{Username: "graphmar1", Email: "graphmar1@example.com"},
+	}
+	users[0].CreatedAt = time.Date(2024, time.January, 5, 10, 0, 0, 0, time.UTC)
+	users[1].CreatedAt = time.Date(2024, time.January, 20, 10, 0, 0, 0, time.UTC)
+	users[2].CreatedAt = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	for i := range users {
+		if err := config.DB.Create(&users[i]).Error; err != nil {
+			t.Fatalf("Failed to seed user: %v", err)
+		}
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/dashboard/graphic", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := controllers.GetDashboardGraphicDataHandler(c); err != nil {
+		t.Fatalf("Handler error: %v", err)
+	}
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data map[string]int `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	assert.Equal(t, 2, response.Data["Jan"])
+	assert.Equal(t, 0, response.Data["Feb"])
+	assert.Equal(t, 1, response.Data["Mar"])
+}
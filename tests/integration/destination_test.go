@@ -0,0 +1,161 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/response"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestGetNearbyDestinations(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "explorer")
+
+	city := models.City{Name: "Jakarta", Latitude: float(-6.2088), Longitude: float(106.8456)}
+	config.DB.Create(&city)
+
+	config.DB.Create(&models.Destination{Name: "Monas", CityID: city.ID, Latitude: float(-6.1754), Longitude: float(106.8272)})
+	config.DB.Create(&models.Destination{Name: "Kota Tua", CityID: city.ID, Latitude: float(-6.1352), Longitude: float(106.8133)})
+	config.DB.Create(&models.Destination{Name: "Kebun Raya Bogor", CityID: city.ID, Latitude: float(-6.5976), Longitude: float(106.7996)})
+	config.DB.Create(&models.Destination{Name: "Unmapped", CityID: city.ID})
+
+	rec := doJSON(e, http.MethodGet, "/destination/nearby?lat=-6.1800&lng=106.8300&radius_km=10", token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Destinations []struct {
+			Name       string  `json:"name"`
+			DistanceKm float64 `json:"distance_km"`
+		} `json:"destinations"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if assert.Len(t, response.Destinations, 2) {
+		assert.Equal(t, "Monas", response.Destinations[0].Name)
+		assert.Equal(t, "Kota Tua", response.Destinations[1].Name)
+		assert.Less(t, response.Destinations[0].DistanceKm, response.Destinations[1].DistanceKm)
+		assert.InDelta(t, 5.4, response.Destinations[1].DistanceKm, 0.5)
+	}
+
+	rec = doJSON(e, http.MethodGet, "/destination/nearby?lat=-6.18&lng=106.83&radius_km=100", token, nil)
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Len(t, response.Destinations, 3)
+
+	rec = doJSON(e, http.MethodGet, "/destination/nearby?lat=95&lng=106.83", token, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCreateCityValidatesCoordinates(t *testing.T) {
+	e, _ := newTestServer()
+
+	rec := doJSON(e, http.MethodPost, "/city", "", map[string]interface{}{"name": "Nowhere", "latitude": 95.0, "longitude": 10.0})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, "/city", "", map[string]interface{}{"name": "Halfway", "latitude": 10.0})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, "/city", "", map[string]interface{}{"name": "Bandung", "latitude": -6.9175, "longitude": 107.6191})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var city models.City
+	config.DB.First(&city, "name = ?", "Bandung")
+	lat, lng, ok := city.Coordinates()
+	assert.True(t, ok)
+	assert.InDelta(t, -6.9175, lat, 1e-9)
+	assert.InDelta(t, 107.6191, lng, 1e-9)
+}
+
+// registerAdmin mendaftarkan user terverifikasi lalu menjadikannya admin
+func registerAdmin(t *testing.T, e *echo.Echo, outbox *bytes.Buffer, username string) string {
+	registerUser(t, e, outbox, username)
+	config.DB.Model(&models.User{}).Where("username = ?", username).Update("role", "admin")
+	return login(t, e, username).Token
+}
+
+func TestDestinationEcoScore(t *testing.T) {
+	e, outbox := newTestServer()
+	adminToken := registerAdmin(t, e, outbox, "ecoadmin")
+
+	config.DB.Create(&models.City{Name: "Bali"})
+
+	green := map[string]interface{}{
+		"name":                        "Green Village",
+		"city":                        "Bali",
+		"category":                    "Ecotourism",
+		"certifications":              []string{"Green Globe", "EarthCheck"},
+		"waste_practice":              "zero_waste",
+		"energy_practice":             "renewable",
+		"visitor_capacity":            200,
+		"public_transport_accessible": true,
+	}
+	rec := doJSON(e, http.MethodPost, "/destination", adminToken, green)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var created models.Destination
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	assert.Equal(t, 20+20+20+15+15, created.EcoScore)
+
+	rec = doJSON(e, http.MethodPost, "/destination", adminToken, map[string]interface{}{
+		"name":           "Plain Beach",
+		"city":           "Bali",
+		"waste_practice": "basic",
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, "/destination", adminToken, map[string]interface{}{
+		"name":            "Bad Input",
+		"city":            "Bali",
+		"energy_practice": "coal",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	type listing struct {
+		Destinations []response.DestinationResponse `json:"destinations"`
+	}
+
+	rec = doJSON(e, http.MethodGet, "/destination?sort=eco_score", adminToken, nil)
+	var sorted listing
+	json.Unmarshal(rec.Body.Bytes(), &sorted)
+	if assert.Len(t, sorted.Destinations, 2) {
+		assert.Equal(t, "Green Village", sorted.Destinations[0].Name)
+		assert.Equal(t, []string{"Green Globe", "EarthCheck"}, sorted.Destinations[0].Sustainability.Certifications)
+		assert.Equal(t, 5, sorted.Destinations[1].EcoScore)
+	}
+
+	rec = doJSON(e, http.MethodGet, "/destination?min_eco_score=50", adminToken, nil)
+	var filtered listing
+	json.Unmarshal(rec.Body.Bytes(), &filtered)
+	assert.Len(t, filtered.Destinations, 1)
+
+	rec = doJSON(e, http.MethodGet, "/destination?certified=true&public_transport=true", adminToken, nil)
+	filtered = listing{}
+	json.Unmarshal(rec.Body.Bytes(), &filtered)
+	assert.Len(t, filtered.Destinations, 1)
+
+	rec = doJSON(e, http.MethodGet, "/destination?sort=eco_score&cursor=", adminToken, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doJSON(e, http.MethodGet, "/destination?sort=newest&cursor=", adminToken, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Update menghitung ulang skor
+	green["certifications"] = []string{}
+	green["public_transport_accessible"] = false
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/destination/%d", created.ID), adminToken, green)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var updated models.Destination
+	config.DB.First(&updated, created.ID)
+	assert.Equal(t, 20+20+15, updated.EcoScore)
+}
@@ -0,0 +1,90 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/response"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFavoriteDestinations(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "wishlister")
+	otherToken, _ := registerUser(t, e, outbox, "otherfan")
+
+	city := models.City{Name: "Flores"}
+	config.DB.Create(&city)
+	komodo := models.Destination{Name: "Komodo", CityID: city.ID}
+	kelimutu := models.Destination{Name: "Kelimutu", CityID: city.ID}
+	config.DB.Create(&komodo)
+	config.DB.Create(&kelimutu)
+
+	rec := doJSON(e, http.MethodPost, fmt.Sprintf("/destination/%d/favorite", komodo.ID), token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = doJSON(e, http.MethodPost, fmt.Sprintf("/destination/%d/favorite", komodo.ID), token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	doJSON(e, http.MethodPost, fmt.Sprintf("/destination/%d/favorite", kelimutu.ID), token, nil)
+	doJSON(e, http.MethodPost, fmt.Sprintf("/destination/%d/favorite", komodo.ID), otherToken, nil)
+
+	rec = doJSON(e, http.MethodPost, "/destination/9999/favorite", token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var favorites struct {
+		Destinations []response.DestinationResponse `json:"destinations"`
+	}
+	rec = doJSON(e, http.MethodGet, "/user/me/favorites", token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &favorites)
+	if assert.Len(t, favorites.Destinations, 2) {
+		assert.Equal(t, "Kelimutu", favorites.Destinations[0].Name)
+		assert.True(t, favorites.Destinations[0].IsFavorite)
+	}
+
+	rec = doJSON(e, http.MethodDelete, fmt.Sprintf("/destination/%d/favorite", kelimutu.ID), token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var listed struct {
+		Destinations []response.DestinationResponse `json:"destinations"`
+	}
+	rec = doJSON(e, http.MethodGet, "/destination", token, nil)
+	json.Unmarshal(rec.Body.Bytes(), &listed)
+	isFavorite := map[string]bool{}
+	for _, destination := range listed.Destinations {
+		isFavorite[destination.Name] = destination.IsFavorite
+	}
+	assert.Equal(t, map[string]bool{"Komodo": true, "Kelimutu": false}, isFavorite)
+
+	var detail struct {
+		Destination response.DestinationResponse `json:"destination"`
+	}
+	rec = doJSON(e, http.MethodGet, fmt.Sprintf("/destination/%d", komodo.ID), token, nil)
+	json.Unmarshal(rec.Body.Bytes(), &detail)
+	assert.True(t, detail.Destination.IsFavorite)
+
+	var dashboard struct {
+		Data struct {
+			Favorite                int64 `json:"favorite"`
+			TopFavoriteDestinations []struct {
+				Name  string `json:"name"`
+				Count int64  `json:"count"`
+			} `json:"topFavoriteDestinations"`
+		} `json:"data"`
+	}
+	// Ranking favorit hanya untuk dashboard admin
+	assert.Equal(t, http.StatusUnauthorized, doJSON(e, http.MethodGet, "/dashboard/count-data", "", nil).Code)
+	assert.Equal(t, http.StatusForbidden, doJSON(e, http.MethodGet, "/dashboard/count-data", token, nil).Code)
+	adminToken := registerAdmin(t, e, outbox, "favoriteadmin")
+	rec = doJSON(e, http.MethodGet, "/dashboard/count-data", adminToken, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &dashboard)
+	assert.Equal(t, int64(2), dashboard.Data.Favorite)
+	if assert.Len(t, dashboard.Data.TopFavoriteDestinations, 1) {
+		assert.Equal(t, "Komodo", dashboard.Data.TopFavoriteDestinations[0].Name)
+		assert.Equal(t, int64(2), dashboard.Data.TopFavoriteDestinations[0].Count)
+	}
+}
@@ -0,0 +1,227 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/jobs"
+	"backend/models"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jpegWithOrientation membuat JPEG width x height dengan segmen EXIF berisi tag
+// Orientation dan penanda yang harus hilang dari varian
+func jpegWithOrientation(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3)
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, []byte("GPS-SECRET-LOCATION")...)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := encoded.Bytes()
+	return append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+}
+
+type imageResponse struct {
+	URL      string `json:"url"`
+	Variants map[string]struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"variants"`
+}
+
+func TestUploadGeneratesImageVariants(t *testing.T) {
+	e, outbox := newTestServer()
+	useTempUploads(t)
+	adminToken := registerAdmin(t, e, outbox, "variantadmin")
+
+	city := models.City{Name: "Lombok"}
+	config.DB.Create(&city)
+	destination := models.Destination{Name: "Gili Trawangan", CityID: city.ID}
+	config.DB.Create(&destination)
+
+	// Foto 2000x1000 yang harus diputar 90 derajat (orientation 6) menjadi potret
+	rec := doMultipart(e, http.MethodPost, fmt.Sprintf("/destination/%d/images", destination.ID), adminToken, nil,
+		formFile{"images", "beach.jpg", jpegWithOrientation(t, 2000, 1000, 6)})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var result struct {
+		Images []imageResponse `json:"images"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &result)
+	if !assert.Len(t, result.Images, 1) {
+		return
+	}
+	variants := result.Images[0].Variants
+	assert.Len(t, variants, 3)
+	assert.Equal(t, [2]int{160, 320}, [2]int{variants["thumbnail"].Width, variants["thumbnail"].Height})
+	assert.Equal(t, [2]int{400, 800}, [2]int{variants["medium"].Width, variants["medium"].Height})
+	assert.Equal(t, [2]int{800, 1600}, [2]int{variants["large"].Width, variants["large"].Height})
+
+	var stored []models.ImageVariant
+	config.DB.Find(&stored)
+	assert.Len(t, stored, 3)
+	for _, variant := range stored {
+		reader, err := config.Storage.Get(context.Background(), variant.Key)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		assert.NotContains(t, string(data), "Exif", variant.Name)
+		assert.NotContains(t, string(data), "GPS-SECRET-LOCATION", variant.Name)
+
+		decoded, format, err := image.DecodeConfig(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, variant.Width, decoded.Width)
+	}
+
+	var uploaded models.Image
+	config.DB.First(&uploaded)
+	assert.Equal(t, models.ImageVariantsReady, uploaded.VariantsStatus)
+
+	// Listing destinasi mengembalikan map varian
+	var listing struct {
+		Destinations []struct {
+			Images []imageResponse `json:"images"`
+		} `json:"destinations"`
+	}
+	rec = doJSON(e, http.MethodGet, "/destination", adminToken, nil)
+	json.Unmarshal(rec.Body.Bytes(), &listing)
+	if assert.Len(t, listing.Destinations, 1) && assert.Len(t, listing.Destinations[0].Images, 1) {
+		assert.Equal(t, variants["thumbnail"].URL, listing.Destinations[0].Images[0].Variants["thumbnail"].URL)
+	}
+}
+
+func TestBackfillImageVariants(t *testing.T) {
+	newTestServer()
+	useTempUploads(t)
+
+	photo := pngBytes(t, color.RGBA{G: 200, A: 255})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/photo.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(photo)
+	}))
+	defer server.Close()
+
+	// Server uji ada di loopback, yang ditolak oleh HTTPClient bawaan
+	t.Setenv("IMAGE_DOWNLOAD_HOSTS", "127.0.0.1")
+	useDownloadClient(t, server.Client())
+
+	city := models.City{Name: "Flores"}
+	config.DB.Create(&city)
+	destination := models.Destination{Name: "Komodo", CityID: city.ID}
+	config.DB.Create(&destination)
+
+	legacy := models.Image{DestinationID: destination.ID, URL: server.URL + "/photo.png"}
+	broken := models.Image{DestinationID: destination.ID, URL: server.URL + "/missing.png"}
+	config.DB.Create(&legacy)
+	config.DB.Create(&broken)
+
+	processed, err := jobs.BackfillImageVariants(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	config.DB.Preload("Variants").First(&legacy, legacy.ID)
+	assert.Equal(t, models.ImageVariantsReady, legacy.VariantsStatus)
+	if assert.Len(t, legacy.Variants, 3) {
+		// Gambar 4x4 tidak diperbesar
+		assert.Equal(t, 4, legacy.Variants[0].Width)
+	}
+
+	// Gambar yang gagal dicoba ulang sampai MaxVariantAttempts lalu ditandai failed
+	config.DB.First(&broken, broken.ID)
+	assert.Equal(t, models.ImageVariantsPending, broken.VariantsStatus)
+	// Satu putaran hanya mencoba setiap gambar sekali, walaupun batch-nya kecil
+	processed, err = jobs.BackfillAllImageVariants(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Zero(t, processed)
+	config.DB.First(&broken, broken.ID)
+	assert.Equal(t, 2, broken.VariantAttempts)
+	for i := 2; i < jobs.MaxVariantAttempts; i++ {
+		jobs.BackfillAllImageVariants(context.Background(), 1)
+	}
+	config.DB.First(&broken, broken.ID)
+	assert.Equal(t, models.ImageVariantsFailed, broken.VariantsStatus)
+	assert.Equal(t, jobs.MaxVariantAttempts, broken.VariantAttempts)
+
+	processed, err = jobs.BackfillImageVariants(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Zero(t, processed)
+}
+
+func useDownloadClient(t *testing.T, client *http.Client) {
+	previous := jobs.HTTPClient
+	jobs.HTTPClient = client
+	t.Cleanup(func() { jobs.HTTPClient = previous })
+}
+
+func TestBackfillRefusesInternalImageURLs(t *testing.T) {
+	newTestServer()
+	useTempUploads(t)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(pngBytes(t, color.RGBA{R: 200, A: 255}))
+	}))
+	defer server.Close()
+
+	city := models.City{Name: "Flores"}
+	config.DB.Create(&city)
+	destination := models.Destination{Name: "Komodo", CityID: city.ID}
+	config.DB.Create(&destination)
+	internal := models.Image{DestinationID: destination.ID, URL: server.URL + "/photo.png"}
+	config.DB.Create(&internal)
+
+	// Host di luar IMAGE_DOWNLOAD_HOSTS tidak diunduh
+	processed, err := jobs.BackfillImageVariants(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Zero(t, processed)
+
+	// Walaupun diizinkan, alamat loopback ditolak setelah resolve
+	t.Setenv("IMAGE_DOWNLOAD_HOSTS", "127.0.0.1")
+	processed, _ = jobs.BackfillImageVariants(context.Background(), 10)
+	assert.Zero(t, processed)
+	assert.Zero(t, requests.Load())
+
+	config.DB.First(&internal, internal.ID)
+	assert.Equal(t, 2, internal.VariantAttempts)
+}
@@ -0,0 +1,130 @@
+package controllers_test
+
+import (
+	"backend/response"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseItinerary(t *testing.T, body []byte) response.RouteDetailResponse {
+	var detail response.RouteDetailResponse
+	if err := json.Unmarshal(body, &detail); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return detail
+}
+
+// itineraryIDs meratakan itinerary menjadi daftar destination ID per hari
+func itineraryIDs(detail response.RouteDetailResponse) map[int][]uint {
+	days := make(map[int][]uint)
+	for _, day := range detail.Days {
+		for _, stop := range day.Stops {
+			days[day.Day] = append(days[day.Day], stop.DestinationID)
+		}
+	}
+	return days
+}
+
+func TestRouteItineraryStops(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "itinerary")
+	otherToken, _ := registerUser(t, e, outbox, "intruder")
+
+	_, _, ids := seedLine(4)
+
+	rec := doJSON(e, http.MethodPost, "/route", token, map[string]interface{}{
+		"originCityName":      "Origin",
+		"destinationCityName": "End",
+		"destinations":        ids[:2],
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var created struct {
+		ID uint `json:"id"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	base := fmt.Sprintf("/route/%d", created.ID)
+
+	rec = doJSON(e, http.MethodGet, base, otherToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = doJSON(e, http.MethodPost, base+"/stops", otherToken, map[string]interface{}{"destination_id": ids[2]})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, base+"/stops", token, map[string]interface{}{
+		"destination_id": ids[3],
+		"day":            2,
+		"arrival_time":   "09:00",
+		"departure_time": "11:30",
+		"notes":          "Sarapan dulu",
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	detail := parseItinerary(t, rec.Body.Bytes())
+	assert.Equal(t, map[int][]uint{1: {ids[0], ids[1]}, 2: {ids[3]}}, itineraryIDs(detail))
+	if assert.Len(t, detail.Days, 2) {
+		stop := detail.Days[1].Stops[0]
+		assert.Equal(t, 150, stop.StayMinutes)
+		assert.Equal(t, "Sarapan dulu", stop.Notes)
+		assert.Equal(t, 3, stop.Sequence)
+		assert.Equal(t, ids[3], stop.Destination.ID)
+	}
+
+	rec = doJSON(e, http.MethodPost, base+"/stops", token, map[string]interface{}{"destination_id": ids[2], "position": 1})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	detail = parseItinerary(t, rec.Body.Bytes())
+	assert.Equal(t, map[int][]uint{1: {ids[2], ids[0], ids[1]}, 2: {ids[3]}}, itineraryIDs(detail))
+
+	rec = doJSON(e, http.MethodPost, base+"/stops", token, map[string]interface{}{
+		"destination_id": ids[2],
+		"arrival_time":   "12:00",
+		"departure_time": "10:00",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doJSON(e, http.MethodPost, base+"/stops", token, map[string]interface{}{"destination_id": ids[2], "arrival_time": "25:00"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Kembalikan urutan sesuai garis dan pindahkan stop terakhir ke hari 1
+	var order []map[string]interface{}
+	for _, day := range detail.Days {
+		for _, stop := range day.Stops {
+			order = append(order, map[string]interface{}{"id": stop.ID})
+		}
+	}
+	reordered := []map[string]interface{}{order[1], order[2], order[0], {"id": order[3]["id"], "day": 1}}
+	rec = doJSON(e, http.MethodPut, base+"/stops/order", token, map[string]interface{}{"stops": reordered})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	detail = parseItinerary(t, rec.Body.Bytes())
+	assert.Equal(t, map[int][]uint{1: {ids[0], ids[1], ids[2], ids[3]}}, itineraryIDs(detail))
+
+	rec = doJSON(e, http.MethodPut, base+"/stops/order", token, map[string]interface{}{"stops": reordered[:2]})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	lastStop := detail.Days[0].Stops[3]
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("%s/stops/%d", base, lastStop.ID), token, map[string]interface{}{
+		"day":          3,
+		"arrival_time": "",
+		"notes":        "Menginap",
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	detail = parseItinerary(t, rec.Body.Bytes())
+	if assert.Len(t, detail.Days, 2) {
+		assert.Equal(t, 3, detail.Days[1].Day)
+		assert.Equal(t, "", detail.Days[1].Stops[0].ArrivalTime)
+		assert.Equal(t, "11:30", detail.Days[1].Stops[0].DepartureTime)
+		assert.Equal(t, "Menginap", detail.Days[1].Stops[0].Notes)
+	}
+	distanceWithAll := detail.Distance
+
+	rec = doJSON(e, http.MethodDelete, fmt.Sprintf("%s/stops/%d", base, detail.Days[0].Stops[0].ID), token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	detail = parseItinerary(t, rec.Body.Bytes())
+	assert.Equal(t, map[int][]uint{1: {ids[1], ids[2]}, 3: {ids[3]}}, itineraryIDs(detail))
+	assert.Equal(t, 1, detail.Days[0].Stops[0].Sequence)
+	// Stop berada di garis lurus, jadi jarak total tidak berubah
+	assert.InDelta(t, distanceWithAll, detail.Distance, 0.05)
+
+	rec = doJSON(e, http.MethodDelete, fmt.Sprintf("%s/stops/%d", base, 99999), token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
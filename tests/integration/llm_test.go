@@ -0,0 +1,298 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/llm"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useLLM mengganti provider chat selama test berjalan
+func useLLM(t *testing.T, provider llm.ChatProvider) {
+	previous := config.LLM
+	config.LLM = provider
+	t.Cleanup(func() { config.LLM = previous })
+}
+
+func TestChatProviderErrorStatus(t *testing.T) {
+	e, outbox := newTestServer()
+	mock := llm.NewMock()
+	useLLM(t, mock)
+	token, _ := registerUser(t, e, outbox, "mockchatter")
+
+	code, reply := sendChat(e, token, map[string]interface{}{"message": "halo, ada rekomendasi?"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "You said: halo, ada rekomendasi?", reply.Data)
+
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{llm.ErrRateLimited, http.StatusTooManyRequests},
+		{llm.ErrTimeout, http.StatusGatewayTimeout},
+		{llm.ErrRejected, http.StatusUnprocessableEntity},
+		{llm.ErrUnavailable, http.StatusBadGateway},
+		{llm.ErrEmptyResponse, http.StatusBadGateway},
+		{llm.ErrMisconfigured, http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		mock.Respond = func([]llm.Message) (string, error) { return "", fmt.Errorf("mock: %w", tc.err) }
+		code, _ := sendChat(e, token, map[string]interface{}{"message": "Masih di sana?"})
+		assert.Equal(t, tc.status, code, tc.err.Error())
+
+		rec := doJSON(e, http.MethodPost, "/chat/stream", token, map[string]string{"message": "Masih di sana?"})
+		assert.Equal(t, tc.status, rec.Code, tc.err.Error())
+	}
+}
+
+func TestGeminiProviderRetries(t *testing.T) {
+	var calls atomic.Int32
+	var respond func(w http.ResponseWriter, call int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.Header.Get("X-Goog-Api-Key"))
+		assert.Empty(t, r.URL.Query().Get("key"))
+		respond(w, calls.Add(1))
+	}))
+	defer server.Close()
+
+	gemini := &llm.Gemini{URL: server.URL, APIKey: "test-key", Retry: llm.Retry{Timeout: time.Second, MaxRetries: 2}}
+	messages := []llm.Message{{Role: llm.RoleUser, Content: "Halo"}}
+	candidate := `{"candidates":[{"content":{"role":"model","parts":[{"text":"Selamat datang"}]}}],"usageMetadata":{"promptTokenCount":1,"candidatesTokenCount":2,"totalTokenCount":3}}`
+
+	// 429 dan 503 dicoba ulang; tidak ada lagi jawaban instan untuk "halo"
+	respond = func(w http.ResponseWriter, call int32) {
+		switch call {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, candidate)
+		}
+	}
+	reply, err := gemini.Chat(context.Background(), messages)
+	assert.NoError(t, err)
+	assert.Equal(t, "Selamat datang", reply.Text)
+	assert.Equal(t, 3, reply.Usage.TotalTokens)
+	assert.EqualValues(t, 3, calls.Load())
+
+	// Percobaan habis: error terakhir dikembalikan lengkap dengan status code
+	calls.Store(0)
+	respond = func(w http.ResponseWriter, call int32) { http.Error(w, "slow down", http.StatusTooManyRequests) }
+	_, err = gemini.Chat(context.Background(), messages)
+	assert.ErrorIs(t, err, llm.ErrRateLimited)
+	var statusErr *llm.StatusError
+	if assert.ErrorAs(t, err, &statusErr) {
+		assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	}
+	assert.EqualValues(t, 3, calls.Load())
+
+	// Error permanen tidak dicoba ulang
+	calls.Store(0)
+	respond = func(w http.ResponseWriter, call int32) { http.Error(w, "bad request", http.StatusBadRequest) }
+	_, err = gemini.Chat(context.Background(), messages)
+	assert.ErrorIs(t, err, llm.ErrRejected)
+	assert.EqualValues(t, 1, calls.Load())
+
+	// Candidates kosong (mis. diblokir filter keamanan) tidak lagi panic
+	respond = func(w http.ResponseWriter, call int32) { fmt.Fprint(w, `{"candidates":[]}`) }
+	_, err = gemini.Chat(context.Background(), messages)
+	assert.ErrorIs(t, err, llm.ErrEmptyResponse)
+	respond = func(w http.ResponseWriter, call int32) { fmt.Fprint(w, `{"promptFeedback":{"blockReason":"SAFETY"}}`) }
+	_, err = gemini.Chat(context.Background(), messages)
+	assert.ErrorIs(t, err, llm.ErrRejected)
+
+	// Provider yang tidak menjawab dihentikan oleh Timeout
+	calls.Store(0)
+	gemini.Retry = llm.Retry{Timeout: 50 * time.Millisecond}
+	respond = func(w http.ResponseWriter, call int32) { time.Sleep(500 * time.Millisecond) }
+	_, err = gemini.Chat(context.Background(), messages)
+	assert.ErrorIs(t, err, llm.ErrTimeout)
+	_, err = gemini.Stream(context.Background(), messages, func(string) error { return nil })
+	assert.ErrorIs(t, err, llm.ErrTimeout)
+
+	// Error transport mencantumkan URL; API key tidak boleh ikut tertulis di log
+	server.Close()
+	_, err = gemini.Chat(context.Background(), messages)
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "test-key")
+	}
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		if failures.Add(-1) >= 0 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+
+		var payload struct {
+			Model    string `json:"model"`
+			Stream   bool   `json:"stream"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		assert.Equal(t, "travel-model", payload.Model)
+		roles := make([]string, len(payload.Messages))
+		for i, message := range payload.Messages {
+			roles[i] = message.Role
+		}
+		assert.Equal(t, []string{"user", "assistant", "user"}, roles)
+
+		if !payload.Stream {
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Coba Raja Ampat"}}],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"delta":{"role":"assistant","content":"Coba "}}]}`,
+			`{"choices":[{"delta":{"content":"Raja Ampat"}}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`,
+			`[DONE]`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+	}))
+	defer server.Close()
+
+	openAI := &llm.OpenAI{
+		BaseURL: server.URL + "/v1",
+		APIKey:  "sk-test",
+		Model:   "travel-model",
+		Retry:   llm.Retry{Timeout: time.Second, MaxRetries: 1},
+	}
+	messages := []llm.Message{
+		{Role: llm.RoleUser, Content: "Mau diving"},
+		{Role: llm.RoleModel, Content: "Di mana?"},
+		{Role: llm.RoleUser, Content: "Indonesia timur"},
+	}
+
+	reply, err := openAI.Chat(context.Background(), messages)
+	assert.NoError(t, err)
+	assert.Equal(t, "Coba Raja Ampat", reply.Text)
+	assert.Equal(t, llm.Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}, reply.Usage)
+
+	failures.Store(1)
+	var streamed []string
+	streamReply, err := openAI.Stream(context.Background(), messages, func(text string) error {
+		streamed = append(streamed, text)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Coba Raja Ampat", strings.Join(streamed, ""))
+	assert.Equal(t, 15, streamReply.Usage.TotalTokens)
+	assert.Equal(t, "Coba Raja Ampat", streamReply.Text)
+
+	// Error dari onText menghentikan stream dan dikembalikan apa adanya
+	stop := errors.New("client gone")
+	_, err = openAI.Stream(context.Background(), messages, func(string) error { return stop })
+	assert.Equal(t, stop, err)
+}
+
+func TestProviderToolCalls(t *testing.T) {
+	tools := []llm.Tool{{
+		Name:        "list_cities",
+		Description: "Daftar kota",
+		Parameters:  json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"}}}`),
+	}}
+	messages := []llm.Message{
+		{Role: llm.RoleUser, Content: "Kota apa saja?"},
+		{Role: llm.RoleModel, ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "list_cities", Arguments: json.RawMessage(`{"query":"ba"}`)}}},
+		{Role: llm.RoleTool, ToolCallID: "call_1", ToolName: "list_cities", Content: `{"cities":["Bali"]}`},
+		{Role: llm.RoleTool, ToolCallID: "call_2", ToolName: "list_cities", Content: "bukan json"},
+	}
+
+	var payload map[string]json.RawMessage
+	var respond string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		if strings.Contains(r.URL.Path, "stream") {
+			w.Header().Set("Content-Type", "text/event-stream")
+		}
+		fmt.Fprint(w, respond)
+	}))
+	defer server.Close()
+
+	// Gemini: functionDeclarations, functionCall di giliran model, dan hasil tool
+	// berurutan digabung menjadi satu giliran user berisi functionResponse. Retry
+	// sengaja kosong: Timeout nol memakai DefaultRetry.Timeout.
+	gemini := &llm.Gemini{URL: server.URL, StreamURL: server.URL + "/stream", APIKey: "k"}
+	respond = `{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"list_cities","args":{"query":"bandung"}}}]}}]}`
+	reply, err := gemini.Chat(context.Background(), messages, tools...)
+	assert.NoError(t, err)
+	assert.Empty(t, reply.Text)
+	if assert.Len(t, reply.ToolCalls, 1) {
+		assert.Equal(t, "list_cities", reply.ToolCalls[0].Name)
+		assert.JSONEq(t, `{"query":"bandung"}`, string(reply.ToolCalls[0].Arguments))
+	}
+	assert.JSONEq(t, `[{"functionDeclarations":[{"name":"list_cities","description":"Daftar kota","parameters":{"type":"object","properties":{"query":{"type":"string"}}}}]}]`, string(payload["tools"]))
+	assert.JSONEq(t, `[
+		{"role":"user","parts":[{"text":"Kota apa saja?"}]},
+		{"role":"model","parts":[{"functionCall":{"name":"list_cities","args":{"query":"ba"}}}]},
+		{"role":"user","parts":[
+			{"functionResponse":{"name":"list_cities","response":{"cities":["Bali"]}}},
+			{"functionResponse":{"name":"list_cities","response":{"result":"bukan json"}}}
+		]}
+	]`, string(payload["contents"]))
+
+	respond = `data: {"candidates":[{"content":{"parts":[{"functionCall":{"name":"list_cities","args":{}}}]}}],"usageMetadata":{"totalTokenCount":4}}` + "\n\n"
+	reply, err = gemini.Stream(context.Background(), messages, func(string) error { return nil }, tools...)
+	assert.NoError(t, err)
+	assert.Len(t, reply.ToolCalls, 1)
+	assert.Equal(t, 4, reply.Usage.TotalTokens)
+
+	// OpenAI: tools bertipe function, tool_calls di pesan assistant, dan role "tool"
+	openAI := &llm.OpenAI{BaseURL: server.URL, Model: "m", Retry: llm.Retry{Timeout: time.Second}}
+	respond = `{"choices":[{"message":{"role":"assistant","content":null,"tool_calls":[{"id":"call_9","type":"function","function":{"name":"list_cities","arguments":"{\"query\":\"bali\"}"}}]}}]}`
+	reply, err = openAI.Chat(context.Background(), messages, tools...)
+	assert.NoError(t, err)
+	if assert.Len(t, reply.ToolCalls, 1) {
+		assert.Equal(t, llm.ToolCall{ID: "call_9", Name: "list_cities", Arguments: json.RawMessage(`{"query":"bali"}`)}, reply.ToolCalls[0])
+	}
+	assert.JSONEq(t, `[{"type":"function","function":{"name":"list_cities","description":"Daftar kota","parameters":{"type":"object","properties":{"query":{"type":"string"}}}}}]`, string(payload["tools"]))
+	assert.JSONEq(t, `[
+		{"role":"user","content":"Kota apa saja?"},
+		{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"list_cities","arguments":"{\"query\":\"ba\"}"}}]},
+		{"role":"tool","content":"{\"cities\":[\"Bali\"]}","tool_call_id":"call_1"},
+		{"role":"tool","content":"bukan json","tool_call_id":"call_2"}
+	]`, string(payload["messages"]))
+
+	// Argumen yang di-stream sepotong-sepotong disambung per index
+	var chunks strings.Builder
+	for _, chunk := range []string{
+		`{"choices":[{"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"list_cities","arguments":""}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"query\":"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_b","function":{"name":"list_cities","arguments":"{}"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"ba\"}"}}]}}]}`,
+		`[DONE]`,
+	} {
+		fmt.Fprintf(&chunks, "data: %s\n\n", chunk)
+	}
+	respond = chunks.String()
+	reply, err = openAI.Stream(context.Background(), messages, func(string) error { return nil }, tools...)
+	assert.NoError(t, err)
+	if assert.Len(t, reply.ToolCalls, 2) {
+		assert.Equal(t, "call_a", reply.ToolCalls[0].ID)
+		assert.JSONEq(t, `{"query":"ba"}`, string(reply.ToolCalls[0].Arguments))
+		assert.Equal(t, "call_b", reply.ToolCalls[1].ID)
+	}
+}
@@ -0,0 +1,174 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/media"
+	"backend/models"
+	"backend/storage"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type formFile struct {
+	Field, Name string
+	Data        []byte
+}
+
+func doMultipart(e *echo.Echo, method, path, token string, fields map[string]string, files ...formFile) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	for _, file := range files {
+		part, _ := writer.CreateFormFile(file.Field, file.Name)
+		part.Write(file.Data)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func pngBytes(t *testing.T, c color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(0, 0, c)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// useTempUploads mengarahkan upload ke direktori sementara selama test
+func useTempUploads(t *testing.T) string {
+	dir := t.TempDir()
+	previous := config.Uploads
+	previousStorage := config.Storage
+	config.Storage = storage.NewLocal(dir, "http://localhost:8000/assets")
+	config.Uploads = media.New(config.Storage)
+	t.Cleanup(func() {
+		config.Storage = previousStorage
+		config.Uploads = previous
+	})
+	return dir
+}
+
+func TestUploadDestinationImages(t *testing.T) {
+	e, outbox := newTestServer()
+	dir := useTempUploads(t)
+	adminToken := registerAdmin(t, e, outbox, "mediaadmin")
+	userToken, _ := registerUser(t, e, outbox, "mediauser")
+
+	city := models.City{Name: "Bandung"}
+	config.DB.Create(&city)
+	destination := models.Destination{Name: "Kawah Putih", CityID: city.ID}
+	config.DB.Create(&destination)
+	path := fmt.Sprintf("/destination/%d/images", destination.ID)
+
+	red := pngBytes(t, color.RGBA{R: 255, A: 255})
+	blue := pngBytes(t, color.RGBA{B: 255, A: 255})
+
+	// Nama dari client diabaikan: isi yang sama mendapat URL yang sama
+	rec := doMultipart(e, http.MethodPost, path, adminToken, nil,
+		formFile{"images", "photo.png", red},
+		formFile{"images", "photo.png", blue},
+		formFile{"images", "../../evil.jpg", red})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var result struct {
+		Files []media.File `json:"files"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &result)
+	if assert.Len(t, result.Files, 3) {
+		assert.NotEqual(t, result.Files[0].URL, result.Files[1].URL)
+		assert.Equal(t, result.Files[0].URL, result.Files[2].URL)
+		assert.True(t, strings.HasPrefix(result.Files[0].URL, "http://localhost:8000/assets/uploads/"))
+		assert.True(t, strings.HasSuffix(result.Files[0].URL, ".png"))
+		assert.Equal(t, "image/png", result.Files[0].MIMEType)
+
+		stored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(result.Files[1].Key)))
+		assert.NoError(t, err)
+		assert.Equal(t, blue, stored)
+	}
+
+	var images []models.Image
+	config.DB.Where("destination_id = ?", destination.ID).Find(&images)
+	assert.Len(t, images, 3)
+
+	// Isi file yang bukan gambar ditolak walau ekstensinya .png
+	rec = doMultipart(e, http.MethodPost, path, adminToken, nil, formFile{"images", "fake.png", []byte("<html><body>hi</body></html>")})
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	config.Uploads.MaxBytes = 64
+	rec = doMultipart(e, http.MethodPost, path, adminToken, nil, formFile{"images", "big.png", red})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	config.Uploads.MaxBytes = media.DefaultMaxBytes
+
+	rec = doMultipart(e, http.MethodPost, path, adminToken, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doMultipart(e, http.MethodPost, path, userToken, nil, formFile{"images", "photo.png", red})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doMultipart(e, http.MethodPost, "/destination/999/images", adminToken, nil, formFile{"images", "photo.png", red})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEditUserAvatarUpload(t *testing.T) {
+	e, outbox := newTestServer()
+	useTempUploads(t)
+	token, _ := registerUser(t, e, outbox, "avataruser")
+
+	var user models.User
+	config.DB.Where("username = ?", "avataruser").First(&user)
+	path := fmt.Sprintf("/user/%d", user.ID)
+	fields := map[string]string{
+		"username":     "avataruser",
+		"first_name":   "John",
+		"last_name":    "Doe",
+		"email":        "avataruser@example.com",
+		"city":         "Jakarta",
+		"phone_number": "08123456789",
+		"gender":       "male",
+	}
+
+	rec := doMultipart(e, http.MethodPut, path, token, fields, formFile{"file", "me.png", pngBytes(t, color.Black)})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	config.DB.First(&user, user.ID)
+	assert.True(t, strings.HasPrefix(user.File, "uploads/"), user.File)
+	assert.NotContains(t, user.File, "me.png")
+
+	var detail struct {
+		Data struct {
+			File string `json:"file"`
+		} `json:"data"`
+	}
+	rec = doJSON(e, http.MethodGet, path, token, nil)
+	json.Unmarshal(rec.Body.Bytes(), &detail)
+	assert.Equal(t, "http://localhost:8000/assets/"+user.File, detail.Data.File)
+
+	rec = doMultipart(e, http.MethodPut, path, token, fields, formFile{"file", "me.png", []byte("not an image at all")})
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
@@ -0,0 +1,100 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/migrations"
+	"backend/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationsUpDown(t *testing.T) {
+	config.TestInitDB()
+
+	statuses, err := migrations.StatusOf(config.DB)
+	if err != nil {
+		t.Fatalf("Failed to read migration status: %v", err)
+	}
+	for _, s := range statuses {
+		assert.True(t, s.Applied, "migration %s should be applied", s.Version)
+	}
+
+	all := migrations.All()
+	rolledBack, err := migrations.Down(config.DB, len(all))
+	if err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	assert.Len(t, rolledBack, len(all))
+	assert.False(t, config.DB.Migrator().HasTable("users"))
+
+	applied, err := migrations.Up(config.DB)
+	if err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+	assert.Len(t, applied, len(all))
+	assert.True(t, config.DB.Migrator().HasTable("users"))
+
+	applied, err = migrations.Up(config.DB)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestGeoMigrationBackfillsCityCoordinates(t *testing.T) {
+	config.TestInitDB()
+
+	// Kembali ke skema sebelum 0005 yang masih memakai lat/long string
+	var steps int
+	for _, m := range migrations.All() {
+		if m.Version >= "0005" {
+			steps++
+		}
+	}
+	if _, err := migrations.Down(config.DB, steps); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+
+	config.DB.Exec("INSERT INTO cities (name, lat, `long`) VALUES (?, ?, ?)", "Yogyakarta", "-7.7956", "110.3695")
+	config.DB.Exec("INSERT INTO cities (name, lat, `long`) VALUES (?, ?, ?)", "Broken", "abc", "")
+
+	if _, err := migrations.Up(config.DB); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+
+	var yogya, broken models.City
+	config.DB.First(&yogya, "name = ?", "Yogyakarta")
+	config.DB.First(&broken, "name = ?", "Broken")
+
+	lat, lng, ok := yogya.Coordinates()
+	assert.True(t, ok)
+	assert.InDelta(t, -7.7956, lat, 1e-9)
+	assert.InDelta(t, 110.3695, lng, 1e-9)
+
+	_, _, ok = broken.Coordinates()
+	assert.False(t, ok)
+}
+
+func TestCarbonMigrationBackfillsRoutes(t *testing.T) {
+	config.TestInitDB()
+
+	var steps int
+	for _, m := range migrations.All() {
+		if m.Version >= "0008" {
+			steps++
+		}
+	}
+	if _, err := migrations.Down(config.DB, steps); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+
+	config.DB.Exec("INSERT INTO routes (user_id, origin_city_name, destination_city_name, distance) VALUES (?, ?, ?, ?)", 1, "Bali", "Bandung", 858.87)
+
+	if _, err := migrations.Up(config.DB); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+
+	var route models.Route
+	config.DB.First(&route, "origin_city_name = ?", "Bali")
+	assert.Equal(t, 146.01, route.CarbonKg)
+	assert.Equal(t, 1, route.Travellers)
+}
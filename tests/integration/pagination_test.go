@@ -0,0 +1,65 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cityPage struct {
+	Cities []models.City   `json:"cities"`
+	Meta   helper.PageMeta `json:"meta"`
+}
+
+func TestCityPagination(t *testing.T) {
+	e, _ := newTestServer()
+
+	for i := 1; i <= 5; i++ {
+		config.DB.Create(&models.City{Name: fmt.Sprintf("City %d", i)})
+	}
+
+	rec := doJSON(e, http.MethodGet, "/city?page=2&limit=2", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page cityPage
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	assert.Equal(t, int64(5), page.Meta.Total)
+	assert.Equal(t, 3, page.Meta.TotalPages)
+	assert.True(t, page.Meta.HasMore)
+	if assert.Len(t, page.Cities, 2) {
+		assert.Equal(t, "City 3", page.Cities[0].Name)
+	}
+
+	// Cursor mode walks every row exactly once
+	var names []string
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		rec = doJSON(e, http.MethodGet, "/city?limit=2&cursor="+url.QueryEscape(cursor), "", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		page = cityPage{}
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		for _, city := range page.Cities {
+			names = append(names, city.Name)
+		}
+		if !page.Meta.HasMore {
+			break
+		}
+		cursor = page.Meta.NextCursor
+	}
+	assert.Equal(t, []string{"City 1", "City 2", "City 3", "City 4", "City 5"}, names)
+
+	rec = doJSON(e, http.MethodGet, "/city?limit=1000", "", nil)
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	assert.Equal(t, 500, page.Meta.Limit)
+
+	rec = doJSON(e, http.MethodGet, "/city?cursor=garbage", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,64 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/mailer"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetFlow(t *testing.T) {
+	e, outbox := newTestServer()
+
+	oldToken, _ := registerUser(t, e, outbox, "forgetful")
+	outbox.Reset()
+
+	rec := doJSON(e, http.MethodPost, "/password/forgot", "", map[string]string{"email": "nobody@example.com"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, outbox.String())
+
+	rec = doJSON(e, http.MethodPost, "/password/forgot", "", map[string]string{"email": "forgetful@example.com"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	resetToken := lastMailToken(t, outbox, "/reset-password")
+
+	rec = doJSON(e, http.MethodPost, "/password/reset", "", map[string]string{"token": "wrong", "newPassword": "newsecret1"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, "/password/reset", "", map[string]string{"token": resetToken, "newPassword": "newsecret1"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Single use
+	rec = doJSON(e, http.MethodPost, "/password/reset", "", map[string]string{"token": resetToken, "newPassword": "another1"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Existing sessions are logged out
+	rec = doJSON(e, http.MethodGet, "/route", oldToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, "/login", "", map[string]string{"username": "forgetful", "password": "newsecret1"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	return errors.New("smtp unavailable")
+}
+
+func TestForgotPasswordHidesMailerFailure(t *testing.T) {
+	e, outbox := newTestServer()
+	registerUser(t, e, outbox, "unlucky")
+	config.Mailer = failingMailer{}
+
+	// Email terdaftar dan tidak terdaftar dijawab sama walaupun SMTP mati
+	unknown := doJSON(e, http.MethodPost, "/password/forgot", "", map[string]string{"email": "nobody@example.com"})
+	known := doJSON(e, http.MethodPost, "/password/forgot", "", map[string]string{"email": "unlucky@example.com"})
+	assert.Equal(t, http.StatusOK, known.Code)
+	assert.Equal(t, unknown.Code, known.Code)
+	assert.Equal(t, unknown.Body.String(), known.Body.String())
+}
@@ -0,0 +1,201 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/llm"
+	"backend/models"
+	"backend/retrieval"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type catalogFixture struct {
+	bali, bandung          models.City
+	kuta, ubud, kawahPutih models.Destination
+}
+
+func seedCatalog(t *testing.T) catalogFixture {
+	var f catalogFixture
+	f.bali = models.City{Name: "Bali"}
+	f.bandung = models.City{Name: "Bandung"}
+	config.DB.Create(&f.bali)
+	config.DB.Create(&f.bandung)
+
+	f.kuta = models.Destination{Name: "Pantai Kuta", CityID: f.bali.ID, Category: "Pantai", TicketPrice: 0,
+		Address: "Jalan Legian", Description: "Pantai berpasir putih dengan ombak untuk berselancar"}
+	f.ubud = models.Destination{Name: "Ubud Monkey Forest", CityID: f.bali.ID, Category: "Alam", TicketPrice: 80000,
+		Description: "Hutan lindung dengan ratusan monyet ekor panjang"}
+	f.kawahPutih = models.Destination{Name: "Kawah Putih", CityID: f.bandung.ID, Category: "Alam", TicketPrice: 28000,
+		Description: "Danau kawah vulkanik berwarna hijau toska"}
+	for _, destination := range []*models.Destination{&f.kuta, &f.ubud, &f.kawahPutih} {
+		if err := config.DB.Create(destination).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	config.DB.Create(&models.VideoContent{DestinationID: f.kawahPutih.ID, Title: "Sunrise di Ciwidey", URL: "https://example.com/v.mp4"})
+	return f
+}
+
+// systemPrompt mengambil instruksi sistem dari percakapan yang dikirim ke provider
+func systemPrompt(messages []llm.Message) string {
+	for _, message := range messages {
+		if message.Role == llm.RoleSystem {
+			return message.Content
+		}
+	}
+	return ""
+}
+
+type citedReply struct {
+	Data           string `json:"data"`
+	ConversationID uint   `json:"conversation_id"`
+	Citations      []struct {
+		Type   string `json:"type"`
+		ID     uint   `json:"id"`
+		Name   string `json:"name"`
+		Marker string `json:"marker"`
+	} `json:"citations"`
+}
+
+func TestChatGroundedInCatalogue(t *testing.T) {
+	e, outbox := newTestServer()
+	catalog := seedCatalog(t)
+	mock := llm.NewMock()
+	useLLM(t, mock)
+	token, _ := registerUser(t, e, outbox, "groundeduser")
+
+	var prompt string
+	mock.Respond = func(messages []llm.Message) (string, error) {
+		prompt = systemPrompt(messages)
+		return fmt.Sprintf("Coba Pantai Kuta [D%d] untuk berselancar. Ada juga [D9999].", catalog.kuta.ID), nil
+	}
+
+	rec := doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Pantai buat selancar di Bali?"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var reply citedReply
+	json.Unmarshal(rec.Body.Bytes(), &reply)
+
+	// Data katalog yang cocok disisipkan ke prompt beserta penandanya
+	assert.Contains(t, prompt, fmt.Sprintf("[D%d] Pantai Kuta (Pantai), Bali.", catalog.kuta.ID))
+	assert.Contains(t, prompt, fmt.Sprintf("[D%d] Ubud Monkey Forest", catalog.ubud.ID))
+	assert.Contains(t, prompt, fmt.Sprintf("[C%d] Kota Bali.", catalog.bali.ID))
+	assert.NotContains(t, prompt, "Kawah Putih")
+
+	// Hanya penanda yang benar-benar ada di hasil pencarian yang menjadi citation
+	if assert.Len(t, reply.Citations, 1) {
+		assert.Equal(t, "destination", reply.Citations[0].Type)
+		assert.Equal(t, catalog.kuta.ID, reply.Citations[0].ID)
+		assert.Equal(t, fmt.Sprintf("D%d", catalog.kuta.ID), reply.Citations[0].Marker)
+	}
+
+	// Pertanyaan lanjutan tetap mendapat konteks dari pertanyaan sebelumnya
+	doJSON(e, http.MethodPost, "/chat", token, map[string]interface{}{"message": "Berapa harga tiketnya?", "conversation_id": reply.ConversationID})
+	assert.Contains(t, prompt, "Pantai Kuta (Pantai), Bali. Alamat: Jalan Legian. Tiket: gratis.")
+
+	// Citation tersimpan bersama pesan
+	var detail struct {
+		Data struct {
+			Messages []struct {
+				Citations []struct {
+					ID uint `json:"id"`
+				} `json:"citations"`
+			} `json:"messages"`
+		} `json:"data"`
+	}
+	rec = doJSON(e, http.MethodGet, fmt.Sprintf("/chat/conversations/%d", reply.ConversationID), token, nil)
+	json.Unmarshal(rec.Body.Bytes(), &detail)
+	if assert.Len(t, detail.Data.Messages, 4) {
+		assert.Empty(t, detail.Data.Messages[0].Citations)
+		if assert.Len(t, detail.Data.Messages[1].Citations, 1) {
+			assert.Equal(t, catalog.kuta.ID, detail.Data.Messages[1].Citations[0].ID)
+		}
+	}
+
+	// Judul video ikut dicari; tanpa penanda, nama tempat yang disebut menjadi citation
+	mock.Respond = func(messages []llm.Message) (string, error) {
+		prompt = systemPrompt(messages)
+		return "Kawah Putih paling cantik saat matahari terbit.", nil
+	}
+	rec = doJSON(e, http.MethodPost, "/chat/stream", token, map[string]string{"message": "Lihat sunrise di mana?"})
+	events := readEvents(rec.Body)
+	if assert.NotEmpty(t, events) {
+		done := events[len(events)-1]
+		assert.Equal(t, "done", done.Name)
+		assert.Contains(t, done.Data, fmt.Sprintf(`"marker":"D%d"`, catalog.kawahPutih.ID))
+	}
+	assert.Contains(t, prompt, "Video: Sunrise di Ciwidey.")
+
+	// Tidak ada yang cocok: model diminta tidak mengarang tempat
+	mock.Respond = func(messages []llm.Message) (string, error) {
+		prompt = systemPrompt(messages)
+		return "Belum ada datanya.", nil
+	}
+	rec = doJSON(e, http.MethodPost, "/chat", token, map[string]string{"message": "Rekomendasi ski di Swiss?"})
+	json.Unmarshal(rec.Body.Bytes(), &reply)
+	assert.Contains(t, prompt, "tidak memuat tempat yang cocok")
+	assert.Empty(t, reply.Citations)
+}
+
+func TestCatalogEmbeddingIndex(t *testing.T) {
+	newTestServer()
+	catalog := seedCatalog(t)
+	retriever := &retrieval.Retriever{DB: config.DB, Embedder: llm.NewLocalEmbedder(256), Limit: 3, MinSimilarity: 0.1}
+	ctx := context.Background()
+
+	indexed, err := retriever.Index(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, indexed)
+
+	// Data yang tidak berubah tidak di-embed ulang
+	indexed, _ = retriever.Index(ctx, 2)
+	assert.Zero(t, indexed)
+
+	config.DB.Model(&catalog.ubud).Update("description", "Hutan dengan monyet dan pura kuno")
+	indexed, _ = retriever.Index(ctx, 2)
+	assert.Equal(t, 1, indexed)
+
+	// Menghapus destinasi menghapus vektornya dan memperbarui ringkasan kotanya
+	config.DB.Delete(&catalog.kawahPutih)
+	indexed, _ = retriever.Index(ctx, 2)
+	assert.Equal(t, 1, indexed)
+	var count int64
+	config.DB.Model(&models.CatalogEmbedding{}).Count(&count)
+	assert.EqualValues(t, 4, count)
+
+	// Alamat tidak dicari dengan kata kunci, tetapi ditemukan lewat kemiripan vektor
+	results, err := retriever.Search(ctx, "legian")
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, results) {
+		assert.Equal(t, catalog.kuta.ID, results[0].ID)
+		assert.Equal(t, models.CatalogDestination, results[0].Type)
+	}
+	retriever.Embedder = nil
+	results, _ = retriever.Search(ctx, "legian")
+	assert.Empty(t, results)
+
+	// Vektor dari model lain tidak dipakai
+	retriever.Embedder = llm.NewLocalEmbedder(128)
+	results, _ = retriever.Search(ctx, "legian")
+	assert.Empty(t, results)
+
+	// Embedder yang gagal tidak menghilangkan hasil kata kunci
+	retriever.Embedder = failingEmbedder{}
+	results, err = retriever.Search(ctx, "pantai kuta")
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, results) {
+		assert.Equal(t, catalog.kuta.ID, results[0].ID)
+	}
+}
+
+type failingEmbedder struct{}
+
+func (failingEmbedder) Name() string { return "failing" }
+
+func (failingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, llm.ErrUnavailable
+}
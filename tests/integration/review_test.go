@@ -0,0 +1,121 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/response"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDestinationReviews(t *testing.T) {
+	e, outbox := newTestServer()
+	adminToken := registerAdmin(t, e, outbox, "moderator")
+	aliceToken, _ := registerUser(t, e, outbox, "revieweralice")
+	bobToken, _ := registerUser(t, e, outbox, "reviewerbob")
+
+	city := models.City{Name: "Lombok"}
+	config.DB.Create(&city)
+	beach := models.Destination{Name: "Pink Beach", CityID: city.ID}
+	hill := models.Destination{Name: "Rinjani", CityID: city.ID}
+	config.DB.Create(&beach)
+	config.DB.Create(&hill)
+
+	reviews := fmt.Sprintf("/destination/%d/reviews", beach.ID)
+
+	rec := doJSON(e, http.MethodPost, reviews, aliceToken, map[string]interface{}{
+		"rating":  5,
+		"comment": "Indah sekali",
+		"photos":  []string{"https://cdn.example.com/pink.jpg"},
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var aliceReview response.ReviewResponse
+	json.Unmarshal(rec.Body.Bytes(), &aliceReview)
+	assert.Equal(t, "revieweralice", aliceReview.User.Username)
+	assert.Equal(t, []string{"https://cdn.example.com/pink.jpg"}, aliceReview.Photos)
+
+	rec = doJSON(e, http.MethodPost, reviews, aliceToken, map[string]interface{}{"rating": 4})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = doJSON(e, http.MethodPost, reviews, bobToken, map[string]interface{}{"rating": 6})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(e, http.MethodPost, reviews, bobToken, map[string]interface{}{"rating": 2, "comment": "Ramai"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var bobReview response.ReviewResponse
+	json.Unmarshal(rec.Body.Bytes(), &bobReview)
+
+	config.DB.First(&beach, beach.ID)
+	assert.Equal(t, 3.5, beach.AverageRating)
+	assert.Equal(t, 2, beach.ReviewCount)
+
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/review/%d", aliceReview.ID), bobToken, map[string]interface{}{"rating": 1})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/review/%d", bobReview.ID), bobToken, map[string]interface{}{"rating": 3, "comment": "Lumayan"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config.DB.First(&beach, beach.ID)
+	assert.Equal(t, 4.0, beach.AverageRating)
+
+	// Review yang disembunyikan tidak dihitung dan tidak tampil
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/review/%d/moderation", bobReview.ID), aliceToken, map[string]interface{}{"action": "hide"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/review/%d/moderation", bobReview.ID), adminToken, map[string]interface{}{"action": "hide"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = doJSON(e, http.MethodPut, fmt.Sprintf("/review/%d/moderation", aliceReview.ID), adminToken, map[string]interface{}{"action": "flag", "reason": "Cek foto"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	config.DB.First(&beach, beach.ID)
+	assert.Equal(t, 5.0, beach.AverageRating)
+	assert.Equal(t, 1, beach.ReviewCount)
+
+	var listed struct {
+		Reviews []response.ReviewResponse `json:"reviews"`
+	}
+	rec = doJSON(e, http.MethodGet, reviews, bobToken, nil)
+	json.Unmarshal(rec.Body.Bytes(), &listed)
+	if assert.Len(t, listed.Reviews, 1) {
+		assert.True(t, listed.Reviews[0].Flagged)
+		assert.Empty(t, listed.Reviews[0].FlagReason)
+	}
+
+	listed.Reviews = nil
+	rec = doJSON(e, http.MethodGet, "/review?flagged=true", adminToken, nil)
+	json.Unmarshal(rec.Body.Bytes(), &listed)
+	if assert.Len(t, listed.Reviews, 1) {
+		assert.Equal(t, "Cek foto", listed.Reviews[0].FlagReason)
+	}
+	rec = doJSON(e, http.MethodGet, "/review", bobToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Rating dipakai untuk filter dan urutan daftar destinasi
+	doJSON(e, http.MethodPost, fmt.Sprintf("/destination/%d/reviews", hill.ID), bobToken, map[string]interface{}{"rating": 4})
+
+	var destinations struct {
+		Destinations []response.DestinationResponse `json:"destinations"`
+	}
+	rec = doJSON(e, http.MethodGet, "/destination?sort=rating", bobToken, nil)
+	json.Unmarshal(rec.Body.Bytes(), &destinations)
+	if assert.Len(t, destinations.Destinations, 2) {
+		assert.Equal(t, "Pink Beach", destinations.Destinations[0].Name)
+		assert.Equal(t, 1, destinations.Destinations[0].ReviewCount)
+	}
+
+	destinations.Destinations = nil
+	rec = doJSON(e, http.MethodGet, "/destination?min_rating=4.5", bobToken, nil)
+	json.Unmarshal(rec.Body.Bytes(), &destinations)
+	assert.Len(t, destinations.Destinations, 1)
+
+	rec = doJSON(e, http.MethodDelete, fmt.Sprintf("/review/%d", aliceReview.ID), adminToken, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config.DB.First(&beach, beach.ID)
+	assert.Equal(t, 0.0, beach.AverageRating)
+	assert.Equal(t, 0, beach.ReviewCount)
+
+	var photos int64
+	config.DB.Model(&models.ReviewPhoto{}).Count(&photos)
+	assert.Zero(t, photos)
+}
@@ -0,0 +1,127 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/helper"
+	"backend/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// seedLine membuat kota asal/tujuan dan n destinasi berjajar ke timur
+func seedLine(n int) (models.City, models.City, []uint) {
+	origin := models.City{Name: "Origin", Latitude: float(-6.2), Longitude: float(106.0)}
+	end := models.City{Name: "End", Latitude: float(-6.2), Longitude: float(106.1 + float64(n)*0.1)}
+	config.DB.Create(&origin)
+	config.DB.Create(&end)
+
+	ids := make([]uint, n)
+	for i := 0; i < n; i++ {
+		destination := models.Destination{Name: "Stop", CityID: origin.ID, Latitude: float(-6.2), Longitude: float(106.1 + float64(i)*0.1)}
+		config.DB.Create(&destination)
+		ids[i] = destination.ID
+	}
+	return origin, end, ids
+}
+
+func reversed(ids []uint) []uint {
+	out := make([]uint, len(ids))
+	for i, id := range ids {
+		out[len(ids)-1-i] = id
+	}
+	return out
+}
+
+func TestCreateRouteOptimizesOrder(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "traveller")
+
+	_, _, ids := seedLine(4)
+	shuffled := []uint{ids[2], ids[0], ids[3], ids[1]}
+
+	rec := doJSON(e, http.MethodPost, "/route", token, map[string]interface{}{
+		"originCityName":      "Origin",
+		"destinationCityName": "End",
+		"destinations":        shuffled,
+		"distance":            1,
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var route models.Route
+	if err := json.Unmarshal(rec.Body.Bytes(), &route); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	// Asal 106.0 sampai tujuan 106.5 di latitude yang sama
+	expected := helper.Haversine(-6.2, 106.0, -6.2, 106.5)
+	assert.InDelta(t, expected, route.Distance, 0.05)
+
+	var stored []models.RouteDestination
+	config.DB.Where("route_id = ?", route.ID).Order("sequence").Find(&stored)
+	if assert.Len(t, stored, 4) {
+		for i, routeDestination := range stored {
+			assert.Equal(t, ids[i], routeDestination.DestinationID)
+			assert.Equal(t, i+1, routeDestination.Sequence)
+		}
+	}
+
+	rec = doJSON(e, http.MethodGet, "/route", token, nil)
+	var listed struct {
+		Data []struct {
+			Destinations []models.Destination `json:"destinations"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &listed)
+	if assert.Len(t, listed.Data, 1) && assert.Len(t, listed.Data[0].Destinations, 4) {
+		assert.Equal(t, ids[0], listed.Data[0].Destinations[0].ID)
+		assert.Equal(t, ids[3], listed.Data[0].Destinations[3].ID)
+	}
+}
+
+func TestOptimizeRouteHeuristicAndOpenEnd(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "planner")
+
+	// Lebih banyak dari batas eksak sehingga memakai nearest-neighbour + 2-opt
+	_, _, ids := seedLine(helper.ExactRouteLimit + 4)
+
+	rec := doJSON(e, http.MethodPost, "/route/optimize", token, map[string]interface{}{
+		"originCityName": "Origin",
+		"destinations":   reversed(ids),
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var plan struct {
+		DestinationCityName string  `json:"destinationCityName"`
+		Distance            float64 `json:"distance"`
+		Stops               []struct {
+			Sequence    int                `json:"sequence"`
+			Destination models.Destination `json:"destination"`
+		} `json:"stops"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &plan); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	assert.Equal(t, "Origin", plan.DestinationCityName)
+	if assert.Len(t, plan.Stops, len(ids)) {
+		for i, stop := range plan.Stops {
+			assert.Equal(t, ids[i], stop.Destination.ID)
+		}
+	}
+	last := 106.1 + float64(len(ids)-1)*0.1
+	assert.InDelta(t, helper.Haversine(-6.2, 106.0, -6.2, last), plan.Distance, 0.05)
+
+	var count int64
+	config.DB.Model(&models.Route{}).Count(&count)
+	assert.Zero(t, count)
+
+	rec = doJSON(e, http.MethodPost, "/route/optimize", token, map[string]interface{}{
+		"originCityName": "Origin",
+		"destinations":   []uint{9999},
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,207 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/media"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 adalah pengganti MinIO di memori yang memverifikasi tanda tangan SigV4
+type fakeS3 struct {
+	accessKey, secretKey, region string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+var authorizationPattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=([a-z0-9;-]+), Signature=([0-9a-f]{64})$`)
+
+func hmacHex(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func (f *fakeS3) verify(r *http.Request, body []byte) bool {
+	m := authorizationPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if m == nil || m[1] != f.accessKey || m[3] != f.region {
+		return false
+	}
+	sum := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		return false
+	}
+
+	var headers strings.Builder
+	for _, name := range strings.Split(m[4], ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		headers.WriteString(name + ":" + value + "\n")
+	}
+	canonical := strings.Join([]string{r.Method, r.URL.EscapedPath(), "", headers.String(), m[4], hex.EncodeToString(sum[:])}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	scope := m[2] + "/" + f.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + r.Header.Get("X-Amz-Date") + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacHex([]byte("AWS4"+f.secretKey), m[2])
+	for _, part := range []string{f.region, "s3", "aws4_request"} {
+		key = hmacHex(key, part)
+	}
+	return hmac.Equal([]byte(hex.EncodeToString(hmacHex(key, stringToSign))), []byte(m[5]))
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !f.verify(r, body) {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *storage.S3) {
+	fake := &fakeS3{accessKey: "minioadmin", secretKey: "minio-secret", region: "us-east-1", objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, &storage.S3{
+		Endpoint:  server.URL,
+		Region:    "us-east-1",
+		Bucket:    "tripwise",
+		AccessKey: "minioadmin",
+		SecretKey: "minio-secret",
+		PathStyle: true,
+	}
+}
+
+func TestStorageBackends(t *testing.T) {
+	_, s3 := newFakeS3(t)
+	backends := map[string]storage.Storage{
+		"local": storage.NewLocal(t.TempDir(), "http://localhost:8000/assets/"),
+		"s3":    s3,
+	}
+
+	for name, store := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := "uploads/ab/photo one.txt"
+
+			assert.NoError(t, store.Put(ctx, key, strings.NewReader("hello"), "text/plain"))
+
+			reader, err := store.Get(ctx, key)
+			if assert.NoError(t, err) {
+				data, _ := io.ReadAll(reader)
+				reader.Close()
+				assert.Equal(t, "hello", string(data))
+			}
+			assert.True(t, strings.HasSuffix(store.URL(key), "/uploads/ab/photo%20one.txt"), store.URL(key))
+
+			assert.NoError(t, store.Delete(ctx, key))
+			_, err = store.Get(ctx, key)
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+			assert.NoError(t, store.Delete(ctx, key), "deleting a missing key is not an error")
+
+			for _, bad := range []string{"", "/etc/passwd", "../secret", "uploads/../../secret", "uploads//x"} {
+				assert.ErrorIs(t, store.Put(ctx, bad, strings.NewReader("x"), ""), storage.ErrInvalidKey, bad)
+			}
+		})
+	}
+
+	assert.Equal(t, "http://localhost:8000/assets/uploads/a.png", backends["local"].URL("uploads/a.png"))
+	assert.Equal(t, s3.Endpoint+"/tripwise/uploads/a.png", s3.URL("uploads/a.png"))
+	s3.PublicURL = "https://cdn.tripwise.my.id/"
+	assert.Equal(t, "https://cdn.tripwise.my.id/uploads/a.png", s3.URL("uploads/a.png"))
+}
+
+func TestS3RejectsBadCredentials(t *testing.T) {
+	_, s3 := newFakeS3(t)
+	s3.SecretKey = "wrong"
+	s3.Now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	err := s3.Put(context.Background(), "uploads/a.txt", strings.NewReader("x"), "text/plain")
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestUploadToS3Storage(t *testing.T) {
+	e, outbox := newTestServer()
+	fake, s3 := newFakeS3(t)
+	s3.PublicURL = "https://cdn.tripwise.my.id"
+
+	previousStorage, previousUploads := config.Storage, config.Uploads
+	config.Storage = s3
+	config.Uploads = media.New(s3)
+	t.Cleanup(func() { config.Storage, config.Uploads = previousStorage, previousUploads })
+
+	adminToken := registerAdmin(t, e, outbox, "s3admin")
+	city := models.City{Name: "Malang"}
+	config.DB.Create(&city)
+	destination := models.Destination{Name: "Bromo", CityID: city.ID}
+	config.DB.Create(&destination)
+
+	rec := doMultipart(e, http.MethodPost, fmt.Sprintf("/destination/%d/images", destination.ID), adminToken, nil,
+		formFile{"images", "bromo.png", pngBytes(t, color.White)})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var result struct {
+		Files []media.File `json:"files"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &result)
+	if assert.Len(t, result.Files, 1) {
+		file := result.Files[0]
+		assert.Equal(t, "https://cdn.tripwise.my.id/"+file.Key, file.URL)
+		assert.Equal(t, "image/png", fake.types["/tripwise/"+file.Key])
+		assert.Contains(t, fake.objects, "/tripwise/"+file.Key)
+	}
+
+	// URL di response destinasi dibangun lewat Storage dari key yang tersimpan
+	var detail struct {
+		Destination struct {
+			Images []struct {
+				URL string `json:"url"`
+			} `json:"images"`
+		} `json:"destination"`
+	}
+	rec = doJSON(e, http.MethodGet, fmt.Sprintf("/destination/%d", destination.ID), adminToken, nil)
+	json.Unmarshal(rec.Body.Bytes(), &detail)
+	if assert.Len(t, detail.Destination.Images, 1) && len(result.Files) == 1 {
+		assert.Equal(t, result.Files[0].URL, detail.Destination.Images[0].URL)
+	}
+}
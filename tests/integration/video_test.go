@@ -0,0 +1,131 @@
+package controllers_test
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/response"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVideoViewsAndRanking(t *testing.T) {
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "viewer")
+	otherToken, _ := registerUser(t, e, outbox, "viewer2")
+
+	city := models.City{Name: "Toba"}
+	config.DB.Create(&city)
+	lake := models.Destination{Name: "Danau Toba", CityID: city.ID}
+	village := models.Destination{Name: "Tomok", CityID: city.ID}
+	config.DB.Create(&lake)
+	config.DB.Create(&village)
+	lakeVideo := models.VideoContent{DestinationID: lake.ID, Title: "Sunrise", URL: "https://video.example.com/1"}
+	villageVideo := models.VideoContent{DestinationID: village.ID, Title: "Tarian", URL: "https://video.example.com/2"}
+	config.DB.Create(&lakeVideo)
+	config.DB.Create(&villageVideo)
+
+	view := func(token string, video models.VideoContent) bool {
+		rec := doJSON(e, http.MethodPost, fmt.Sprintf("/video-content/%d/view", video.ID), token, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var result struct {
+			Counted bool `json:"counted"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		return result.Counted
+	}
+
+	assert.True(t, view(token, villageVideo))
+	assert.False(t, view(token, villageVideo), "repeat view inside the window is deduplicated")
+	assert.True(t, view(otherToken, villageVideo))
+	assert.True(t, view(token, lakeVideo))
+
+	rec := doJSON(e, http.MethodPost, "/video-content/999/view", token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doJSON(e, http.MethodPost, fmt.Sprintf("/video-content/%d/view", lakeVideo.ID), "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Tiga view lama di luar jendela 7 hari
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	for i := 0; i < 3; i++ {
+		config.DB.Create(&models.VideoContentView{VideoContentID: lakeVideo.ID, UserID: uint(100 + i), DestinationID: lake.ID, CreatedAt: old})
+	}
+
+	type ranking struct {
+		Data struct {
+			Window       string                            `json:"window"`
+			Videos       []response.VideoViewRanking       `json:"videos"`
+			Destinations []response.DestinationViewRanking `json:"destinations"`
+		} `json:"data"`
+	}
+
+	var weekly ranking
+	rec = doJSON(e, http.MethodGet, "/video-content/most?window=7d", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &weekly)
+	if assert.Len(t, weekly.Data.Videos, 2) {
+		assert.Equal(t, "Tarian", weekly.Data.Videos[0].Title)
+		assert.Equal(t, int64(2), weekly.Data.Videos[0].ViewCount)
+		assert.Equal(t, "Tomok", weekly.Data.Videos[0].DestinationName)
+	}
+	if assert.Len(t, weekly.Data.Destinations, 2) {
+		assert.Equal(t, "Tomok", weekly.Data.Destinations[0].Name)
+		assert.Len(t, weekly.Data.Destinations[0].Videos, 1)
+	}
+
+	var allTime ranking
+	rec = doJSON(e, http.MethodGet, "/video-content/most", "", nil)
+	json.Unmarshal(rec.Body.Bytes(), &allTime)
+	assert.Equal(t, "all", allTime.Data.Window)
+	if assert.NotEmpty(t, allTime.Data.Destinations) {
+		assert.Equal(t, "Danau Toba", allTime.Data.Destinations[0].Name)
+		assert.Equal(t, int64(4), allTime.Data.Destinations[0].ViewCount)
+	}
+
+	rec = doJSON(e, http.MethodGet, "/video-content/most?window=forever", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestConcurrentVideoViewsCountedOnce(t *testing.T) {
+	// Jendela yang sangat panjang supaya semua request jatuh di jendela yang sama
+	t.Setenv("VIDEO_VIEW_DEDUP_WINDOW", "876000h")
+	e, outbox := newTestServer()
+	token, _ := registerUser(t, e, outbox, "eagerviewer")
+
+	city := models.City{Name: "Bukittinggi"}
+	config.DB.Create(&city)
+	destination := models.Destination{Name: "Jam Gadang", CityID: city.ID}
+	config.DB.Create(&destination)
+	video := models.VideoContent{DestinationID: destination.ID, Title: "Senja", URL: "https://video.example.com/3"}
+	config.DB.Create(&video)
+
+	var wg sync.WaitGroup
+	var counted atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := doJSON(e, http.MethodPost, fmt.Sprintf("/video-content/%d/view", video.ID), token, nil)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var result struct {
+				Counted bool `json:"counted"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &result)
+			if result.Counted {
+				counted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 1, counted.Load())
+	var views int64
+	config.DB.Model(&models.VideoContentView{}).Where("video_content_id = ?", video.ID).Count(&views)
+	assert.EqualValues(t, 1, views)
+}